@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"admin-dashboard/internal/config"
 	"admin-dashboard/internal/handlers"
@@ -56,26 +58,110 @@ func main() {
 	roleRepo := repository.NewRoleRepository(db.DB)
 	divisionRepo := repository.NewDivisionRepository(db.DB)
 	positionRepo := repository.NewPositionRepository(db.DB)
+	sessionRepo := repository.NewSessionRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	accessReviewRepo := repository.NewAccessReviewRepository(db.DB)
+	changeRequestRepo := repository.NewChangeRequestRepository(db.DB)
+	sodRepo := repository.NewSoDRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	reportRepo := repository.NewReportRepository(db.DB)
+	reportScheduleRepo := repository.NewReportScheduleRepository(db.DB)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db.DB)
+	documentRepo := repository.NewDocumentRepository(db.DB)
+	customFieldRepo := repository.NewCustomFieldRepository(db.DB)
+	checklistRepo := repository.NewChecklistRepository(db.DB)
+	offboardingRepo := repository.NewOffboardingRepository(db.DB)
+	scheduledChangeRepo := repository.NewScheduledChangeRepository(db.DB)
+	idempotencyRepo := repository.NewIdempotencyRepository(db.DB)
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, roleRepo, jwtManager)
-	userService := services.NewUserService(userRepo, roleRepo, divisionRepo, positionRepo)
-	roleService := services.NewRoleService(roleRepo)
-	divisionService := services.NewDivisionService(divisionRepo)
-	positionService := services.NewPositionService(positionRepo)
-	dashboardService := services.NewDashboardService(db.DB)
+	authService := services.NewAuthService(userRepo, roleRepo, sessionRepo, jwtManager)
+	userService := services.NewUserService(userRepo, roleRepo, divisionRepo, positionRepo, outboxRepo, customFieldRepo)
+	roleService := services.NewRoleService(roleRepo, outboxRepo)
+	divisionService := services.NewDivisionService(divisionRepo, outboxRepo, customFieldRepo)
+	positionService := services.NewPositionService(positionRepo, outboxRepo, customFieldRepo)
+	statsCache := services.NewMemoryStatsCache(time.Duration(cfg.CacheConfig.StatsTTL) * time.Second)
+	dashboardService := services.NewDashboardService(db.DB, statsCache, &cfg.AuthConfig, &cfg.CalendarConfig)
+	scimService := services.NewSCIMService(userRepo, roleRepo, outboxRepo, userService, roleService)
+	sessionService := services.NewSessionService(sessionRepo)
+	impersonationService := services.NewImpersonationService(userRepo, roleRepo, sessionRepo, jwtManager, &cfg.AuthConfig)
+	auditService := services.NewAuditService(auditRepo)
+	accessReviewService := services.NewAccessReviewService(accessReviewRepo, roleRepo, divisionRepo, outboxRepo, userService, &cfg.AuthConfig)
+	approvalService := services.NewApprovalService(changeRequestRepo, userRepo, roleRepo, userService, &cfg.ApprovalConfig, &cfg.AuthConfig)
+	sodService := services.NewSoDService(sodRepo, roleRepo, userRepo)
+	webhookService := services.NewWebhookService(webhookRepo)
+	reportService := services.NewReportService(reportRepo)
+	mailSender := services.NewMailSender(&cfg.MailConfig)
+	reportScheduleService := services.NewReportScheduleService(reportScheduleRepo, divisionRepo, userRepo, dashboardService, mailSender)
+	calendarService := services.NewCalendarService(calendarFeedRepo, userRepo, dashboardService)
+	blobStore, err := services.NewBlobStore(&cfg.StorageConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob storage: %v", err)
+	}
+	avatarService := services.NewAvatarService(userRepo, userService, blobStore, &cfg.AuthConfig, &cfg.StorageConfig)
+	customFieldService := services.NewCustomFieldService(customFieldRepo)
+	documentService := services.NewDocumentService(documentRepo, userRepo, blobStore, &cfg.AuthConfig, &cfg.DocumentConfig)
+	checklistService := services.NewChecklistService(checklistRepo, userRepo, roleRepo, divisionRepo, positionRepo, outboxRepo, &cfg.AuthConfig)
+	offboardingService := services.NewOffboardingService(offboardingRepo, userRepo, userService, outboxRepo)
+	scheduledChangeService := services.NewScheduledChangeService(scheduledChangeRepo, userService, approvalService, outboxRepo)
+	userBatchService := services.NewUserBatchService(userRepo, roleRepo, customFieldRepo, outboxRepo, userService, approvalService)
+
+	// Subscribe in-process handlers to domain events dispatched from the outbox
+	eventBus := services.NewEventBus()
+	eventBus.Subscribe("webhooks", webhookService.HandleEvent)
+	eventBus.Subscribe("stats-cache", dashboardService.HandleEvent)
+	eventBus.Subscribe("documents", documentService.HandleEvent)
+	eventBus.Subscribe("checklists", checklistService.HandleEvent)
+	dashboardStream := services.NewDashboardStream(dashboardService, outboxRepo, cfg.DBConfig.DSN, time.Duration(cfg.StreamConfig.HeartbeatInterval)*time.Second)
+	eventBus.Subscribe("dashboard-stream", dashboardStream.HandleEvent)
+
+	// Start background workers
+	roleGrantSweeper := services.NewRoleGrantSweeper(roleRepo, sessionRepo, userService, time.Duration(cfg.WorkerConfig.RoleSweepInterval)*time.Second)
+	go roleGrantSweeper.Run(context.Background())
+	outboxDispatcher := services.NewOutboxDispatcher(outboxRepo, eventBus, time.Duration(cfg.WorkerConfig.OutboxPollInterval)*time.Second)
+	go outboxDispatcher.Run(context.Background())
+	webhookDeliverer := services.NewWebhookDeliverer(webhookRepo, &cfg.WebhookConfig)
+	go webhookDeliverer.Run(context.Background())
+	go dashboardStream.Run(context.Background())
+	scheduler := services.NewScheduler(db.DB, time.Duration(cfg.WorkerConfig.SchedulerPollInterval)*time.Second)
+	scheduler.Register("reports", reportScheduleService.RunDue)
+	scheduler.Register("offboardings", offboardingService.RunDue)
+	scheduler.Register("scheduled-changes", scheduledChangeService.RunDue)
+	scheduler.Register("idempotency-keys", idempotencyRepo.DeleteExpired)
+	go scheduler.Run(context.Background())
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	userHandler := handlers.NewUserHandler(userService)
+	authHandler := handlers.NewAuthHandler(authService, sessionService)
+	userHandler := handlers.NewUserHandler(userService, sessionService, approvalService)
 	roleHandler := handlers.NewRoleHandler(roleService)
 	divisionHandler := handlers.NewDivisionHandler(divisionService)
 	positionHandler := handlers.NewPositionHandler(positionService)
-	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService, dashboardStream)
+	scimHandler := handlers.NewSCIMHandler(scimService)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	accessReviewHandler := handlers.NewAccessReviewHandler(accessReviewService)
+	changeRequestHandler := handlers.NewChangeRequestHandler(approvalService)
+	sodHandler := handlers.NewSoDHandler(sodService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	reportScheduleHandler := handlers.NewReportScheduleHandler(reportScheduleService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	avatarHandler := handlers.NewAvatarHandler(avatarService)
+	documentHandler := handlers.NewDocumentHandler(documentService)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService)
+	checklistHandler := handlers.NewChecklistHandler(checklistService)
+	offboardingHandler := handlers.NewOffboardingHandler(offboardingService)
+	scheduledChangeHandler := handlers.NewScheduledChangeHandler(scheduledChangeService)
+	userBatchHandler := handlers.NewUserBatchHandler(userBatchService)
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager, sessionRepo)
 	authenticate := authMiddleware.Authenticate()
+	requireAdmin := authMiddleware.RequireRole(cfg.AuthConfig.AdminRoles...)
+	scimAuthenticate := middleware.SCIMAuth(cfg.SCIMConfig.Token)
+	requireIfMatch := middleware.RequireIfMatch(cfg.Server.RequireIfMatch)
 
 	// Set up Gin router
 	log.Println("Setting up HTTP router...")
@@ -88,6 +174,8 @@ func main() {
 	router.Use(middleware.CORS())
 	router.Use(middleware.Logger())
 	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.Audit(auditRepo))
+	router.Use(middleware.Idempotency(idempotencyRepo, jwtManager, time.Duration(cfg.IdempotencyConfig.TTL)*time.Hour, int64(cfg.IdempotencyConfig.MaxBodySize)))
 	
 	// Set trusted proxies for Railway
 	router.SetTrustedProxies(nil) // Trust all proxies in Railway
@@ -105,13 +193,33 @@ func main() {
 		authHandler.RegisterRoutes(api, &authenticate)
 
 		// Protected routes (authentication required)
-		userHandler.RegisterRoutes(api, &authenticate)
-		roleHandler.RegisterRoutes(api, &authenticate)
-		divisionHandler.RegisterRoutes(api, &authenticate)
-		positionHandler.RegisterRoutes(api, &authenticate)
+		userHandler.RegisterRoutes(api, &authenticate, &requireAdmin, &requireIfMatch)
+		roleHandler.RegisterRoutes(api, &authenticate, &requireIfMatch)
+		divisionHandler.RegisterRoutes(api, &authenticate, &requireIfMatch)
+		positionHandler.RegisterRoutes(api, &authenticate, &requireIfMatch)
 		dashboardHandler.RegisterRoutes(api, &authenticate)
+		impersonationHandler.RegisterRoutes(api, &authenticate)
+		auditHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		accessReviewHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		changeRequestHandler.RegisterRoutes(api, &authenticate)
+		sodHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		webhookHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		reportHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		reportScheduleHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		calendarHandler.RegisterRoutes(api, &authenticate)
+		avatarHandler.RegisterRoutes(api, &authenticate)
+		documentHandler.RegisterRoutes(api, &authenticate)
+		customFieldHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		checklistHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		offboardingHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		scheduledChangeHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
+		userBatchHandler.RegisterRoutes(api, &authenticate, &requireAdmin)
 	}
 
+	// SCIM provisioning routes (identity provider bearer token required)
+	scim := router.Group("/scim/v2")
+	scimHandler.RegisterRoutes(scim, &scimAuthenticate)
+
 	// Get port from environment with fallback
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -4,15 +4,28 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for our application
 type Config struct {
-	DBConfig  DBConfig
-	JWTConfig JWTConfig
-	Server    ServerConfig
+	DBConfig          DBConfig
+	JWTConfig         JWTConfig
+	Server            ServerConfig
+	SCIMConfig        SCIMConfig
+	AuthConfig        AuthConfig
+	WorkerConfig      WorkerConfig
+	ApprovalConfig    ApprovalConfig
+	WebhookConfig     WebhookConfig
+	StreamConfig      StreamConfig
+	CacheConfig       CacheConfig
+	MailConfig        MailConfig
+	CalendarConfig    CalendarConfig
+	StorageConfig     StorageConfig
+	DocumentConfig    DocumentConfig
+	IdempotencyConfig IdempotencyConfig
 }
 
 // DBConfig holds database related configuration
@@ -35,8 +48,101 @@ type JWTConfig struct {
 
 // ServerConfig holds server related configuration
 type ServerConfig struct {
-	Host string
-	Port string
+	Host           string
+	Port           string
+	RequireIfMatch bool // reject updates of versioned resources without an If-Match header
+}
+
+// SCIMConfig holds SCIM provisioning related configuration
+type SCIMConfig struct {
+	Token string // bearer token used by the identity provider
+}
+
+// AuthConfig holds authorization related configuration
+type AuthConfig struct {
+	AdminRoles []string // role names allowed to use admin-only endpoints
+
+	ImpersonationRoles    []string // role names allowed to impersonate users
+	ImpersonationMinLevel int      // minimum role level allowed to impersonate users, 0 disables
+	ImpersonationTTL      int      // in minutes
+}
+
+// WorkerConfig holds background worker related configuration
+type WorkerConfig struct {
+	RoleSweepInterval     int // in seconds
+	OutboxPollInterval    int // in seconds
+	SchedulerPollInterval int // in seconds
+}
+
+// ApprovalConfig holds configuration for changes that need approval before they apply
+type ApprovalConfig struct {
+	RoleLevel      int    // granting a role at or above this level needs approval, 0 disables
+	Deactivation   bool   // deactivating a user needs approval
+	DivisionChange bool   // moving a user to another division needs approval
+	ApproverRule   string // "manager" (the target's manager) or "role" (any holder of ApproverRole)
+	ApproverRole   string // role name used by the "role" rule
+}
+
+// WebhookConfig holds webhook delivery related configuration
+type WebhookConfig struct {
+	MaxAttempts  int // attempts before a delivery is dead-lettered
+	BackoffBase  int // in seconds, doubled after every failed attempt
+	Timeout      int // in seconds, per request
+	PollInterval int // in seconds
+	// AllowPrivateTargets permits deliveries to loopback, private and link-local addresses,
+	// for local development only
+	AllowPrivateTargets bool
+}
+
+// StreamConfig holds server-sent event stream related configuration
+type StreamConfig struct {
+	HeartbeatInterval int // in seconds
+}
+
+// CacheConfig holds cache related configuration
+type CacheConfig struct {
+	StatsTTL int // dashboard statistics cache lifetime in seconds, 0 disables
+}
+
+// IdempotencyConfig holds configuration for requests made with an Idempotency-Key header
+type IdempotencyConfig struct {
+	TTL         int // how long responses are kept for replay, in hours
+	MaxBodySize int // largest request body buffered for a key in bytes; larger requests get 413
+}
+
+// MailConfig holds outgoing mail related configuration
+type MailConfig struct {
+	SMTPHost     string // empty logs mails instead of sending them
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+// CalendarConfig holds dashboard calendar related configuration
+type CalendarConfig struct {
+	ProbationMonths  int   // probation ends this many months after the join date, 0 disables
+	AnniversaryYears []int // work anniversaries shown on the calendar
+}
+
+// StorageConfig holds blob storage related configuration
+type StorageConfig struct {
+	Driver        string // "local" or "s3"
+	LocalDir      string // directory used by the local driver
+	S3Endpoint    string // e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000 for MinIO
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PathStyle   bool // address the bucket in the path instead of the host name, as MinIO needs
+	AvatarMaxSize int  // largest accepted avatar upload in bytes
+}
+
+// DocumentConfig holds user document related configuration
+type DocumentConfig struct {
+	MaxSize         int      // largest accepted upload in bytes
+	HRRoles         []string // role names that manage documents and read confidential ones
+	RestrictedRoles []string // role names that read restricted documents, the admin roles when empty
 }
 
 // LoadConfig loads configuration from environment variables
@@ -74,14 +180,194 @@ func LoadConfig() (*Config, error) {
 
 	// Server config
 	serverConfig := ServerConfig{
-		Host: getEnv("SERVER_HOST", "0.0.0.0"),
-		Port: getEnv("SERVER_PORT", "3000"),
+		Host:           getEnv("SERVER_HOST", "0.0.0.0"),
+		Port:           getEnv("SERVER_PORT", "3000"),
+		RequireIfMatch: getEnv("REQUIRE_IF_MATCH", "false") == "true",
+	}
+
+	// SCIM config
+	scimConfig := SCIMConfig{
+		Token: getEnv("SCIM_TOKEN", ""),
+	}
+
+	// Auth config
+	impersonationMinLevel, err := strconv.Atoi(getEnv("IMPERSONATION_MIN_LEVEL", "0"))
+	if err != nil {
+		impersonationMinLevel = 0 // Default to role-based permission only
+	}
+	impersonationTTL, err := strconv.Atoi(getEnv("IMPERSONATION_TTL", "30"))
+	if err != nil {
+		impersonationTTL = 30 // Default to 30 minutes
+	}
+	authConfig := AuthConfig{
+		AdminRoles:            splitList(getEnv("ADMIN_ROLES", "admin")),
+		ImpersonationRoles:    splitList(getEnv("IMPERSONATION_ROLES", "")),
+		ImpersonationMinLevel: impersonationMinLevel,
+		ImpersonationTTL:      impersonationTTL,
+	}
+
+	// Worker config
+	roleSweepInterval, err := strconv.Atoi(getEnv("ROLE_SWEEP_INTERVAL", "60"))
+	if err != nil {
+		roleSweepInterval = 60 // Default to every minute
+	}
+	outboxPollInterval, err := strconv.Atoi(getEnv("OUTBOX_POLL_INTERVAL", "2"))
+	if err != nil {
+		outboxPollInterval = 2 // Default to every 2 seconds
+	}
+	schedulerPollInterval, err := strconv.Atoi(getEnv("SCHEDULER_POLL_INTERVAL", "30"))
+	if err != nil {
+		schedulerPollInterval = 30 // Default to every 30 seconds
+	}
+	workerConfig := WorkerConfig{
+		RoleSweepInterval:     roleSweepInterval,
+		OutboxPollInterval:    outboxPollInterval,
+		SchedulerPollInterval: schedulerPollInterval,
+	}
+
+	// Approval config
+	approvalRoleLevel, err := strconv.Atoi(getEnv("APPROVAL_ROLE_LEVEL", "0"))
+	if err != nil {
+		approvalRoleLevel = 0 // Default to no approval for role grants
+	}
+	approvalConfig := ApprovalConfig{
+		RoleLevel:      approvalRoleLevel,
+		Deactivation:   getEnv("APPROVAL_DEACTIVATION", "false") == "true",
+		DivisionChange: getEnv("APPROVAL_DIVISION_CHANGE", "false") == "true",
+		ApproverRule:   getEnv("APPROVER_RULE", "manager"),
+		ApproverRole:   getEnv("APPROVER_ROLE", ""),
+	}
+
+	// Webhook config
+	webhookMaxAttempts, err := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	if err != nil {
+		webhookMaxAttempts = 8
+	}
+	webhookBackoffBase, err := strconv.Atoi(getEnv("WEBHOOK_BACKOFF_BASE", "30"))
+	if err != nil {
+		webhookBackoffBase = 30 // Default to 30 seconds
+	}
+	webhookTimeout, err := strconv.Atoi(getEnv("WEBHOOK_TIMEOUT", "10"))
+	if err != nil {
+		webhookTimeout = 10 // Default to 10 seconds
+	}
+	webhookPollInterval, err := strconv.Atoi(getEnv("WEBHOOK_POLL_INTERVAL", "5"))
+	if err != nil {
+		webhookPollInterval = 5 // Default to every 5 seconds
+	}
+	webhookConfig := WebhookConfig{
+		MaxAttempts:  webhookMaxAttempts,
+		BackoffBase:  webhookBackoffBase,
+		Timeout:      webhookTimeout,
+		PollInterval: webhookPollInterval,
+
+		AllowPrivateTargets: getEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false") == "true",
+	}
+
+	// Stream config
+	streamHeartbeat, err := strconv.Atoi(getEnv("STREAM_HEARTBEAT_INTERVAL", "15"))
+	if err != nil || streamHeartbeat < 1 {
+		streamHeartbeat = 15 // Default to every 15 seconds
+	}
+	streamConfig := StreamConfig{
+		HeartbeatInterval: streamHeartbeat,
+	}
+
+	// Cache config
+	statsCacheTTL, err := strconv.Atoi(getEnv("STATS_CACHE_TTL", "60"))
+	if err != nil {
+		statsCacheTTL = 60 // Default to one minute
+	}
+	cacheConfig := CacheConfig{
+		StatsTTL: statsCacheTTL,
+	}
+
+	// Mail config
+	mailConfig := MailConfig{
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		From:         getEnv("MAIL_FROM", "noreply@example.com"),
+	}
+
+	// Calendar config
+	probationMonths, err := strconv.Atoi(getEnv("PROBATION_MONTHS", "3"))
+	if err != nil {
+		probationMonths = 3 // Default to three months
+	}
+	var anniversaryYears []int
+	for _, item := range splitList(getEnv("CALENDAR_ANNIVERSARY_YEARS", "1,5,10")) {
+		if years, err := strconv.Atoi(item); err == nil && years > 0 {
+			anniversaryYears = append(anniversaryYears, years)
+		}
+	}
+	calendarConfig := CalendarConfig{
+		ProbationMonths:  probationMonths,
+		AnniversaryYears: anniversaryYears,
+	}
+
+	// Storage config
+	avatarMaxSize, err := strconv.Atoi(getEnv("AVATAR_MAX_SIZE", "5242880"))
+	if err != nil {
+		avatarMaxSize = 5 << 20 // Default to 5 MB
+	}
+	storageConfig := StorageConfig{
+		Driver:        getEnv("STORAGE_DRIVER", "local"),
+		LocalDir:      getEnv("STORAGE_LOCAL_DIR", "./data/blobs"),
+		S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:      getEnv("S3_BUCKET", ""),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:   getEnv("S3_PATH_STYLE", "false") == "true",
+		AvatarMaxSize: avatarMaxSize,
+	}
+
+	// Document config
+	documentMaxSize, err := strconv.Atoi(getEnv("DOCUMENT_MAX_SIZE", "20971520"))
+	if err != nil {
+		documentMaxSize = 20 << 20 // Default to 20 MB
+	}
+	documentConfig := DocumentConfig{
+		MaxSize:         documentMaxSize,
+		HRRoles:         splitList(getEnv("DOCUMENT_HR_ROLES", "hr")),
+		RestrictedRoles: splitList(getEnv("DOCUMENT_RESTRICTED_ROLES", "")),
+	}
+	if len(documentConfig.RestrictedRoles) == 0 {
+		documentConfig.RestrictedRoles = authConfig.AdminRoles
+	}
+
+	// Idempotency config
+	idempotencyTTL, err := strconv.Atoi(getEnv("IDEMPOTENCY_KEY_TTL", "24"))
+	if err != nil || idempotencyTTL < 1 {
+		idempotencyTTL = 24 // Default to one day
+	}
+	idempotencyMaxBodySize, err := strconv.Atoi(getEnv("IDEMPOTENCY_MAX_BODY_SIZE", "1048576"))
+	if err != nil || idempotencyMaxBodySize < 1 {
+		idempotencyMaxBodySize = 1 << 20 // Default to 1 MB
+	}
+	idempotencyConfig := IdempotencyConfig{
+		TTL:         idempotencyTTL,
+		MaxBodySize: idempotencyMaxBodySize,
 	}
 
 	config := &Config{
-		DBConfig:  dbConfig,
-		JWTConfig: jwtConfig,
-		Server:    serverConfig,
+		DBConfig:          dbConfig,
+		JWTConfig:         jwtConfig,
+		Server:            serverConfig,
+		SCIMConfig:        scimConfig,
+		AuthConfig:        authConfig,
+		WorkerConfig:      workerConfig,
+		ApprovalConfig:    approvalConfig,
+		WebhookConfig:     webhookConfig,
+		StreamConfig:      streamConfig,
+		CacheConfig:       cacheConfig,
+		MailConfig:        mailConfig,
+		CalendarConfig:    calendarConfig,
+		StorageConfig:     storageConfig,
+		DocumentConfig:    documentConfig,
+		IdempotencyConfig: idempotencyConfig,
 	}
 
 	if os.Getenv("RAILWAY_ENVIRONMENT") == "production" {
@@ -98,4 +384,15 @@ func getEnv(key, defaultValue string) string {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// Helper function to split a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -67,11 +67,44 @@ func (d *Database) Migrate() error {
 		&models.Role{},
 		&models.User{},
 		&models.UserRole{},
+		&models.RoleGrantExpiration{},
+		&models.UserSession{},
+		&models.LoginEvent{},
+		&models.AuditLog{},
+		&models.AccessReviewCampaign{},
+		&models.AccessReviewItem{},
+		&models.ChangeRequest{},
+		&models.ChangeRequestComment{},
+		&models.SoDRule{},
+		&models.SoDRuleRole{},
+		&models.SoDException{},
+		&models.OutboxEvent{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.ReportDefinition{},
+		&models.ReportSchedule{},
+		&models.CalendarFeed{},
+		&models.UserDocument{},
+		&models.CustomFieldDefinition{},
+		&models.ChecklistTemplate{},
+		&models.ChecklistTemplateTask{},
+		&models.Checklist{},
+		&models.ChecklistTask{},
+		&models.Offboarding{},
+		&models.ScheduledChange{},
+		&models.IdempotencyKey{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to automigrate: %w", err)
 	}
 
+	// Backfill deactivation dates of users deactivated before they were recorded
+	err = d.DB.Exec(`UPDATE "user".users SET u_deactivated_at = u_updated_at WHERE u_is_active = false AND u_deactivated_at IS NULL`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill deactivation dates: %w", err)
+	}
+
 	log.Println("Database migrations completed successfully!")
 	return nil
 }
\ No newline at end of file
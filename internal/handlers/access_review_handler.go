@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AccessReviewHandler handles access review HTTP requests
+type AccessReviewHandler struct {
+	accessReviewService *services.AccessReviewService
+}
+
+// NewAccessReviewHandler creates a new access review handler
+func NewAccessReviewHandler(accessReviewService *services.AccessReviewService) *AccessReviewHandler {
+	return &AccessReviewHandler{
+		accessReviewService: accessReviewService,
+	}
+}
+
+// CreateCampaign creates a new access review campaign
+// @Summary Create an access review campaign
+// @Description Start a campaign that asks managers to recertify their reports' roles, scoped to a role, a division or both (admin only)
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param campaign body models.AccessReviewCampaignRequest true "Campaign details"
+// @Success 201 {object} models.AccessReviewCampaignResponse "Created campaign"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /access-reviews [post]
+func (h *AccessReviewHandler) CreateCampaign(c *gin.Context) {
+	var request models.AccessReviewCampaignRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create campaign
+	campaign, err := h.accessReviewService.CreateCampaign(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
+// ListCampaigns lists access review campaigns
+// @Summary List access review campaigns
+// @Description List access review campaigns with pagination (admin only)
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Page size (default: 10)"
+// @Param status query string false "Filter by status (open, closed)"
+// @Success 200 {object} models.PaginatedResponse "List of campaigns"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /access-reviews [get]
+func (h *AccessReviewHandler) ListCampaigns(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status")
+
+	// Get campaigns
+	campaigns, err := h.accessReviewService.ListCampaigns(page, limit, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaigns)
+}
+
+// GetCampaign gets an access review campaign by ID
+// @Summary Get an access review campaign
+// @Description Get a campaign with its review progress (admin only)
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} models.AccessReviewCampaignResponse "Campaign details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Campaign not found"
+// @Router /access-reviews/{id} [get]
+func (h *AccessReviewHandler) GetCampaign(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	// Get campaign
+	campaign, err := h.accessReviewService.GetCampaign(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaign)
+}
+
+// ListItems lists the items of an access review campaign
+// @Summary List campaign items
+// @Description List the user-role pairs under review in a campaign (admin only)
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Campaign ID"
+// @Param decision query string false "Filter by decision (pending, approved, revoked)"
+// @Param reviewer_id query int false "Filter by reviewer"
+// @Success 200 {array} models.AccessReviewItem "List of items"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Campaign not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /access-reviews/{id}/items [get]
+func (h *AccessReviewHandler) ListItems(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+	reviewerID, _ := strconv.ParseUint(c.Query("reviewer_id"), 10, 32)
+
+	// Get items
+	items, err := h.accessReviewService.ListItems(uint(id), c.Query("decision"), uint(reviewerID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// CloseCampaign closes an access review campaign
+// @Summary Close an access review campaign
+// @Description Close a campaign and remove every role whose review was revoked (admin only)
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} models.AccessReviewCampaignResponse "Closed campaign"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Campaign not found"
+// @Router /access-reviews/{id}/close [post]
+func (h *AccessReviewHandler) CloseCampaign(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	// Get closer ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Close campaign
+	campaign, err := h.accessReviewService.CloseCampaign(uint(id), employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaign)
+}
+
+// Report exports an access review campaign as CSV
+// @Summary Export an access review campaign
+// @Description Download every decision of a campaign as CSV for auditors (admin only)
+// @Tags access-reviews
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path int true "Campaign ID"
+// @Success 200 {file} file "CSV report"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Campaign not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /access-reviews/{id}/report [get]
+func (h *AccessReviewHandler) Report(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	// Build report
+	campaign, report, err := h.accessReviewService.Report(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"access-review-%d.csv\"", campaign.ID))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", report)
+}
+
+// MyItems lists the pending items assigned to the current user
+// @Summary List my pending reviews
+// @Description List the pending items of open campaigns where the current user is the reviewer
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.AccessReviewItem "List of items"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /access-reviews/my-items [get]
+func (h *AccessReviewHandler) MyItems(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Get items
+	items, err := h.accessReviewService.ListReviewerItems(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// Decide records a decision on an access review item
+// @Summary Decide on an access review item
+// @Description Approve or revoke a user's role; only the assigned reviewer or an admin may decide
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param itemId path int true "Item ID"
+// @Param decision body models.AccessReviewDecisionRequest true "Decision"
+// @Success 200 {object} models.AccessReviewItem "Updated item"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Not the reviewer"
+// @Failure 404 {object} map[string]string "Item not found"
+// @Router /access-reviews/items/{itemId}/decision [post]
+func (h *AccessReviewHandler) Decide(c *gin.Context) {
+	// Parse ID from URL
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var request models.AccessReviewDecisionRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get reviewer from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	employeeID, _ := c.Get("employeeID")
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	// Record decision
+	item, err := h.accessReviewService.Decide(uint(itemID), &request, userID.(uint), roleNames, employeeID.(string))
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// RegisterRoutes registers the access review routes
+func (h *AccessReviewHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	reviewGroup := router.Group("/access-reviews")
+	reviewGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		reviewGroup.GET("/my-items", h.MyItems)
+		reviewGroup.POST("/items/:itemId/decision", h.Decide)
+		reviewGroup.POST("", *adminMiddleware, h.CreateCampaign)
+		reviewGroup.GET("", *adminMiddleware, h.ListCampaigns)
+		reviewGroup.GET("/:id", *adminMiddleware, h.GetCampaign)
+		reviewGroup.GET("/:id/items", *adminMiddleware, h.ListItems)
+		reviewGroup.POST("/:id/close", *adminMiddleware, h.CloseCampaign)
+		reviewGroup.GET("/:id/report", *adminMiddleware, h.Report)
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List lists audit log entries
+// @Summary List audit log entries
+// @Description List audited requests with pagination (admin only)
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Page size (default: 10)"
+// @Param user_id query int false "Only entries made by or on behalf of this user"
+// @Param impersonated query bool false "Only entries made while impersonating"
+// @Success 200 {object} models.PaginatedResponse "List of audit log entries"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	impersonated, _ := strconv.ParseBool(c.Query("impersonated"))
+
+	// Get audit log entries
+	entries, err := h.auditService.List(page, limit, uint(userID), impersonated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// RegisterRoutes registers the audit routes
+func (h *AuditHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	auditGroup := router.Group("/audit-logs")
+	auditGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		auditGroup.GET("", h.List)
+	}
+}
@@ -1,23 +1,28 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"admin-dashboard/internal/models"
 	"admin-dashboard/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	authService *services.AuthService
+	authService    *services.AuthService
+	sessionService *services.SessionService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, sessionService *services.SessionService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:    authService,
+		sessionService: sessionService,
 	}
 }
 
@@ -43,12 +48,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 	
 	// Authenticate user
-	response, err := h.authService.Login(request.Email, request.Password)
+	response, err := h.authService.Login(request.Email, request.Password, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 	
+	// Keep the token out of caches and stored idempotent responses
+	c.Header("Cache-Control", "no-store")
 	c.JSON(http.StatusOK, response)
 }
 
@@ -81,6 +88,76 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// Sessions lists the current user's active sessions
+// @Summary List current user sessions
+// @Description List the active sessions of the currently authenticated user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SessionResponse "Active sessions"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /auth/sessions [get]
+func (h *AuthHandler) Sessions(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	
+	// Get sessions
+	sessions, err := h.sessionService.ListSessions(userID.(uint), c.GetString("sessionID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession revokes one of the current user's sessions
+// @Summary Revoke a session
+// @Description Revoke one of the currently authenticated user's sessions
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Session not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	// Parse ID from URL
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+	
+	// Get user from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	
+	// Revoke session
+	err = h.sessionService.RevokeSession(userID.(uint), sessionID, c.GetString("employeeID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
 // RegisterRoutes registers the auth routes
 func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
 	authGroup := router.Group("/auth")
@@ -90,6 +167,8 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gi
 		// Gunakan middleware untuk endpoint profile
 		if authMiddleware != nil {
 			authGroup.GET("/profile", *authMiddleware, h.Profile)
+			authGroup.GET("/sessions", *authMiddleware, h.Sessions)
+			authGroup.DELETE("/sessions/:id", *authMiddleware, h.RevokeSession)
 		} else {
 			authGroup.GET("/profile", h.Profile)
 		}
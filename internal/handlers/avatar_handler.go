@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// avatarFormOverhead allows for the multipart framing around the uploaded file
+const avatarFormOverhead = 64 * 1024
+
+// AvatarHandler handles avatar HTTP requests
+type AvatarHandler struct {
+	avatarService *services.AvatarService
+}
+
+// NewAvatarHandler creates a new avatar handler
+func NewAvatarHandler(avatarService *services.AvatarService) *AvatarHandler {
+	return &AvatarHandler{
+		avatarService: avatarService,
+	}
+}
+
+// Upload handles avatar uploads
+// @Summary Upload an avatar
+// @Description Upload a JPEG, PNG or GIF profile image. Metadata such as EXIF is removed and original (at most 1024px), medium (256px) and small (64px) versions are stored. Users may change their own avatar; admins may change anyone's
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param file formData file true "Image file"
+// @Success 200 {object} models.UserResponse "Updated user"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "Unsupported image type"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/avatar [post]
+func (h *AvatarHandler) Upload(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	maxSize := h.avatarService.MaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(maxSize+avatarFormOverhead))
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrAvatarTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Multipart field \"file\" is required"})
+		return
+	}
+	if fileHeader.Size > int64(maxSize) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrAvatarTooLarge.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, int64(maxSize)+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	user, err := h.avatarService.Upload(c.Request.Context(), uint(id), data, actorID.(uint), roleNames, c.GetString("employeeID"))
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own avatar"})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	case errors.Is(err, services.ErrAvatarTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrAvatarUnsupported):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Get serves a user's avatar
+// @Summary Get an avatar
+// @Description Serve a user's avatar image. Requests with the current version in the v parameter (as in the user's profile_image URL) may be cached indefinitely; others are revalidated with the ETag
+// @Tags users
+// @Produce image/jpeg,image/png
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param size query string false "original, medium or small" default(original)
+// @Param v query string false "Avatar version"
+// @Success 200 {file} binary "Avatar image"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Avatar not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/avatar [get]
+func (h *AvatarHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	size := c.DefaultQuery("size", services.AvatarSizeOriginal)
+	avatar, err := h.avatarService.Get(c.Request.Context(), uint(id), size)
+	switch {
+	case errors.Is(err, services.ErrAvatarSize):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, services.ErrBlobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Avatar not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A versioned URL always refers to the same image, so it never needs revalidating
+	etag := `"` + avatar.Version + "-" + size + `"`
+	c.Header("ETag", etag)
+	if c.Query("v") == avatar.Version {
+		c.Header("Cache-Control", "private, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, avatar.ContentType, avatar.Data)
+}
+
+// Delete handles avatar removal
+// @Summary Delete an avatar
+// @Description Remove a user's uploaded avatar. Users may remove their own avatar; admins may remove anyone's
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserResponse "Updated user"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Avatar not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/avatar [delete]
+func (h *AvatarHandler) Delete(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	user, err := h.avatarService.Delete(c.Request.Context(), uint(id), actorID.(uint), roleNames, c.GetString("employeeID"))
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only remove your own avatar"})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, services.ErrBlobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Avatar not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RegisterRoutes registers the avatar routes
+func (h *AvatarHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+	userGroup := router.Group("/users")
+	userGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		userGroup.POST("/:id/avatar", h.Upload)
+		userGroup.GET("/:id/avatar", h.Get)
+		userGroup.DELETE("/:id/avatar", h.Delete)
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CalendarHandler handles calendar feed HTTP requests
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// CreateFeed creates the current manager's calendar feed
+// @Summary Create a calendar feed
+// @Description Create an iCalendar subscription URL with the birthdays, work anniversaries and probation ends of the current manager's direct reports. Creating it again replaces the URL (managers only)
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} models.CalendarFeedResponse "Feed URL, shown only once"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not a manager"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /calendar/feed [post]
+func (h *CalendarHandler) CreateFeed(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	token, feed, err := h.calendarService.CreateFeed(userID.(uint), c.GetString("employeeID"))
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only managers can subscribe to a calendar feed"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The feed URL carries its token, so it must not be cached or kept for replay
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, models.CalendarFeedResponse{
+		URL:       requestBaseURL(c) + "/api/calendar/feed/" + token + "/calendar.ics",
+		CreatedAt: feed.CreatedAt,
+	})
+}
+
+// DeleteFeed deletes the current manager's calendar feed
+// @Summary Delete the calendar feed
+// @Description Stop the current user's calendar subscription URL from working
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Feed not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /calendar/feed [delete]
+func (h *CalendarHandler) DeleteFeed(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	err := h.calendarService.DeleteFeed(userID.(uint))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feed deleted successfully"})
+}
+
+// Feed serves a calendar feed
+// @Summary Get a calendar feed
+// @Description iCalendar feed of a manager's direct reports for the last 30 days and the next 11 months. The token in the URL authenticates the request, so calendar applications can subscribe to it
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {object} map[string]string "Feed not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /calendar/feed/{token}/calendar.ics [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	feed, err := h.calendarService.RenderFeed(c.Param("token"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// requestBaseURL returns the scheme and host the client used to reach the API
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// RegisterRoutes registers the calendar routes
+func (h *CalendarHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+	calendarGroup := router.Group("/calendar")
+	{
+		calendarGroup.POST("/feed", *authMiddleware, h.CreateFeed)
+		calendarGroup.DELETE("/feed", *authMiddleware, h.DeleteFeed)
+		calendarGroup.GET("/feed/:token/calendar.ics", h.Feed) // The token authenticates calendar applications
+	}
+}
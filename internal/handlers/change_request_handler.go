@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ChangeRequestHandler handles change request HTTP requests
+type ChangeRequestHandler struct {
+	approvalService *services.ApprovalService
+}
+
+// NewChangeRequestHandler creates a new change request handler
+func NewChangeRequestHandler(approvalService *services.ApprovalService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{
+		approvalService: approvalService,
+	}
+}
+
+// List lists change requests
+// @Summary List change requests
+// @Description List sensitive user changes awaiting or past approval
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Page size (default: 10)"
+// @Param status query string false "Filter by status (pending, approved, rejected)"
+// @Param user_id query int false "Filter by target user"
+// @Success 200 {object} models.PaginatedResponse "List of change requests"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /change-requests [get]
+func (h *ChangeRequestHandler) List(c *gin.Context) {
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+
+	// Get change requests
+	changeRequests, err := h.approvalService.List(page, limit, c.Query("status"), uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changeRequests)
+}
+
+// Get gets a change request by ID
+// @Summary Get a change request
+// @Description Get a change request with its proposed changes and comments
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Change request ID"
+// @Success 200 {object} models.ChangeRequestResponse "Change request details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Router /change-requests/{id} [get]
+func (h *ChangeRequestHandler) Get(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid change request ID"})
+		return
+	}
+
+	// Get change request
+	changeRequest, err := h.approvalService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Change request not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, changeRequest)
+}
+
+// Approve approves a change request
+// @Summary Approve a change request
+// @Description Apply the proposed changes; only an approver other than the requester may approve
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Change request ID"
+// @Param decision body models.ChangeRequestDecisionRequest false "Decision comment"
+// @Success 200 {object} models.ChangeRequestResponse "Approved change request"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Not an approver"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Failure 409 {object} map[string]string "Conflicting roles"
+// @Router /change-requests/{id}/approve [post]
+func (h *ChangeRequestHandler) Approve(c *gin.Context) {
+	h.decide(c, h.approvalService.Approve)
+}
+
+// Reject rejects a change request
+// @Summary Reject a change request
+// @Description Reject the proposed changes; only an approver other than the requester may reject
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Change request ID"
+// @Param decision body models.ChangeRequestDecisionRequest false "Decision comment"
+// @Success 200 {object} models.ChangeRequestResponse "Rejected change request"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Not an approver"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Router /change-requests/{id}/reject [post]
+func (h *ChangeRequestHandler) Reject(c *gin.Context) {
+	h.decide(c, h.approvalService.Reject)
+}
+
+// Comment comments on a change request
+// @Summary Comment on a change request
+// @Description Add a comment; the requester, approvers and admins may comment
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Change request ID"
+// @Param comment body models.ChangeRequestCommentRequest true "Comment"
+// @Success 201 {object} models.ChangeRequestResponse "Change request with comments"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Not involved"
+// @Failure 404 {object} map[string]string "Change request not found"
+// @Router /change-requests/{id}/comments [post]
+func (h *ChangeRequestHandler) Comment(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid change request ID"})
+		return
+	}
+
+	var request models.ChangeRequestCommentRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get author from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	employeeID, _ := c.Get("employeeID")
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	// Add comment
+	changeRequest, err := h.approvalService.Comment(uint(id), request.Body, userID.(uint), roleNames, employeeID.(string))
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Change request not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, changeRequest)
+}
+
+// decide runs an approve or reject decision for the current user
+func (h *ChangeRequestHandler) decide(c *gin.Context, decision func(id uint, actorID uint, actorRoles []string, decidedBy, comment string) (*models.ChangeRequestResponse, error)) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid change request ID"})
+		return
+	}
+
+	// The comment is optional, so an empty body is allowed
+	var request models.ChangeRequestDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Get approver from context
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	employeeID, _ := c.Get("employeeID")
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	// Record decision
+	changeRequest, err := decision(uint(id), userID.(uint), roleNames, employeeID.(string), request.Comment)
+	var conflictErr *services.RoleConflictError
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Change request not found"})
+		return
+	} else if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changeRequest)
+}
+
+// RegisterRoutes registers the change request routes
+func (h *ChangeRequestHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+	changeRequestGroup := router.Group("/change-requests")
+	changeRequestGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		changeRequestGroup.GET("", h.List)
+		changeRequestGroup.GET("/:id", h.Get)
+		changeRequestGroup.POST("/:id/approve", h.Approve)
+		changeRequestGroup.POST("/:id/reject", h.Reject)
+		changeRequestGroup.POST("/:id/comments", h.Comment)
+	}
+}
@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ChecklistHandler handles onboarding and offboarding checklist HTTP requests
+type ChecklistHandler struct {
+	checklistService *services.ChecklistService
+}
+
+// NewChecklistHandler creates a new checklist handler
+func NewChecklistHandler(checklistService *services.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{
+		checklistService: checklistService,
+	}
+}
+
+// ListTemplates lists checklist templates
+// @Summary List checklist templates
+// @Description List onboarding and offboarding checklist templates with their tasks (admin only)
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param kind query string false "onboarding or offboarding"
+// @Success 200 {array} models.ChecklistTemplate "List of templates"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /checklist-templates [get]
+func (h *ChecklistHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.checklistService.ListTemplates(c.Query("kind"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate gets a checklist template by ID
+// @Summary Get a checklist template
+// @Description Get a checklist template with its tasks (admin only)
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} models.ChecklistTemplate "Template details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Router /checklist-templates/{id} [get]
+func (h *ChecklistHandler) GetTemplate(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	// Get template
+	template, err := h.checklistService.GetTemplate(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateTemplate creates a checklist template
+// @Summary Create a checklist template
+// @Description Create a checklist template for a division and/or position. Each task is assigned to a role or to the user's manager and is due a number of days after the join or deactivation date (admin only)
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template body models.ChecklistTemplateRequest true "Template details"
+// @Success 201 {object} models.ChecklistTemplate "Created template"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /checklist-templates [post]
+func (h *ChecklistHandler) CreateTemplate(c *gin.Context) {
+	var request models.ChecklistTemplateRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create template
+	template, err := h.checklistService.CreateTemplate(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate updates a checklist template
+// @Summary Update a checklist template
+// @Description Update a checklist template and replace its tasks; checklists already created from it are not changed (admin only)
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param template body models.ChecklistTemplateRequest true "Template details"
+// @Success 200 {object} models.ChecklistTemplate "Updated template"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Router /checklist-templates/{id} [put]
+func (h *ChecklistHandler) UpdateTemplate(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var request models.ChecklistTemplateRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updater ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Update template
+	template, err := h.checklistService.UpdateTemplate(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate deletes a checklist template
+// @Summary Delete a checklist template
+// @Description Delete a checklist template; checklists already created from it are kept (admin only)
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /checklist-templates/{id} [delete]
+func (h *ChecklistHandler) DeleteTemplate(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	// Delete template
+	err = h.checklistService.DeleteTemplate(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// ListByUser lists a user's checklists
+// @Summary List a user's checklists
+// @Description List the onboarding and offboarding checklists of a user with their tasks. Admins, the user and the user's manager may see them
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.Checklist "Checklists"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/checklists [get]
+func (h *ChecklistHandler) ListByUser(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	checklists, err := h.checklistService.ListByUser(uint(id), actorID.(uint), roleNames)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, checklists)
+}
+
+// ListTasks lists checklist tasks
+// @Summary List checklist tasks
+// @Description List checklist tasks across users ordered by due date; status=overdue reports open tasks past their due date. Non-admins only see the tasks of their roles and of the users they manage
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open, overdue or completed"
+// @Param user_id query int false "Only the tasks of this user's checklists"
+// @Param mine query bool false "Only the tasks the current user may complete"
+// @Success 200 {array} models.ChecklistTaskItem "Tasks"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /checklists/tasks [get]
+func (h *ChecklistHandler) ListTasks(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	filter := models.ChecklistTaskFilter{
+		Status:     c.Query("status"),
+		Mine:       c.Query("mine") == "true",
+		ActorID:    actorID.(uint),
+		ActorRoles: roleNames,
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+
+	tasks, err := h.checklistService.ListTasks(&filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+// CompleteTask marks a checklist task as completed
+// @Summary Complete a checklist task
+// @Description Mark a checklist task as completed. Admins, holders of the task's role and the assigned manager may complete it
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.ChecklistTask "Updated task"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /checklists/tasks/{id}/complete [post]
+func (h *ChecklistHandler) CompleteTask(c *gin.Context) {
+	h.setTaskCompletion(c, true)
+}
+
+// ReopenTask marks a completed checklist task as open again
+// @Summary Reopen a checklist task
+// @Description Mark a completed checklist task as open again. Admins, holders of the task's role and the assigned manager may reopen it
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.ChecklistTask "Updated task"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Task not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /checklists/tasks/{id}/reopen [post]
+func (h *ChecklistHandler) ReopenTask(c *gin.Context) {
+	h.setTaskCompletion(c, false)
+}
+
+// setTaskCompletion completes or reopens the task named in the URL
+func (h *ChecklistHandler) setTaskCompletion(c *gin.Context, completed bool) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	var task *models.ChecklistTask
+	if completed {
+		task, err = h.checklistService.CompleteTask(uint(id), actorID.(uint), c.GetString("employeeID"), roleNames)
+	} else {
+		task, err = h.checklistService.ReopenTask(uint(id), actorID.(uint), c.GetString("employeeID"), roleNames)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	} else if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// RegisterRoutes registers the checklist routes
+func (h *ChecklistHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	templateGroup := router.Group("/checklist-templates")
+	templateGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		templateGroup.GET("", h.ListTemplates)
+		templateGroup.GET("/:id", h.GetTemplate)
+		templateGroup.POST("", h.CreateTemplate)
+		templateGroup.PUT("/:id", h.UpdateTemplate)
+		templateGroup.DELETE("/:id", h.DeleteTemplate)
+	}
+
+	userGroup := router.Group("/users")
+	userGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		userGroup.GET("/:id/checklists", h.ListByUser)
+	}
+
+	checklistGroup := router.Group("/checklists")
+	checklistGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		checklistGroup.GET("/tasks", h.ListTasks)
+		checklistGroup.POST("/tasks/:id/complete", h.CompleteTask)
+		checklistGroup.POST("/tasks/:id/reopen", h.ReopenTask)
+	}
+}
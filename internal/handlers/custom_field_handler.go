@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CustomFieldHandler handles custom field definition HTTP requests
+type CustomFieldHandler struct {
+	customFieldService *services.CustomFieldService
+}
+
+// NewCustomFieldHandler creates a new custom field handler
+func NewCustomFieldHandler(customFieldService *services.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldService: customFieldService,
+	}
+}
+
+// List lists custom field definitions
+// @Summary List custom field definitions
+// @Description List the custom fields defined for users, divisions and positions
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity query string false "user, division or position"
+// @Success 200 {array} models.CustomFieldDefinition "List of definitions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /custom-fields [get]
+func (h *CustomFieldHandler) List(c *gin.Context) {
+	definitions, err := h.customFieldService.List(c.Query("entity"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, definitions)
+}
+
+// Get gets a custom field definition by ID
+// @Summary Get a custom field definition
+// @Description Get a custom field definition by ID
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Definition ID"
+// @Success 200 {object} models.CustomFieldDefinition "Definition details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Custom field not found"
+// @Router /custom-fields/{id} [get]
+func (h *CustomFieldHandler) Get(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom field ID"})
+		return
+	}
+
+	// Get definition
+	definition, err := h.customFieldService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// Create creates a custom field definition
+// @Summary Create a custom field definition
+// @Description Define a custom field for users, divisions or positions with its type and validation rules (admin only)
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param definition body models.CustomFieldDefinitionRequest true "Definition details"
+// @Success 201 {object} models.CustomFieldDefinition "Created definition"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /custom-fields [post]
+func (h *CustomFieldHandler) Create(c *gin.Context) {
+	var request models.CustomFieldDefinitionRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create definition
+	definition, err := h.customFieldService.Create(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, definition)
+}
+
+// Update updates a custom field definition
+// @Summary Update a custom field definition
+// @Description Update a custom field's label and validation rules; entity, key and type cannot change (admin only)
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Definition ID"
+// @Param definition body models.CustomFieldDefinitionRequest true "Definition details"
+// @Success 200 {object} models.CustomFieldDefinition "Updated definition"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Custom field not found"
+// @Router /custom-fields/{id} [put]
+func (h *CustomFieldHandler) Update(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom field ID"})
+		return
+	}
+
+	var request models.CustomFieldDefinitionRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updater ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Update definition
+	definition, err := h.customFieldService.Update(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// Delete deletes a custom field definition
+// @Summary Delete a custom field definition
+// @Description Delete a custom field and remove its values from every user, division or position (admin only)
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Definition ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Custom field not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /custom-fields/{id} [delete]
+func (h *CustomFieldHandler) Delete(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom field ID"})
+		return
+	}
+
+	// Delete definition
+	err = h.customFieldService.Delete(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom field deleted successfully"})
+}
+
+// RegisterRoutes registers the custom field routes
+func (h *CustomFieldHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	customFieldGroup := router.Group("/custom-fields")
+	customFieldGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		customFieldGroup.GET("", h.List)
+		customFieldGroup.GET("/:id", h.Get)
+		customFieldGroup.POST("", *adminMiddleware, h.Create)
+		customFieldGroup.PUT("/:id", *adminMiddleware, h.Update)
+		customFieldGroup.DELETE("/:id", *adminMiddleware, h.Delete)
+	}
+}
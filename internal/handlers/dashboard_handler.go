@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"admin-dashboard/internal/services"
 
@@ -11,29 +15,40 @@ import (
 // DashboardHandler handles dashboard-related HTTP requests
 type DashboardHandler struct {
 	dashboardService *services.DashboardService
+	dashboardStream  *services.DashboardStream
 }
 
 // NewDashboardHandler creates a new dashboard handler
-func NewDashboardHandler(dashboardService *services.DashboardService) *DashboardHandler {
+func NewDashboardHandler(dashboardService *services.DashboardService, dashboardStream *services.DashboardStream) *DashboardHandler {
 	return &DashboardHandler{
 		dashboardService: dashboardService,
+		dashboardStream:  dashboardStream,
 	}
 }
 
 // GetStatistics gets dashboard statistics
 // @Summary Get dashboard statistics
-// @Description Get statistics for the dashboard
+// @Description Get statistics for the dashboard, served from a short-lived cache; generated_at tells when they were computed
 // @Tags dashboard
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param fresh query bool false "Bypass the cache (admin only)"
 // @Success 200 {object} models.Statistics "Dashboard statistics"
+// @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /dashboard/statistics [get]
 func (h *DashboardHandler) GetStatistics(c *gin.Context) {
+	fresh := c.Query("fresh") == "true"
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
 	// Get statistics
-	stats, err := h.dashboardService.GetStatistics()
-	if err != nil {
+	stats, err := h.dashboardService.GetStatistics(fresh, roleNames)
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -41,11 +56,197 @@ func (h *DashboardHandler) GetStatistics(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetInactiveUsers gets the inactive users report
+// @Summary Get inactive users report
+// @Description List active users who have not logged in for the given number of days
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days without login (default: 90)"
+// @Success 200 {array} models.InactiveUser "Inactive users"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /dashboard/inactive-users [get]
+func (h *DashboardHandler) GetInactiveUsers(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "90"))
+	
+	// Get report
+	users, err := h.dashboardService.GetInactiveUsers(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, users)
+}
+
+// GetTrends gets the headcount trends report
+// @Summary Get headcount trends
+// @Description Hires, leavers, headcount and turnover rate per period, optionally broken down by division or position
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date YYYY-MM-DD (default: 12 months ago)"
+// @Param to query string false "End date YYYY-MM-DD (default: today)"
+// @Param interval query string false "week, month, quarter or year (default: month)"
+// @Param group_by query string false "division or position"
+// @Success 200 {object} models.TrendsResponse "Trends report"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /dashboard/trends [get]
+func (h *DashboardHandler) GetTrends(c *gin.Context) {
+	// Parse date range
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+	
+	from := time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	
+	// Get report
+	trends, err := h.dashboardService.GetTrends(from, to, c.Query("interval"), c.Query("group_by"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, trends)
+}
+
+// Stream streams dashboard statistics changes
+// @Summary Stream dashboard statistics
+// @Description Server-sent events: a "snapshot" event with the full statistics, then "delta" events with the fields that changed whenever users, divisions or positions change. Send Last-Event-ID to resume.
+// @Tags dashboard
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {string} string "Event stream"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /dashboard/stream [get]
+func (h *DashboardHandler) Stream(c *gin.Context) {
+	// Subscribe before writing anything so errors can still be reported as JSON
+	initial, messages, unsubscribe, err := h.dashboardStream.Subscribe(c.GetHeader("Last-Event-ID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+	
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
+	c.Status(http.StatusOK)
+	
+	for _, message := range initial {
+		writeDashboardEvent(c, message)
+	}
+	c.Writer.Flush()
+	
+	heartbeat := time.NewTicker(h.dashboardStream.Heartbeat())
+	defer heartbeat.Stop()
+	
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			writeDashboardEvent(c, message)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeDashboardEvent writes one server-sent event
+func writeDashboardEvent(c *gin.Context, message services.DashboardMessage) {
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Event, message.Data)
+}
+
+// GetCalendar gets the birthdays, work anniversaries and probation ends calendar
+// @Summary Get the people calendar
+// @Description Birthdays, work anniversaries and probation end dates of active users, optionally of one division
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date YYYY-MM-DD (default: today)"
+// @Param to query string false "End date YYYY-MM-DD, inclusive (default: 30 days after from)"
+// @Param division_id query int false "Filter by division"
+// @Success 200 {array} models.CalendarEvent "Calendar events"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /dashboard/calendar [get]
+func (h *DashboardHandler) GetCalendar(c *gin.Context) {
+	// Parse date range
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, 30)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+
+	var divisionID *uint
+	if value := c.Query("division_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid division ID"})
+			return
+		}
+		divisionIDValue := uint(id)
+		divisionID = &divisionIDValue
+	}
+
+	// Get calendar
+	events, err := h.dashboardService.GetCalendar(from, to, divisionID, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
 // RegisterRoutes registers the dashboard routes
 func (h *DashboardHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
 	dashboardGroup := router.Group("/dashboard")
 	dashboardGroup.Use(*authMiddleware) // Apply auth middleware
 	{
 		dashboardGroup.GET("/statistics", h.GetStatistics)
+		dashboardGroup.GET("/inactive-users", h.GetInactiveUsers)
+		dashboardGroup.GET("/trends", h.GetTrends)
+		dashboardGroup.GET("/calendar", h.GetCalendar)
+		dashboardGroup.GET("/stream", h.Stream)
 	}
 }
\ No newline at end of file
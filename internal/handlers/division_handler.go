@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"admin-dashboard/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // DivisionHandler handles division-related HTTP requests
@@ -24,13 +26,15 @@ func NewDivisionHandler(divisionService *services.DivisionService) *DivisionHand
 
 // Get gets a division by ID
 // @Summary Get a division by ID
-// @Description Get a division by its ID
+// @Description Get a division by its ID. The response carries an ETag, and If-None-Match returns 304 when it is unchanged
 // @Tags divisions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Division ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.Division "Division details"
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Division not found"
 // @Failure 500 {object} map[string]string "Server error"
@@ -50,7 +54,7 @@ func (h *DivisionHandler) Get(c *gin.Context) {
 		return
 	}
 	
-	c.JSON(http.StatusOK, division)
+	writeVersioned(c, http.StatusOK, division.Version, division)
 }
 
 // Create creates a new division
@@ -93,16 +97,19 @@ func (h *DivisionHandler) Create(c *gin.Context) {
 
 // Update updates a division
 // @Summary Update a division
-// @Description Update a division with the provided details
+// @Description Update a division with the provided details. If-Match with the ETag from GET rejects the update when the division changed since
 // @Tags divisions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Division ID"
+// @Param If-Match header string false "ETag the update is based on"
 // @Param division body models.DivisionRequest true "Division details"
 // @Success 200 {object} models.Division "Updated division"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Division not found"
+// @Failure 412 {object} map[string]string "Division was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /divisions/{id} [put]
 func (h *DivisionHandler) Update(c *gin.Context) {
@@ -121,6 +128,76 @@ func (h *DivisionHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// Get current division
+	current, err := h.divisionService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Division not found"})
+		return
+	}
+	
+	h.update(c, current, &request)
+}
+
+// Patch partially updates a division
+// @Summary Partially update a division
+// @Description Update a division with a JSON merge patch (RFC 7396): fields left out are unchanged and null resets a field, and null custom_fields clears them all. The result is validated like PUT. If-Match with the ETag from GET rejects the update when the division changed since
+// @Tags divisions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Division ID"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param division body object true "Merge patch of division fields"
+// @Success 200 {object} models.Division "Updated division"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Division not found"
+// @Failure 412 {object} map[string]string "Division was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /divisions/{id} [patch]
+func (h *DivisionHandler) Patch(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid division ID"})
+		return
+	}
+	
+	// Read merge patch
+	patch, err := readMergePatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	// Get current division
+	current, err := h.divisionService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Division not found"})
+		return
+	}
+	
+	// Apply the patch to the current division and validate the result like PUT
+	if err := patchCustomFields(patch, current.CustomFields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var request models.DivisionRequest
+	if err := mergePatch(&models.DivisionRequest{Code: current.Code, Name: current.Name}, patch, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	h.update(c, current, &request)
+}
+
+// update applies an update to a division on behalf of the current user, unless the request's
+// If-Match names another version
+func (h *DivisionHandler) update(c *gin.Context, current *models.Division, request *models.DivisionRequest) {
 	// Get updater ID from context
 	employeeID, exists := c.Get("employeeID")
 	if !exists {
@@ -128,14 +205,23 @@ func (h *DivisionHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// Check the version the client's changes are based on
+	if !checkIfMatch(c, current.Version) {
+		return
+	}
+	request.Version = &current.Version
+	
 	// Update division
-	division, err := h.divisionService.Update(uint(id), &request, employeeID.(string))
-	if err != nil {
+	division, err := h.divisionService.Update(current.ID, request, employeeID.(string))
+	if errors.Is(err, services.ErrVersionConflict) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	
-	c.JSON(http.StatusOK, division)
+	writeVersioned(c, http.StatusOK, division.Version, division)
 }
 
 // Delete deletes a division
@@ -219,7 +305,7 @@ func (h *DivisionHandler) ListAll(c *gin.Context) {
 }
 
 // RegisterRoutes registers the division routes
-func (h *DivisionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+func (h *DivisionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, ifMatchMiddleware *gin.HandlerFunc) {
 	divisionGroup := router.Group("/divisions")
 	divisionGroup.Use(*authMiddleware) // Apply auth middleware
 	{
@@ -227,7 +313,8 @@ func (h *DivisionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware
 		divisionGroup.GET("/all", h.ListAll)
 		divisionGroup.POST("", h.Create)
 		divisionGroup.GET("/:id", h.Get)
-		divisionGroup.PUT("/:id", h.Update)
+		divisionGroup.PUT("/:id", *ifMatchMiddleware, h.Update)
+		divisionGroup.PATCH("/:id", *ifMatchMiddleware, h.Patch)
 		divisionGroup.DELETE("/:id", h.Delete)
 	}
 }
\ No newline at end of file
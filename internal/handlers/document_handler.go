@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// documentFormOverhead allows for the multipart framing and form fields around the uploaded file
+const documentFormOverhead = 64 * 1024
+
+// DocumentHandler handles user document HTTP requests
+type DocumentHandler struct {
+	documentService *services.DocumentService
+}
+
+// NewDocumentHandler creates a new document handler
+func NewDocumentHandler(documentService *services.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// List handles listing a user's documents
+// @Summary List a user's documents
+// @Description List the documents attached to a user that the current user may read. Public documents are visible to everyone, internal ones to the employee, their manager and HR, confidential ones to HR and restricted ones to the restricted document roles
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.UserDocument "Documents"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/documents [get]
+func (h *DocumentHandler) List(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	documents, err := h.documentService.List(uint(id), actorID.(uint), roleNames)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// Upload handles document uploads
+// @Summary Upload a document
+// @Description Attach a file such as a contract, ID or certificate to a user. Its SHA-256 checksum is stored and verified on download (admins and HR only)
+// @Tags documents
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param file formData file true "Document file"
+// @Param type formData string true "contract, id, certificate or other"
+// @Param confidentiality formData string false "public, internal, confidential or restricted" default(internal)
+// @Param expires_at formData string false "Expiry date (YYYY-MM-DD)"
+// @Success 201 {object} models.UserDocument "Created document"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/documents [post]
+func (h *DocumentHandler) Upload(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	maxSize := h.documentService.MaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(maxSize+documentFormOverhead))
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrDocumentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Multipart field \"file\" is required"})
+		return
+	}
+	if fileHeader.Size > int64(maxSize) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrDocumentTooLarge.Error()})
+		return
+	}
+
+	var request models.UserDocumentRequest
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, int64(maxSize)+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	document, err := h.documentService.Upload(
+		c.Request.Context(),
+		uint(id),
+		fileHeader.Filename,
+		fileHeader.Header.Get("Content-Type"),
+		data,
+		&request,
+		actorID.(uint),
+		roleNames,
+		c.GetString("employeeID"),
+	)
+	switch {
+	case errors.Is(err, services.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot upload documents at this confidentiality level"})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	case errors.Is(err, services.ErrDocumentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// Download handles document downloads
+// @Summary Download a document
+// @Description Download a document the current user may read. The response carries the verified SHA-256 checksum in the X-Checksum-Sha256 header
+// @Tags documents
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param documentId path int true "Document ID"
+// @Success 200 {file} binary "Document file"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Document not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/documents/{documentId} [get]
+func (h *DocumentHandler) Download(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	documentID, err := strconv.ParseUint(c.Param("documentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	document, data, err := h.documentService.Download(c.Request.Context(), uint(id), uint(documentID), actorID.(uint), roleNames)
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, services.ErrBlobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Serve as an attachment so uploaded HTML or scripts never run in the browser
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.Name}))
+	c.Header("X-Checksum-Sha256", document.Checksum)
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, document.ContentType, data)
+}
+
+// Delete handles document deletion
+// @Summary Delete a document
+// @Description Delete a document and its file (admins and HR only)
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param documentId path int true "Document ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Document not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/documents/{documentId} [delete]
+func (h *DocumentHandler) Delete(c *gin.Context) {
+	// Get user ID from context (set by auth middleware)
+	actorID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	documentID, err := strconv.ParseUint(c.Param("documentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	err = h.documentService.Delete(c.Request.Context(), uint(id), uint(documentID), actorID.(uint), roleNames)
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Document deleted successfully"})
+}
+
+// Expiring handles the expiring documents report
+// @Summary List expiring documents
+// @Description List documents of all users that expire within the next days days, soonest first, limited to the confidentiality levels the current user may read (admins, HR and restricted document roles)
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days ahead (1-366)" default(30)
+// @Success 200 {array} models.ExpiringDocument "Expiring documents"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /documents/expiring [get]
+func (h *DocumentHandler) Expiring(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+		return
+	}
+
+	roles, _ := c.Get("roles")
+	roleNames, _ := roles.([]string)
+
+	documents, err := h.documentService.Expiring(days, roleNames)
+	if errors.Is(err, services.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// RegisterRoutes registers the document routes
+func (h *DocumentHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+	userGroup := router.Group("/users")
+	userGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		userGroup.GET("/:id/documents", h.List)
+		userGroup.POST("/:id/documents", h.Upload)
+		userGroup.GET("/:id/documents/:documentId", h.Download)
+		userGroup.DELETE("/:id/documents/:documentId", h.Delete)
+	}
+
+	documentGroup := router.Group("/documents")
+	documentGroup.Use(*authMiddleware) // Apply auth middleware
+	{
+		documentGroup.GET("/expiring", h.Expiring)
+	}
+}
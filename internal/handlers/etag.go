@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag formats the version of a resource as a strong entity tag
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// matchesETag reports whether an If-Match or If-None-Match header is "*" or lists the entity
+// tag of version. Weak tags only match when weak is set, as If-None-Match allows.
+func matchesETag(header string, version uint, weak bool) bool {
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// writeVersioned writes a versioned resource with its ETag, or 304 Not Modified when a GET
+// request's If-None-Match already names that version
+func writeVersioned(c *gin.Context, status int, version uint, body interface{}) {
+	c.Header("ETag", etag(version))
+	if c.Request.Method == http.MethodGet {
+		if header := c.GetHeader("If-None-Match"); header != "" && matchesETag(header, version, true) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.JSON(status, body)
+}
+
+// checkIfMatch evaluates the request's If-Match header against the current version of a
+// resource. It writes 412 Precondition Failed and returns false when the header names
+// other versions.
+func checkIfMatch(c *gin.Context, version uint) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" || matchesETag(header, version, false) {
+		return true
+	}
+	c.Header("ETag", etag(version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "the resource was modified since it was read"})
+	return false
+}
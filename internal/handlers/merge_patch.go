@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"admin-dashboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// requiredUserFields lists the user fields a merge patch cannot clear
+var requiredUserFields = []string{"name", "email", "join_date", "is_manager", "is_active"}
+
+// userStringFields lists the user fields that an empty string clears like null
+var userStringFields = []string{"name", "email", "join_date", "phone", "address", "birthdate", "profile_image"}
+
+// readMergePatch reads a JSON merge patch (RFC 7396) from the request body. Only objects are
+// accepted, since a patch of any other type would replace the whole resource.
+func readMergePatch(c *gin.Context) (map[string]json.RawMessage, error) {
+	body, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+	return patch, nil
+}
+
+// mergePatch applies the members of a merge patch to the JSON representation of current and
+// decodes the result into target. Members set to null are removed, which leaves the field at its
+// zero value in target. Objects are replaced rather than merged; request fields holding objects,
+// such as custom_fields, already merge into the stored values.
+func mergePatch(current interface{}, patch map[string]json.RawMessage, target interface{}) error {
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+
+	for field, value := range patch {
+		if isJSONNull(value) {
+			delete(document, field)
+		} else {
+			document[field] = value
+		}
+	}
+
+	merged, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(merged, target)
+}
+
+// patchCustomFields turns a null custom_fields member of a merge patch into the removal of
+// every current custom field, so it clears them instead of leaving them untouched
+func patchCustomFields(patch map[string]json.RawMessage, current models.CustomFields) error {
+	value, ok := patch["custom_fields"]
+	if !ok || !isJSONNull(value) {
+		return nil
+	}
+
+	removals := make(map[string]interface{}, len(current))
+	for key := range current {
+		removals[key] = nil
+	}
+	encoded, err := json.Marshal(removals)
+	if err != nil {
+		return err
+	}
+	patch["custom_fields"] = encoded
+	return nil
+}
+
+// newUserPatchRequest converts a merge patch of a user into an update request. Fields set to
+// null, or string fields set to "", become cleared fields, and null role_ids removes every role.
+// Fields the patch leaves out are not provided, so they stay unchanged.
+func newUserPatchRequest(patch map[string]json.RawMessage) (*models.UpdateUserRequest, error) {
+	var request models.UpdateUserRequest
+	values := make(map[string]json.RawMessage, len(patch))
+	for field, value := range patch {
+		cleared := isJSONNull(value) ||
+			(slices.Contains(userStringFields, field) && bytes.Equal(bytes.TrimSpace(value), []byte(`""`)))
+		switch {
+		case field == "clear":
+			// Not a user field
+		case !cleared:
+			values[field] = value
+		case field == "role_ids":
+			request.RoleIDs = []uint{}
+		case slices.Contains(models.ClearableUserFields, field):
+			request.Clear = append(request.Clear, field)
+		case slices.Contains(requiredUserFields, field):
+			return nil, fmt.Errorf("%s cannot be cleared", field)
+		}
+	}
+	sort.Strings(request.Clear)
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &request); err != nil {
+		return nil, err
+	}
+
+	// Validate like PUT, where email is the only field with binding rules
+	if request.Email != "" {
+		if err := binding.Validator.ValidateStruct(&request); err != nil {
+			return nil, err
+		}
+	}
+	return &request, nil
+}
+
+// isJSONNull reports whether a raw JSON value is null
+func isJSONNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
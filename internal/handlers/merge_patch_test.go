@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"admin-dashboard/internal/models"
+)
+
+func TestMergePatch(t *testing.T) {
+	current := &models.PositionRequest{
+		Code:         "ENG",
+		Name:         "Engineer",
+		CustomFields: map[string]interface{}{"grade": "3", "track": "ic"},
+	}
+
+	tests := []struct {
+		name  string
+		patch string
+		want  models.PositionRequest
+	}{
+		{
+			name:  "empty patch keeps every field",
+			patch: `{}`,
+			want:  *current,
+		},
+		{
+			name:  "member replaces the field",
+			patch: `{"name":"Senior Engineer"}`,
+			want:  models.PositionRequest{Code: "ENG", Name: "Senior Engineer", CustomFields: current.CustomFields},
+		},
+		{
+			name:  "null removes the field",
+			patch: `{"custom_fields":null}`,
+			want:  models.PositionRequest{Code: "ENG", Name: "Engineer"},
+		},
+		{
+			name:  "objects are replaced, not merged",
+			patch: `{"custom_fields":{"grade":"4"}}`,
+			want:  models.PositionRequest{Code: "ENG", Name: "Engineer", CustomFields: map[string]interface{}{"grade": "4"}},
+		},
+		{
+			name:  "unknown members are ignored",
+			patch: `{"unknown":1}`,
+			want:  *current,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("invalid patch: %v", err)
+			}
+
+			var got models.PositionRequest
+			if err := mergePatch(current, patch, &got); err != nil {
+				t.Fatalf("mergePatch: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergePatch = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePatchInvalidValue(t *testing.T) {
+	patch := map[string]json.RawMessage{"level": json.RawMessage(`"high"`)}
+
+	var got models.RoleRequest
+	if err := mergePatch(&models.RoleRequest{Name: "Admin", Level: 1}, patch, &got); err == nil {
+		t.Errorf("mergePatch succeeded with a string level, want an error")
+	}
+}
+
+func TestNewUserPatchRequest(t *testing.T) {
+	divisionID := uint(4)
+	isActive := false
+
+	tests := []struct {
+		name  string
+		patch string
+		want  models.UpdateUserRequest
+	}{
+		{
+			name:  "empty patch changes nothing",
+			patch: `{}`,
+			want:  models.UpdateUserRequest{},
+		},
+		{
+			name:  "values are set",
+			patch: `{"name":"Jane Doe","division_id":4,"is_active":false,"role_ids":[1,2]}`,
+			want:  models.UpdateUserRequest{Name: "Jane Doe", DivisionID: &divisionID, IsActive: &isActive, RoleIDs: []uint{1, 2}},
+		},
+		{
+			name:  "null and empty strings clear fields",
+			patch: `{"phone":null,"address":"","manager_id":null,"custom_fields":null}`,
+			want:  models.UpdateUserRequest{Clear: []string{"address", "custom_fields", "manager_id", "phone"}},
+		},
+		{
+			name:  "null role_ids removes every role",
+			patch: `{"role_ids":null}`,
+			want:  models.UpdateUserRequest{RoleIDs: []uint{}},
+		},
+		{
+			name:  "clear member is ignored",
+			patch: `{"clear":["phone"]}`,
+			want:  models.UpdateUserRequest{},
+		},
+		{
+			name:  "custom fields are passed on for merging",
+			patch: `{"custom_fields":{"shirt_size":"M","badge":null}}`,
+			want:  models.UpdateUserRequest{CustomFields: map[string]interface{}{"shirt_size": "M", "badge": nil}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("invalid patch: %v", err)
+			}
+
+			got, err := newUserPatchRequest(patch)
+			if err != nil {
+				t.Fatalf("newUserPatchRequest: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("newUserPatchRequest = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUserPatchRequestInvalid(t *testing.T) {
+	for _, patch := range []string{
+		`{"name":null}`,
+		`{"name":""}`,
+		`{"email":null}`,
+		`{"is_active":null}`,
+		`{"email":"not-an-email"}`,
+		`{"division_id":"four"}`,
+	} {
+		var decoded map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(patch), &decoded); err != nil {
+			t.Fatalf("invalid patch %s: %v", patch, err)
+		}
+		if _, err := newUserPatchRequest(decoded); err == nil {
+			t.Errorf("newUserPatchRequest(%s) succeeded, want an error", patch)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OffboardingHandler handles offboarding HTTP requests
+type OffboardingHandler struct {
+	offboardingService *services.OffboardingService
+}
+
+// NewOffboardingHandler creates a new offboarding handler
+func NewOffboardingHandler(offboardingService *services.OffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{
+		offboardingService: offboardingService,
+	}
+}
+
+// Offboard offboards a user
+// @Summary Offboard a user
+// @Description Reassign a leaver's direct reports to the successor or the leaver's manager, revoke their roles and sessions, deactivate them and record the termination. A future effective date schedules the offboarding instead (admin only)
+// @Tags offboardings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param offboarding body models.OffboardRequest true "Offboarding details"
+// @Success 200 {object} models.Offboarding "Completed offboarding"
+// @Success 202 {object} models.Offboarding "Scheduled offboarding"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 409 {object} map[string]string "Offboarding already scheduled"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/offboard [post]
+func (h *OffboardingHandler) Offboard(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var request models.OffboardRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get actor ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Offboard user
+	offboarding, err := h.offboardingService.Offboard(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if errors.Is(err, services.ErrOffboardingScheduled) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if offboarding.Status == models.OffboardingScheduled {
+		c.JSON(http.StatusAccepted, offboarding)
+		return
+	}
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// List lists offboardings
+// @Summary List offboardings
+// @Description List offboardings, newest effective date first (admin only)
+// @Tags offboardings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Filter by user"
+// @Param status query string false "scheduled, completed, cancelled or failed"
+// @Success 200 {array} models.Offboarding "List of offboardings"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /offboardings [get]
+func (h *OffboardingHandler) List(c *gin.Context) {
+	var userID uint
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		userID = uint(id)
+	}
+
+	offboardings, err := h.offboardingService.List(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, offboardings)
+}
+
+// Get gets an offboarding by ID
+// @Summary Get an offboarding
+// @Description Get an offboarding by ID (admin only)
+// @Tags offboardings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Offboarding ID"
+// @Success 200 {object} models.Offboarding "Offboarding details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Offboarding not found"
+// @Router /offboardings/{id} [get]
+func (h *OffboardingHandler) Get(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offboarding ID"})
+		return
+	}
+
+	// Get offboarding
+	offboarding, err := h.offboardingService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// Cancel cancels a scheduled offboarding
+// @Summary Cancel a scheduled offboarding
+// @Description Cancel an offboarding that has not reached its effective date (admin only)
+// @Tags offboardings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Offboarding ID"
+// @Success 200 {object} models.Offboarding "Cancelled offboarding"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Offboarding not found"
+// @Router /offboardings/{id}/cancel [post]
+func (h *OffboardingHandler) Cancel(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offboarding ID"})
+		return
+	}
+
+	// Get actor ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Cancel offboarding
+	offboarding, err := h.offboardingService.Cancel(uint(id), employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offboarding not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, offboarding)
+}
+
+// RegisterRoutes registers the offboarding routes
+func (h *OffboardingHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	userGroup := router.Group("/users")
+	userGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		userGroup.POST("/:id/offboard", h.Offboard)
+	}
+
+	offboardingGroup := router.Group("/offboardings")
+	offboardingGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		offboardingGroup.GET("", h.List)
+		offboardingGroup.GET("/:id", h.Get)
+		offboardingGroup.POST("/:id/cancel", h.Cancel)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"admin-dashboard/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // PositionHandler handles position-related HTTP requests
@@ -24,13 +26,15 @@ func NewPositionHandler(positionService *services.PositionService) *PositionHand
 
 // Get gets a position by ID
 // @Summary Get a position by ID
-// @Description Get a position by its ID
+// @Description Get a position by its ID. The response carries an ETag, and If-None-Match returns 304 when it is unchanged
 // @Tags positions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Position ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.Position "Position details"
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Position not found"
 // @Failure 500 {object} map[string]string "Server error"
@@ -50,7 +54,7 @@ func (h *PositionHandler) Get(c *gin.Context) {
 		return
 	}
 	
-	c.JSON(http.StatusOK, position)
+	writeVersioned(c, http.StatusOK, position.Version, position)
 }
 
 // Create creates a new position
@@ -93,16 +97,19 @@ func (h *PositionHandler) Create(c *gin.Context) {
 
 // Update updates a position
 // @Summary Update a position
-// @Description Update a position with the provided details
+// @Description Update a position with the provided details. If-Match with the ETag from GET rejects the update when the position changed since
 // @Tags positions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Position ID"
+// @Param If-Match header string false "ETag the update is based on"
 // @Param position body models.PositionRequest true "Position details"
 // @Success 200 {object} models.Position "Updated position"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Position not found"
+// @Failure 412 {object} map[string]string "Position was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /positions/{id} [put]
 func (h *PositionHandler) Update(c *gin.Context) {
@@ -121,6 +128,76 @@ func (h *PositionHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// Get current position
+	current, err := h.positionService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+		return
+	}
+	
+	h.update(c, current, &request)
+}
+
+// Patch partially updates a position
+// @Summary Partially update a position
+// @Description Update a position with a JSON merge patch (RFC 7396): fields left out are unchanged and null resets a field, and null custom_fields clears them all. The result is validated like PUT. If-Match with the ETag from GET rejects the update when the position changed since
+// @Tags positions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Position ID"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param position body object true "Merge patch of position fields"
+// @Success 200 {object} models.Position "Updated position"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Position not found"
+// @Failure 412 {object} map[string]string "Position was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /positions/{id} [patch]
+func (h *PositionHandler) Patch(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position ID"})
+		return
+	}
+	
+	// Read merge patch
+	patch, err := readMergePatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	// Get current position
+	current, err := h.positionService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Position not found"})
+		return
+	}
+	
+	// Apply the patch to the current position and validate the result like PUT
+	if err := patchCustomFields(patch, current.CustomFields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var request models.PositionRequest
+	if err := mergePatch(&models.PositionRequest{Code: current.Code, Name: current.Name}, patch, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	h.update(c, current, &request)
+}
+
+// update applies an update to a position on behalf of the current user, unless the request's
+// If-Match names another version
+func (h *PositionHandler) update(c *gin.Context, current *models.Position, request *models.PositionRequest) {
 	// Get updater ID from context
 	employeeID, exists := c.Get("employeeID")
 	if !exists {
@@ -128,14 +205,23 @@ func (h *PositionHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// Check the version the client's changes are based on
+	if !checkIfMatch(c, current.Version) {
+		return
+	}
+	request.Version = &current.Version
+	
 	// Update position
-	position, err := h.positionService.Update(uint(id), &request, employeeID.(string))
-	if err != nil {
+	position, err := h.positionService.Update(current.ID, request, employeeID.(string))
+	if errors.Is(err, services.ErrVersionConflict) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	
-	c.JSON(http.StatusOK, position)
+	writeVersioned(c, http.StatusOK, position.Version, position)
 }
 
 // Delete deletes a position
@@ -219,7 +305,7 @@ func (h *PositionHandler) ListAll(c *gin.Context) {
 }
 
 // RegisterRoutes registers the position routes
-func (h *PositionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+func (h *PositionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, ifMatchMiddleware *gin.HandlerFunc) {
 	positionGroup := router.Group("/positions")
 	positionGroup.Use(*authMiddleware) // Apply auth middleware
 	{
@@ -227,7 +313,8 @@ func (h *PositionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware
 		positionGroup.GET("/all", h.ListAll)
 		positionGroup.POST("", h.Create)
 		positionGroup.GET("/:id", h.Get)
-		positionGroup.PUT("/:id", h.Update)
+		positionGroup.PUT("/:id", *ifMatchMiddleware, h.Update)
+		positionGroup.PATCH("/:id", *ifMatchMiddleware, h.Patch)
 		positionGroup.DELETE("/:id", h.Delete)
 	}
 }
\ No newline at end of file
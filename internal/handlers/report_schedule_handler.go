@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReportScheduleHandler handles scheduled report email HTTP requests
+type ReportScheduleHandler struct {
+	reportScheduleService *services.ReportScheduleService
+}
+
+// NewReportScheduleHandler creates a new report schedule handler
+func NewReportScheduleHandler(reportScheduleService *services.ReportScheduleService) *ReportScheduleHandler {
+	return &ReportScheduleHandler{
+		reportScheduleService: reportScheduleService,
+	}
+}
+
+// List lists report schedules
+// @Summary List report schedules
+// @Description List scheduled report emails (admin only)
+// @Tags report-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ReportSchedule "List of schedules"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /report-schedules [get]
+func (h *ReportScheduleHandler) List(c *gin.Context) {
+	schedules, err := h.reportScheduleService.ListSchedules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// Get gets a report schedule by ID
+// @Summary Get a report schedule
+// @Description Get a scheduled report email by ID (admin only)
+// @Tags report-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} models.ReportSchedule "Schedule details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Router /report-schedules/{id} [get]
+func (h *ReportScheduleHandler) Get(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	// Get schedule
+	schedule, err := h.reportScheduleService.GetSchedule(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Create creates a report schedule
+// @Summary Create a report schedule
+// @Description Schedule a headcount and work anniversary email with a cron expression; without recipients it goes to the managers of the division (admin only)
+// @Tags report-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param schedule body models.ReportScheduleRequest true "Schedule details"
+// @Success 201 {object} models.ReportSchedule "Created schedule"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /report-schedules [post]
+func (h *ReportScheduleHandler) Create(c *gin.Context) {
+	var request models.ReportScheduleRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create schedule
+	schedule, err := h.reportScheduleService.CreateSchedule(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// Update updates a report schedule
+// @Summary Update a report schedule
+// @Description Update a scheduled report email; the next run is recomputed (admin only)
+// @Tags report-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Param schedule body models.ReportScheduleRequest true "Schedule details"
+// @Success 200 {object} models.ReportSchedule "Updated schedule"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Router /report-schedules/{id} [put]
+func (h *ReportScheduleHandler) Update(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	var request models.ReportScheduleRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updater ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Update schedule
+	schedule, err := h.reportScheduleService.UpdateSchedule(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Delete deletes a report schedule
+// @Summary Delete a report schedule
+// @Description Delete a scheduled report email (admin only)
+// @Tags report-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /report-schedules/{id} [delete]
+func (h *ReportScheduleHandler) Delete(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	// Delete schedule
+	err = h.reportScheduleService.DeleteSchedule(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
+}
+
+// Run sends a scheduled report now
+// @Summary Send a scheduled report now
+// @Description Send a schedule's report immediately without changing its next run (admin only)
+// @Tags report-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} models.ReportSchedule "Schedule after the run"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /report-schedules/{id}/run [post]
+func (h *ReportScheduleHandler) Run(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	// Send report
+	schedule, err := h.reportScheduleService.RunSchedule(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// RegisterRoutes registers the report schedule routes
+func (h *ReportScheduleHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	scheduleGroup := router.Group("/report-schedules")
+	scheduleGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		scheduleGroup.GET("", h.List)
+		scheduleGroup.POST("", h.Create)
+		scheduleGroup.GET("/:id", h.Get)
+		scheduleGroup.PUT("/:id", h.Update)
+		scheduleGroup.DELETE("/:id", h.Delete)
+		scheduleGroup.POST("/:id/run", h.Run)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"admin-dashboard/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // RoleHandler handles role-related HTTP requests
@@ -24,13 +26,15 @@ func NewRoleHandler(roleService *services.RoleService) *RoleHandler {
 
 // Get gets a role by ID
 // @Summary Get a role by ID
-// @Description Get a role by its ID
+// @Description Get a role by its ID. The response carries an ETag, and If-None-Match returns 304 when it is unchanged
 // @Tags roles
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Role ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.Role "Role details"
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Role not found"
 // @Failure 500 {object} map[string]string "Server error"
@@ -50,7 +54,7 @@ func (h *RoleHandler) Get(c *gin.Context) {
 		return
 	}
 	
-	c.JSON(http.StatusOK, role)
+	writeVersioned(c, http.StatusOK, role.Version, role)
 }
 
 // Create creates a new role
@@ -93,16 +97,19 @@ func (h *RoleHandler) Create(c *gin.Context) {
 
 // Update updates a role
 // @Summary Update a role
-// @Description Update a role with the provided details
+// @Description Update a role with the provided details. If-Match with the ETag from GET rejects the update when the role changed since
 // @Tags roles
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Role ID"
+// @Param If-Match header string false "ETag the update is based on"
 // @Param role body models.RoleRequest true "Role details"
 // @Success 200 {object} models.Role "Updated role"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Role not found"
+// @Failure 412 {object} map[string]string "Role was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /roles/{id} [put]
 func (h *RoleHandler) Update(c *gin.Context) {
@@ -121,6 +128,72 @@ func (h *RoleHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// Get current role
+	current, err := h.roleService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		return
+	}
+	
+	h.update(c, current, &request)
+}
+
+// Patch partially updates a role
+// @Summary Partially update a role
+// @Description Update a role with a JSON merge patch (RFC 7396): fields left out are unchanged and null resets a field. The result is validated like PUT. If-Match with the ETag from GET rejects the update when the role changed since
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param role body object true "Merge patch of role fields"
+// @Success 200 {object} models.Role "Updated role"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Role not found"
+// @Failure 412 {object} map[string]string "Role was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /roles/{id} [patch]
+func (h *RoleHandler) Patch(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+	
+	// Read merge patch
+	patch, err := readMergePatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	// Get current role
+	current, err := h.roleService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		return
+	}
+	
+	// Apply the patch to the current role and validate the result like PUT
+	var request models.RoleRequest
+	if err := mergePatch(&models.RoleRequest{Name: current.Name, Level: current.Level}, patch, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	h.update(c, current, &request)
+}
+
+// update applies an update to a role on behalf of the current user, unless the request's
+// If-Match names another version
+func (h *RoleHandler) update(c *gin.Context, current *models.Role, request *models.RoleRequest) {
 	// Get updater ID from context
 	employeeID, exists := c.Get("employeeID")
 	if !exists {
@@ -128,14 +201,23 @@ func (h *RoleHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	// Check the version the client's changes are based on
+	if !checkIfMatch(c, current.Version) {
+		return
+	}
+	request.Version = &current.Version
+	
 	// Update role
-	role, err := h.roleService.Update(uint(id), &request, employeeID.(string))
-	if err != nil {
+	role, err := h.roleService.Update(current.ID, request, employeeID.(string))
+	if errors.Is(err, services.ErrVersionConflict) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	
-	c.JSON(http.StatusOK, role)
+	writeVersioned(c, http.StatusOK, role.Version, role)
 }
 
 // Delete deletes a role
@@ -219,7 +301,7 @@ func (h *RoleHandler) ListAll(c *gin.Context) {
 }
 
 // RegisterRoutes registers the role routes
-func (h *RoleHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+func (h *RoleHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, ifMatchMiddleware *gin.HandlerFunc) {
 	roleGroup := router.Group("/roles")
 	roleGroup.Use(*authMiddleware) // Apply auth middleware
 	{
@@ -227,7 +309,8 @@ func (h *RoleHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gi
 		roleGroup.GET("/all", h.ListAll)
 		roleGroup.POST("", h.Create)
 		roleGroup.GET("/:id", h.Get)
-		roleGroup.PUT("/:id", h.Update)
+		roleGroup.PUT("/:id", *ifMatchMiddleware, h.Update)
+		roleGroup.PATCH("/:id", *ifMatchMiddleware, h.Patch)
 		roleGroup.DELETE("/:id", h.Delete)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ScheduledChangeHandler handles scheduled user change HTTP requests
+type ScheduledChangeHandler struct {
+	scheduledChangeService *services.ScheduledChangeService
+}
+
+// NewScheduledChangeHandler creates a new scheduled change handler
+func NewScheduledChangeHandler(scheduledChangeService *services.ScheduledChangeService) *ScheduledChangeHandler {
+	return &ScheduledChangeHandler{
+		scheduledChangeService: scheduledChangeService,
+	}
+}
+
+// Create schedules a user update
+// @Summary Schedule a user update
+// @Description Schedule an update of a user, such as a transfer or promotion, to be applied at effective_at. The changes are validated like PUT /users/{id} now and again when applied (admin only)
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param change body models.ScheduledChangeRequest true "Effective time and changes"
+// @Success 201 {object} models.ScheduledChangeResponse "Scheduled change"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/{id}/scheduled-changes [post]
+func (h *ScheduledChangeHandler) Create(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var request models.ScheduledChangeRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get requester ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	// Schedule change
+	change, err := h.scheduledChangeService.Create(uint(id), &request, userID.(uint), employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// ListByUser lists a user's scheduled changes
+// @Summary List a user's scheduled changes
+// @Description List the scheduled updates of a user in effective order (admin only)
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param status query string false "pending, applied, submitted, cancelled or failed"
+// @Success 200 {array} models.ScheduledChangeResponse "Scheduled changes"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/scheduled-changes [get]
+func (h *ScheduledChangeHandler) ListByUser(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	changes, err := h.scheduledChangeService.List(uint(id), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// List lists scheduled changes
+// @Summary List scheduled changes
+// @Description List scheduled user updates in effective order (admin only)
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Filter by user"
+// @Param status query string false "pending, applied, submitted, cancelled or failed"
+// @Success 200 {array} models.ScheduledChangeResponse "Scheduled changes"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /scheduled-changes [get]
+func (h *ScheduledChangeHandler) List(c *gin.Context) {
+	var userID uint
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		userID = uint(id)
+	}
+
+	changes, err := h.scheduledChangeService.List(userID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// Get gets a scheduled change by ID
+// @Summary Get a scheduled change
+// @Description Get a scheduled user update by ID (admin only)
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Scheduled change ID"
+// @Success 200 {object} models.ScheduledChangeResponse "Scheduled change"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Scheduled change not found"
+// @Router /scheduled-changes/{id} [get]
+func (h *ScheduledChangeHandler) Get(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled change ID"})
+		return
+	}
+
+	// Get scheduled change
+	change, err := h.scheduledChangeService.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled change not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// Update edits a pending scheduled change
+// @Summary Edit a scheduled change
+// @Description Replace the changes and effective time of a pending scheduled update (admin only)
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Scheduled change ID"
+// @Param change body models.ScheduledChangeRequest true "Effective time and changes"
+// @Success 200 {object} models.ScheduledChangeResponse "Updated scheduled change"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Scheduled change not found"
+// @Failure 409 {object} map[string]string "Change is no longer pending"
+// @Router /scheduled-changes/{id} [put]
+func (h *ScheduledChangeHandler) Update(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled change ID"})
+		return
+	}
+
+	var request models.ScheduledChangeRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updater ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Update scheduled change
+	change, err := h.scheduledChangeService.Update(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled change not found"})
+		return
+	} else if errors.Is(err, services.ErrScheduledChangeNotPending) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// Cancel cancels a pending scheduled change
+// @Summary Cancel a scheduled change
+// @Description Cancel a scheduled user update that has not been applied (admin only)
+// @Tags scheduled-changes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Scheduled change ID"
+// @Success 200 {object} models.ScheduledChangeResponse "Cancelled scheduled change"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Scheduled change not found"
+// @Failure 409 {object} map[string]string "Change is no longer pending"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /scheduled-changes/{id}/cancel [post]
+func (h *ScheduledChangeHandler) Cancel(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled change ID"})
+		return
+	}
+
+	// Get actor ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Cancel scheduled change
+	change, err := h.scheduledChangeService.Cancel(uint(id), employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled change not found"})
+		return
+	} else if errors.Is(err, services.ErrScheduledChangeNotPending) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// RegisterRoutes registers the scheduled change routes
+func (h *ScheduledChangeHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	userGroup := router.Group("/users")
+	userGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		userGroup.POST("/:id/scheduled-changes", h.Create)
+		userGroup.GET("/:id/scheduled-changes", h.ListByUser)
+	}
+
+	scheduledChangeGroup := router.Group("/scheduled-changes")
+	scheduledChangeGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		scheduledChangeGroup.GET("", h.List)
+		scheduledChangeGroup.GET("/:id", h.Get)
+		scheduledChangeGroup.PUT("/:id", h.Update)
+		scheduledChangeGroup.POST("/:id/cancel", h.Cancel)
+	}
+}
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMHandler handles SCIM 2.0 provisioning requests
+type SCIMHandler struct {
+	scimService *services.SCIMService
+}
+
+// NewSCIMHandler creates a new SCIM handler
+func NewSCIMHandler(scimService *services.SCIMService) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+	}
+}
+
+// ListUsers lists users
+// @Summary List SCIM users
+// @Description List users with SCIM filter and pagination semantics
+// @Tags scim
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param filter query string false "SCIM filter, e.g. userName eq \"john@company.com\""
+// @Param startIndex query int false "1-based start index (default: 1)"
+// @Param count query int false "Page size (default: 100)"
+// @Success 200 {object} models.SCIMListResponse "List of users"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid filter"
+// @Router /scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	startIndex, _ := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "100"))
+
+	response, err := h.scimService.ListUsers(c.Query("filter"), startIndex, count)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, response)
+}
+
+// GetUser gets a user
+// @Summary Get a SCIM user
+// @Tags scim
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param id path string true "User UID"
+// @Success 200 {object} models.SCIMUser "User"
+// @Failure 404 {object} models.SCIMErrorResponse "User not found"
+// @Router /scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	user, err := h.scimService.GetUser(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, user)
+}
+
+// CreateUser provisions a user
+// @Summary Create a SCIM user
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param user body models.SCIMUser true "User"
+// @Success 201 {object} models.SCIMUser "Created user"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid request"
+// @Failure 409 {object} models.SCIMErrorResponse "User already exists"
+// @Router /scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var request models.SCIMUser
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, &services.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+
+	user, err := h.scimService.CreateUser(&request)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Header("Location", user.Meta.Location)
+	h.respond(c, http.StatusCreated, user)
+}
+
+// ReplaceUser replaces a user
+// @Summary Replace a SCIM user
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param id path string true "User UID"
+// @Param user body models.SCIMUser true "User"
+// @Success 200 {object} models.SCIMUser "Replaced user"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid request"
+// @Failure 404 {object} models.SCIMErrorResponse "User not found"
+// @Router /scim/v2/Users/{id} [put]
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	var request models.SCIMUser
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, &services.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+
+	user, err := h.scimService.ReplaceUser(c.Param("id"), &request)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, user)
+}
+
+// PatchUser patches a user
+// @Summary Patch a SCIM user
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param id path string true "User UID"
+// @Param operations body models.SCIMPatchRequest true "Patch operations"
+// @Success 200 {object} models.SCIMUser "Patched user"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid request"
+// @Failure 404 {object} models.SCIMErrorResponse "User not found"
+// @Router /scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var request models.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, &services.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+
+	user, err := h.scimService.PatchUser(c.Param("id"), &request)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, user)
+}
+
+// DeleteUser deprovisions a user
+// @Summary Deprovision a SCIM user
+// @Description Marks the user inactive instead of deleting it
+// @Tags scim
+// @Security SCIMBearerAuth
+// @Param id path string true "User UID"
+// @Success 204 "User deactivated"
+// @Failure 404 {object} models.SCIMErrorResponse "User not found"
+// @Router /scim/v2/Users/{id} [delete]
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	if err := h.scimService.DeactivateUser(c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups lists groups
+// @Summary List SCIM groups
+// @Description List roles as SCIM groups with filter and pagination semantics
+// @Tags scim
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param filter query string false "SCIM filter, e.g. displayName eq \"admin\""
+// @Param startIndex query int false "1-based start index (default: 1)"
+// @Param count query int false "Page size (default: 100)"
+// @Param excludedAttributes query string false "Set to members to omit group members"
+// @Success 200 {object} models.SCIMListResponse "List of groups"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid filter"
+// @Router /scim/v2/Groups [get]
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	startIndex, _ := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "100"))
+	includeMembers := !strings.Contains(strings.ToLower(c.Query("excludedAttributes")), "members")
+
+	response, err := h.scimService.ListGroups(c.Query("filter"), startIndex, count, includeMembers)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, response)
+}
+
+// GetGroup gets a group
+// @Summary Get a SCIM group
+// @Tags scim
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param id path string true "Role ID"
+// @Success 200 {object} models.SCIMGroup "Group"
+// @Failure 404 {object} models.SCIMErrorResponse "Group not found"
+// @Router /scim/v2/Groups/{id} [get]
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	group, err := h.scimService.GetGroup(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, group)
+}
+
+// CreateGroup provisions a group
+// @Summary Create a SCIM group
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param group body models.SCIMGroup true "Group"
+// @Success 201 {object} models.SCIMGroup "Created group"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid request"
+// @Failure 409 {object} models.SCIMErrorResponse "Group already exists"
+// @Router /scim/v2/Groups [post]
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var request models.SCIMGroup
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, &services.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+
+	group, err := h.scimService.CreateGroup(&request)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Header("Location", group.Meta.Location)
+	h.respond(c, http.StatusCreated, group)
+}
+
+// ReplaceGroup replaces a group
+// @Summary Replace a SCIM group
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param id path string true "Role ID"
+// @Param group body models.SCIMGroup true "Group"
+// @Success 200 {object} models.SCIMGroup "Replaced group"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid request"
+// @Failure 404 {object} models.SCIMErrorResponse "Group not found"
+// @Router /scim/v2/Groups/{id} [put]
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	var request models.SCIMGroup
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, &services.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+
+	group, err := h.scimService.ReplaceGroup(c.Param("id"), &request)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, group)
+}
+
+// PatchGroup patches a group
+// @Summary Patch a SCIM group
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMBearerAuth
+// @Param id path string true "Role ID"
+// @Param operations body models.SCIMPatchRequest true "Patch operations"
+// @Success 200 {object} models.SCIMGroup "Patched group"
+// @Failure 400 {object} models.SCIMErrorResponse "Invalid request"
+// @Failure 404 {object} models.SCIMErrorResponse "Group not found"
+// @Router /scim/v2/Groups/{id} [patch]
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	var request models.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.respondError(c, &services.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: err.Error()})
+		return
+	}
+
+	group, err := h.scimService.PatchGroup(c.Param("id"), &request)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, group)
+}
+
+// DeleteGroup deprovisions a group
+// @Summary Deprovision a SCIM group
+// @Description Marks the role inactive instead of deleting it
+// @Tags scim
+// @Security SCIMBearerAuth
+// @Param id path string true "Role ID"
+// @Success 204 "Group deactivated"
+// @Failure 404 {object} models.SCIMErrorResponse "Group not found"
+// @Router /scim/v2/Groups/{id} [delete]
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	if err := h.scimService.DeactivateGroup(c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ServiceProviderConfig describes the supported SCIM features
+// @Summary Get SCIM service provider configuration
+// @Tags scim
+// @Produce json
+// @Security SCIMBearerAuth
+// @Success 200 {object} map[string]interface{} "Service provider configuration"
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	h.respond(c, http.StatusOK, gin.H{
+		"schemas":        []string{models.SCIMSchemaServiceProviderConfig},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": 200},
+		"changePassword": gin.H{"supported": true},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication using a dedicated SCIM bearer token",
+		}},
+	})
+}
+
+// respond writes a SCIM JSON response
+func (h *SCIMHandler) respond(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", "application/scim+json; charset=utf-8")
+	c.JSON(status, body)
+}
+
+// respondError writes a SCIM error response
+func (h *SCIMHandler) respondError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	var scimType string
+
+	var scimErr *services.SCIMError
+	var conflictErr *services.RoleConflictError
+	if errors.As(err, &scimErr) {
+		status = scimErr.Status
+		scimType = scimErr.ScimType
+	} else if errors.As(err, &conflictErr) {
+		status = http.StatusConflict
+	}
+
+	h.respond(c, status, models.SCIMErrorResponse{
+		Schemas:  []string{models.SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   err.Error(),
+	})
+}
+
+// RegisterRoutes registers the SCIM routes
+func (h *SCIMHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+	scimGroup := router.Group("")
+	scimGroup.Use(*authMiddleware) // Apply SCIM token middleware
+	{
+		scimGroup.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+
+		scimGroup.GET("/Users", h.ListUsers)
+		scimGroup.POST("/Users", h.CreateUser)
+		scimGroup.GET("/Users/:id", h.GetUser)
+		scimGroup.PUT("/Users/:id", h.ReplaceUser)
+		scimGroup.PATCH("/Users/:id", h.PatchUser)
+		scimGroup.DELETE("/Users/:id", h.DeleteUser)
+
+		scimGroup.GET("/Groups", h.ListGroups)
+		scimGroup.POST("/Groups", h.CreateGroup)
+		scimGroup.GET("/Groups/:id", h.GetGroup)
+		scimGroup.PUT("/Groups/:id", h.ReplaceGroup)
+		scimGroup.PATCH("/Groups/:id", h.PatchGroup)
+		scimGroup.DELETE("/Groups/:id", h.DeleteGroup)
+	}
+}
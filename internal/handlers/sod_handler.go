@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SoDHandler handles segregation-of-duties HTTP requests
+type SoDHandler struct {
+	sodService *services.SoDService
+}
+
+// NewSoDHandler creates a new segregation-of-duties handler
+func NewSoDHandler(sodService *services.SoDService) *SoDHandler {
+	return &SoDHandler{
+		sodService: sodService,
+	}
+}
+
+// ListRules lists segregation-of-duties rules
+// @Summary List segregation-of-duties rules
+// @Description List sets of roles that must not be held together (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SoDRule "List of rules"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /sod/rules [get]
+func (h *SoDHandler) ListRules(c *gin.Context) {
+	rules, err := h.sodService.ListRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetRule gets a segregation-of-duties rule by ID
+// @Summary Get a segregation-of-duties rule
+// @Description Get a rule with its roles (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.SoDRule "Rule details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Router /sod/rules/{id} [get]
+func (h *SoDHandler) GetRule(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	// Get rule
+	rule, err := h.sodService.GetRule(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule creates a segregation-of-duties rule
+// @Summary Create a segregation-of-duties rule
+// @Description Create a set of at least two roles no user may hold two of (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule body models.SoDRuleRequest true "Rule details"
+// @Success 201 {object} models.SoDRule "Created rule"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /sod/rules [post]
+func (h *SoDHandler) CreateRule(c *gin.Context) {
+	var request models.SoDRuleRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create rule
+	rule, err := h.sodService.CreateRule(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule updates a segregation-of-duties rule
+// @Summary Update a segregation-of-duties rule
+// @Description Update a rule and replace its roles (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule ID"
+// @Param rule body models.SoDRuleRequest true "Rule details"
+// @Success 200 {object} models.SoDRule "Updated rule"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Router /sod/rules/{id} [put]
+func (h *SoDHandler) UpdateRule(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	var request models.SoDRuleRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updater ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Update rule
+	rule, err := h.sodService.UpdateRule(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule deletes a segregation-of-duties rule
+// @Summary Delete a segregation-of-duties rule
+// @Description Delete a rule together with its exceptions (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /sod/rules/{id} [delete]
+func (h *SoDHandler) DeleteRule(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	// Delete rule
+	err = h.sodService.DeleteRule(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rule deleted successfully"})
+}
+
+// ListExceptions lists segregation-of-duties exceptions
+// @Summary List segregation-of-duties exceptions
+// @Description List exceptions allowing users to hold conflicting roles (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Filter by user"
+// @Param active query bool false "Only exceptions that have not expired"
+// @Success 200 {array} models.SoDException "List of exceptions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /sod/exceptions [get]
+func (h *SoDHandler) ListExceptions(c *gin.Context) {
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	activeOnly, _ := strconv.ParseBool(c.Query("active"))
+
+	exceptions, err := h.sodService.ListExceptions(uint(userID), activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exceptions)
+}
+
+// CreateException grants a segregation-of-duties exception
+// @Summary Grant a segregation-of-duties exception
+// @Description Allow a user to hold conflicting roles of a rule until the exception expires (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param exception body models.SoDExceptionRequest true "Exception details"
+// @Success 201 {object} models.SoDException "Created exception"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /sod/exceptions [post]
+func (h *SoDHandler) CreateException(c *gin.Context) {
+	var request models.SoDExceptionRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create exception
+	exception, err := h.sodService.CreateException(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, exception)
+}
+
+// DeleteException revokes a segregation-of-duties exception
+// @Summary Revoke a segregation-of-duties exception
+// @Description Revoke an exception before it expires (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Exception ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Exception not found"
+// @Router /sod/exceptions/{id} [delete]
+func (h *SoDHandler) DeleteException(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exception ID"})
+		return
+	}
+
+	// Delete exception
+	err = h.sodService.DeleteException(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exception not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exception revoked successfully"})
+}
+
+// ScanViolations scans role assignments for segregation-of-duties violations
+// @Summary Scan for segregation-of-duties violations
+// @Description List active users holding conflicting roles without a valid exception (admin only)
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SoDViolation "List of violations"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /sod/violations [get]
+func (h *SoDHandler) ScanViolations(c *gin.Context) {
+	violations, err := h.sodService.ScanViolations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, violations)
+}
+
+// RegisterRoutes registers the segregation-of-duties routes
+func (h *SoDHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	sodGroup := router.Group("/sod")
+	sodGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		sodGroup.GET("/rules", h.ListRules)
+		sodGroup.POST("/rules", h.CreateRule)
+		sodGroup.GET("/rules/:id", h.GetRule)
+		sodGroup.PUT("/rules/:id", h.UpdateRule)
+		sodGroup.DELETE("/rules/:id", h.DeleteRule)
+		sodGroup.GET("/exceptions", h.ListExceptions)
+		sodGroup.POST("/exceptions", h.CreateException)
+		sodGroup.DELETE("/exceptions/:id", h.DeleteException)
+		sodGroup.GET("/violations", h.ScanViolations)
+	}
+}
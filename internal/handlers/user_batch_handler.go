@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserBatchHandler handles user batch HTTP requests
+type UserBatchHandler struct {
+	userBatchService *services.UserBatchService
+}
+
+// NewUserBatchHandler creates a new user batch handler
+func NewUserBatchHandler(userBatchService *services.UserBatchService) *UserBatchHandler {
+	return &UserBatchHandler{
+		userBatchService: userBatchService,
+	}
+}
+
+// Run applies a batch of operations to many users
+// @Summary Change many users at once
+// @Description Apply operations (update, add_role, remove_role, deactivate) in order to the users given by user_ids or matching filter, as one update per user validated like PUT /users/{id}. Changes that need approval are submitted as change requests. In all_or_nothing mode (the default) any failure rolls back every user and returns 400 with the per-user results; in best_effort mode each user is changed on its own (admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param batch body models.UserBatchRequest true "Target users, operations and mode"
+// @Success 200 {object} models.UserBatchResponse "Per-user results"
+// @Failure 400 {object} models.UserBatchResponse "Invalid batch, or all_or_nothing batch rolled back"
+// @Router /users/batch [post]
+func (h *UserBatchHandler) Run(c *gin.Context) {
+	var request models.UserBatchRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get requester ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	// Run batch
+	response, err := h.userBatchService.Run(&request, userID.(uint), employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if response.Error != "" {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RegisterRoutes registers the user batch routes
+func (h *UserBatchHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	userGroup := router.Group("/users")
+	userGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		userGroup.POST("/batch", h.Run)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,29 +9,36 @@ import (
 	"admin-dashboard/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	userService *services.UserService
+	userService     *services.UserService
+	sessionService  *services.SessionService
+	approvalService *services.ApprovalService
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService *services.UserService) *UserHandler {
+func NewUserHandler(userService *services.UserService, sessionService *services.SessionService, approvalService *services.ApprovalService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:     userService,
+		sessionService:  sessionService,
+		approvalService: approvalService,
 	}
 }
 
 // Get gets a user by ID
 // @Summary Get a user by ID
-// @Description Get a user by their ID
+// @Description Get a user by their ID. The response carries an ETag, and If-None-Match returns 304 when it is unchanged
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "User ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.UserResponse "User details"
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string "Server error"
@@ -50,7 +58,7 @@ func (h *UserHandler) Get(c *gin.Context) {
 		return
 	}
 	
-	c.JSON(http.StatusOK, user)
+	writeVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // Create creates a new user
@@ -63,6 +71,7 @@ func (h *UserHandler) Get(c *gin.Context) {
 // @Param user body models.CreateUserRequest true "User details"
 // @Success 201 {object} models.UserResponse "Created user"
 // @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 409 {object} map[string]string "Conflicting roles"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /users [post]
 func (h *UserHandler) Create(c *gin.Context) {
@@ -83,7 +92,11 @@ func (h *UserHandler) Create(c *gin.Context) {
 	
 	// Create user
 	user, err := h.userService.Create(&request, employeeID.(string))
-	if err != nil {
+	var conflictErr *services.RoleConflictError
+	if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -93,16 +106,21 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 // Update updates a user
 // @Summary Update a user
-// @Description Update a user with the provided details
+// @Description Update a user with the provided details; sensitive changes are held as a change request until approved. If-Match with the ETag from GET rejects the update when the user changed since
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "User ID"
+// @Param If-Match header string false "ETag the update is based on"
 // @Param user body models.UpdateUserRequest true "User details"
 // @Success 200 {object} models.UserResponse "Updated user"
+// @Success 202 {object} models.ChangeRequestResponse "Change request awaiting approval"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "User not found"
+// @Failure 409 {object} map[string]string "Conflicting roles"
+// @Failure 412 {object} map[string]string "User was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
@@ -121,6 +139,56 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 	
+	h.update(c, uint(id), &request)
+}
+
+// Patch partially updates a user
+// @Summary Partially update a user
+// @Description Update a user with a JSON merge patch (RFC 7396): fields left out are unchanged, and null clears phone, address, birthdate, profile_image, division_id, position_id, manager_id, custom_fields or role_ids. Changes are validated and held for approval like PUT. If-Match with the ETag from GET rejects the update when the user changed since
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param user body object true "Merge patch of user fields"
+// @Success 200 {object} models.UserResponse "Updated user"
+// @Success 202 {object} models.ChangeRequestResponse "Change request awaiting approval"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 409 {object} map[string]string "Conflicting roles"
+// @Failure 412 {object} map[string]string "User was modified"
+// @Failure 428 {object} map[string]string "If-Match header is required"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id} [patch]
+func (h *UserHandler) Patch(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	
+	// Read merge patch
+	patch, err := readMergePatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	// Convert the patch into an update request
+	request, err := newUserPatchRequest(patch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	h.update(c, uint(id), request)
+}
+
+// update applies an update to a user on behalf of the current user, or holds it for
+// approval, unless the request's If-Match names another version
+func (h *UserHandler) update(c *gin.Context, id uint, request *models.UpdateUserRequest) {
 	// Get updater ID from context
 	employeeID, exists := c.Get("employeeID")
 	if !exists {
@@ -128,14 +196,51 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 	
-	// Update user
-	user, err := h.userService.Update(uint(id), &request, employeeID.(string))
+	userID, _ := c.Get("userID")
+	
+	// Check the version the client's changes are based on
+	current, err := h.userService.Get(id)
 	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if !checkIfMatch(c, current.Version) {
+		return
+	}
+	request.Version = &current.Version
+	
+	// Hold sensitive changes for approval
+	changeRequest, err := h.approvalService.Submit(id, request, userID.(uint), employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if errors.Is(err, services.ErrVersionConflict) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if changeRequest != nil {
+		c.JSON(http.StatusAccepted, changeRequest)
+		return
+	}
 	
-	c.JSON(http.StatusOK, user)
+	// Update user
+	user, err := h.userService.Update(id, request, employeeID.(string))
+	var conflictErr *services.RoleConflictError
+	if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, services.ErrVersionConflict) {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	writeVersioned(c, http.StatusOK, user.Version, user)
 }
 
 // Delete deletes a user
@@ -178,7 +283,9 @@ func (h *UserHandler) Delete(c *gin.Context) {
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Page size (default: 10)"
 // @Param search query string false "Search term"
+// @Param custom_fields query object false "Custom field filters, e.g. custom_fields[shirt_size]=L"
 // @Success 200 {object} models.PaginatedResponse "List of users"
+// @Failure 400 {object} map[string]string "Unknown custom field"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /users [get]
 func (h *UserHandler) List(c *gin.Context) {
@@ -188,8 +295,11 @@ func (h *UserHandler) List(c *gin.Context) {
 	search := c.Query("search")
 	
 	// Get users
-	users, err := h.userService.List(page, limit, search)
-	if err != nil {
+	users, err := h.userService.List(page, limit, search, c.QueryMap("custom_fields"))
+	if errors.Is(err, services.ErrUnknownCustomField) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -197,15 +307,191 @@ func (h *UserHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, users)
 }
 
+// ListLogins lists the login history of a user
+// @Summary List a user's login history
+// @Description List successful and failed logins of a user (admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Page size (default: 10)"
+// @Success 200 {object} models.PaginatedResponse "Login history"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/logins [get]
+func (h *UserHandler) ListLogins(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	
+	// Get login history
+	logins, err := h.sessionService.ListLogins(uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, logins)
+}
+
+// ListRoles lists a user's role assignments
+// @Summary List a user's role assignments
+// @Description List the roles assigned to a user with their validity windows
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.RoleGrantResponse "Role assignments"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/{id}/roles [get]
+func (h *UserHandler) ListRoles(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	
+	// Get role assignments
+	grants, err := h.userService.ListRoleGrants(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	
+	c.JSON(http.StatusOK, grants)
+}
+
+// GrantRole assigns a role to a user
+// @Summary Assign a role to a user
+// @Description Assign a role to a user, optionally limited to a validity window (valid_from/valid_until) (admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param grant body models.RoleGrantRequest true "Role assignment"
+// @Success 201 {array} models.RoleGrantResponse "Role assignments"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Role requires approval"
+// @Failure 409 {object} map[string]string "Conflicting roles"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/roles [post]
+func (h *UserHandler) GrantRole(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	
+	var request models.RoleGrantRequest
+	
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	
+	// Roles that need approval must go through PUT /users/{id}
+	requiresApproval, err := h.approvalService.RoleRequiresApproval(request.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if requiresApproval {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Granting this role requires approval, submit it through PUT /api/users/{id}"})
+		return
+	}
+	
+	// Assign role
+	grants, err := h.userService.GrantRole(uint(id), &request, employeeID.(string))
+	var conflictErr *services.RoleConflictError
+	if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusCreated, grants)
+}
+
+// RevokeRole removes a role from a user
+// @Summary Remove a role from a user
+// @Description Remove a role assignment from a user (admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param roleId path int true "Role ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /users/{id}/roles/{roleId} [delete]
+func (h *UserHandler) RevokeRole(c *gin.Context) {
+	// Parse IDs from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	
+	roleID, err := strconv.ParseUint(c.Param("roleId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+	
+	// Remove role
+	err = h.userService.RevokeRole(uint(id), uint(roleID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	
+	c.JSON(http.StatusOK, gin.H{"message": "Role removed successfully"})
+}
+
 // RegisterRoutes registers the user routes
-func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc) {
+func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc, ifMatchMiddleware *gin.HandlerFunc) {
 	userGroup := router.Group("/users")
 	userGroup.Use(*authMiddleware) // Apply auth middleware
 	{
 		userGroup.GET("", h.List)
 		userGroup.POST("", h.Create)
 		userGroup.GET("/:id", h.Get)
-		userGroup.PUT("/:id", h.Update)
+		userGroup.PUT("/:id", *ifMatchMiddleware, h.Update)
+		userGroup.PATCH("/:id", *ifMatchMiddleware, h.Patch)
 		userGroup.DELETE("/:id", h.Delete)
+		userGroup.GET("/:id/logins", *adminMiddleware, h.ListLogins)
+		userGroup.GET("/:id/roles", h.ListRoles)
+		userGroup.POST("/:id/roles", *adminMiddleware, h.GrantRole)
+		userGroup.DELETE("/:id/roles/:roleId", *adminMiddleware, h.RevokeRole)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// List lists webhook subscriptions
+// @Summary List webhook subscriptions
+// @Description List webhook subscriptions; secrets are never returned (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.WebhookSubscription "List of subscriptions"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	subscriptions, err := h.webhookService.ListSubscriptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// Get gets a webhook subscription by ID
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription by ID (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscription "Subscription details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) Get(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription ID"})
+		return
+	}
+
+	// Get subscription
+	subscription, err := h.webhookService.GetSubscription(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// Create creates a webhook subscription
+// @Summary Create a webhook subscription
+// @Description Subscribe a URL to events; use "*" to receive every event. Deliveries are signed with the secret (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscription body models.WebhookSubscriptionRequest true "Subscription details"
+// @Success 201 {object} models.WebhookSubscription "Created subscription"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var request models.WebhookSubscriptionRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get creator ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Create subscription
+	subscription, err := h.webhookService.CreateSubscription(&request, employeeID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// Update updates a webhook subscription
+// @Summary Update a webhook subscription
+// @Description Update a webhook subscription; an empty secret keeps the current one (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param subscription body models.WebhookSubscriptionRequest true "Subscription details"
+// @Success 200 {object} models.WebhookSubscription "Updated subscription"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription ID"})
+		return
+	}
+
+	var request models.WebhookSubscriptionRequest
+
+	// Bind JSON to request struct
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updater ID from context
+	employeeID, exists := c.Get("employeeID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Update subscription
+	subscription, err := h.webhookService.UpdateSubscription(uint(id), &request, employeeID.(string))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// Delete deletes a webhook subscription
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription together with its delivery log (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription ID"})
+		return
+	}
+
+	// Delete subscription
+	err = h.webhookService.DeleteSubscription(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Subscription deleted successfully"})
+}
+
+// ListDeliveries lists the delivery log of a webhook subscription
+// @Summary List webhook deliveries
+// @Description List a subscription's deliveries, newest first (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param status query string false "Filter by status (pending, retrying, delivered, dead)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Page size (default: 10)"
+// @Success 200 {object} models.PaginatedResponse "List of deliveries"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subscription ID"})
+		return
+	}
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status")
+
+	// List deliveries
+	deliveries, err := h.webhookService.ListDeliveries(uint(id), status, page, limit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// GetDelivery gets a webhook delivery with its attempts
+// @Summary Get a webhook delivery
+// @Description Get a delivery with its payload and every attempt made (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param deliveryId path int true "Delivery ID"
+// @Success 200 {object} models.WebhookDelivery "Delivery details"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Delivery not found"
+// @Router /webhooks/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	// Get delivery
+	delivery, err := h.webhookService.GetDelivery(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver queues a webhook delivery to be sent again
+// @Summary Redeliver a webhook
+// @Description Send a delivery again with a fresh retry budget, including dead-lettered ones (admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202 {object} models.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Delivery not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /webhooks/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	// Parse ID from URL
+	id, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	// Queue delivery
+	delivery, err := h.webhookService.Redeliver(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// RegisterRoutes registers the webhook routes
+func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware *gin.HandlerFunc, adminMiddleware *gin.HandlerFunc) {
+	webhookGroup := router.Group("/webhooks")
+	webhookGroup.Use(*authMiddleware, *adminMiddleware) // Apply auth and admin middleware
+	{
+		webhookGroup.GET("", h.List)
+		webhookGroup.POST("", h.Create)
+		webhookGroup.GET("/deliveries/:deliveryId", h.GetDelivery)
+		webhookGroup.POST("/deliveries/:deliveryId/redeliver", h.Redeliver)
+		webhookGroup.GET("/:id", h.Get)
+		webhookGroup.PUT("/:id", h.Update)
+		webhookGroup.DELETE("/:id", h.Delete)
+		webhookGroup.GET("/:id/deliveries", h.ListDeliveries)
+	}
+}
@@ -1,24 +1,34 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"log"
 	"net/http"
 	"strings"
 
+	"admin-dashboard/internal/models"
 	"admin-dashboard/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// SessionChecker reports whether the session behind a token is still active
+type SessionChecker interface {
+	IsActive(id uuid.UUID) (bool, error)
+}
+
 // AuthMiddleware represents the authentication middleware
 type AuthMiddleware struct {
-	jwtManager *utils.JWTManager
+	jwtManager     *utils.JWTManager
+	sessionChecker SessionChecker
 }
 
 // NewAuthMiddleware creates a new authentication middleware
-func NewAuthMiddleware(jwtManager *utils.JWTManager) *AuthMiddleware {
+func NewAuthMiddleware(jwtManager *utils.JWTManager, sessionChecker SessionChecker) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtManager: jwtManager,
+		jwtManager:     jwtManager,
+		sessionChecker: sessionChecker,
 	}
 }
 
@@ -55,13 +65,34 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		// Reject tokens whose session was revoked (tokens issued before sessions existed carry no ID)
+		if claims.ID != "" {
+			active := false
+			sessionID, err := uuid.Parse(claims.ID)
+			if err == nil {
+				active, err = m.sessionChecker.IsActive(sessionID)
+			}
+			if err != nil || !active {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked or expired"})
+				c.Abort()
+				return
+			}
+		}
+
 		// Set the user in the context
+		c.Set("sessionID", claims.ID)
 		c.Set("userID", claims.UserID)
 		c.Set("uid", claims.UID)
 		c.Set("employeeID", claims.EmployeeID)
 		c.Set("email", claims.Email)
 		c.Set("roles", claims.Roles)
 
+		// Mark requests made while impersonating
+		if claims.ImpersonatorID != 0 {
+			c.Set("impersonatorID", claims.ImpersonatorID)
+			c.Set("impersonatorEmployeeID", claims.ImpersonatorEmployeeID)
+		}
+
 		c.Next()
 	}
 }
@@ -90,4 +121,26 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
 		c.Abort()
 	}
-}
\ No newline at end of file
+}
+
+// SCIMAuth authenticates identity provider requests using a dedicated bearer token
+func SCIMAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		provided := strings.TrimPrefix(authHeader, "Bearer ")
+
+		// Reject everything when no token is configured
+		if token == "" || provided == authHeader ||
+			subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, models.SCIMErrorResponse{
+				Schemas: []string{models.SCIMSchemaError},
+				Status:  "401",
+				Detail:  "Invalid or missing SCIM bearer token",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
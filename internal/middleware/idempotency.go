@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength limits the length of an Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyStore persists the responses of requests made with an Idempotency-Key
+type IdempotencyStore interface {
+	Begin(record *models.IdempotencyKey) (*models.IdempotencyKey, error)
+	Complete(record *models.IdempotencyKey) error
+	Release(record *models.IdempotencyKey) error
+}
+
+// responseRecorder keeps a copy of the response body written through it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes to the response and the copy
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes to the response and the copy
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency is a middleware function that makes authenticated POST requests with an
+// Idempotency-Key header safe to retry. The first response under a key is stored for ttl and
+// replayed for retries of the same request; reusing the key for a different request returns 422.
+// Bodies over maxBodySize bytes are rejected with 413. Server errors, authentication failures
+// and responses marked no-store, such as those carrying tokens, are not stored, so those
+// requests can be retried.
+func Idempotency(store IdempotencyStore, jwtManager *utils.JWTManager, ttl time.Duration, maxBodySize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		// Anonymous requests share no scope the key could be kept in
+		scope, ok := idempotencyScope(c, jwtManager)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+
+		// Read the body, leaving it in place for the handler
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Request body exceeds %d bytes allowed with an Idempotency-Key", maxBodySize)})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Fingerprint the request
+		fingerprint := sha256.New()
+		fingerprint.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+		fingerprint.Write(body)
+
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: hex.EncodeToString(fingerprint.Sum(nil)),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		existing, err := store.Begin(record)
+		if err != nil {
+			log.Printf("Failed to reserve idempotency key: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			case existing.CompletedAt == nil:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+			default:
+				// Replay the stored response
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.StatusCode, existing.ContentType, []byte(existing.ResponseBody))
+				c.Abort()
+			}
+			return
+		}
+
+		// Release the key when the request fails, is not authenticated, must not be stored or
+		// panics, so that it can be retried
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := store.Release(record); err != nil {
+				log.Printf("Failed to release idempotency key %q: %v", key, err)
+			}
+		}()
+
+		// Process request, keeping a copy of the response
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError || recorder.Status() == http.StatusUnauthorized ||
+			strings.Contains(recorder.Header().Get("Cache-Control"), "no-store") {
+			return
+		}
+
+		stored = true
+		record.StatusCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.String()
+		if err := store.Complete(record); err != nil {
+			log.Printf("Failed to store response of idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// idempotencyScope scopes keys to the authenticated user, so a client that refreshes its token
+// between retries still finds its key. Requests without a valid user token, such as SCIM
+// requests, are scoped to a hash of their Authorization header, and requests without one have
+// no scope.
+func idempotencyScope(c *gin.Context, jwtManager *utils.JWTManager) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return "", false
+	}
+
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		if claims, err := jwtManager.ValidateToken(token); err == nil {
+			scope := fmt.Sprintf("user:%d", claims.UserID)
+			if claims.ImpersonatorID != 0 {
+				scope += fmt.Sprintf(":impersonator:%d", claims.ImpersonatorID)
+			}
+			return scope, true
+		}
+	}
+
+	sum := sha256.Sum256([]byte(header))
+	return "authorization:" + hex.EncodeToString(sum[:]), true
+}
@@ -5,9 +5,16 @@ import (
 	"net/http"
 	"time"
 
+	"admin-dashboard/internal/models"
+
 	"github.com/gin-gonic/gin"
 )
 
+// AuditRecorder persists audit log entries
+type AuditRecorder interface {
+	Record(entry *models.AuditLog) error
+}
+
 // Logger is a middleware function that logs the request method, path, and time
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -28,17 +35,61 @@ func Logger() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		statusCode := c.Writer.Status()
 
-		// Log request details
+		// Log request details, marking requests made while impersonating
+		if impersonator, ok := c.Get("impersonatorEmployeeID"); ok {
+			log.Printf("[%s] %s %s %d %s [IMPERSONATED by %s as %s]", method, path, latency, statusCode, c.ClientIP(), impersonator, c.GetString("employeeID"))
+			return
+		}
 		log.Printf("[%s] %s %s %d %s", method, path, latency, statusCode, c.ClientIP())
 	}
 }
 
+// Audit is a middleware function that records every authenticated mutating request,
+// and every request made while impersonating, in the audit log
+func Audit(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Process request
+		c.Next()
+
+		// Only authenticated requests can be attributed
+		userID, authenticated := c.Get("userID")
+		if !authenticated {
+			return
+		}
+
+		impersonatorID, impersonating := c.Get("impersonatorID")
+		if !impersonating && (c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead) {
+			return
+		}
+
+		entry := &models.AuditLog{
+			UserID:     userID.(uint),
+			EmployeeID: c.GetString("employeeID"),
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			StatusCode: c.Writer.Status(),
+			IPAddress:  c.ClientIP(),
+		}
+
+		if impersonating {
+			id := impersonatorID.(uint)
+			entry.ImpersonatorID = &id
+			entry.ImpersonatorEmployeeID = c.GetString("impersonatorEmployeeID")
+		}
+
+		if err := recorder.Record(entry); err != nil {
+			log.Printf("Failed to record audit entry for %s %s: %v", entry.Method, entry.Path, err)
+		}
+	}
+}
+
 // CORS is a middleware function that adds CORS headers to the response
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Last-Event-ID, If-Match, If-None-Match, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Idempotent-Replayed")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		// Handle preflight requests
@@ -51,6 +102,20 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
+// RequireIfMatch is a middleware function that rejects requests without an If-Match header
+// with 428 Precondition Required when required is set, so that clients cannot overwrite
+// changes they have not seen
+func RequireIfMatch(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && c.GetHeader("If-Match") == "" {
+			c.AbortWithStatusJSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // ErrorHandler is a middleware function that handles errors
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Access review campaign statuses
+const (
+	AccessReviewStatusOpen   = "open"
+	AccessReviewStatusClosed = "closed"
+)
+
+// Access review item decisions
+const (
+	AccessReviewDecisionPending  = "pending"
+	AccessReviewDecisionApproved = "approved"
+	AccessReviewDecisionRevoked  = "revoked"
+)
+
+// AccessReviewCampaign represents the access_review_campaigns table
+type AccessReviewCampaign struct {
+	ID          uint       `gorm:"primaryKey;column:arc_id" json:"id"`
+	Name        string     `gorm:"column:arc_name" json:"name"`
+	Description string     `gorm:"column:arc_description" json:"description"`
+	RoleID      *uint      `gorm:"column:arc_role_id" json:"role_id"`
+	DivisionID  *uint      `gorm:"column:arc_division_id" json:"division_id"`
+	Status      string     `gorm:"column:arc_status" json:"status"`
+	DueDate     *time.Time `gorm:"column:arc_due_date" json:"due_date"`
+	CreatedAt   time.Time  `gorm:"column:arc_created_at" json:"created_at"`
+	CreatedBy   string     `gorm:"column:arc_created_by" json:"created_by"`
+	ClosedAt    *time.Time `gorm:"column:arc_closed_at" json:"closed_at"`
+	ClosedBy    string     `gorm:"column:arc_closed_by" json:"closed_by,omitempty"`
+	// Relations
+	Role     *Role     `gorm:"foreignKey:arc_role_id;references:role_id" json:"role,omitempty"`
+	Division *Division `gorm:"foreignKey:arc_division_id;references:div_id" json:"division,omitempty"`
+}
+
+// TableName overrides the table name
+func (AccessReviewCampaign) TableName() string {
+	return "\"user\".access_review_campaigns"
+}
+
+// AccessReviewItem represents the access_review_items table (one user-role pair to review)
+type AccessReviewItem struct {
+	ID         uint       `gorm:"primaryKey;column:ari_id" json:"id"`
+	CampaignID uint       `gorm:"column:ari_campaign_id;index" json:"campaign_id"`
+	UserID     uint       `gorm:"column:ari_user_id" json:"user_id"`
+	RoleID     uint       `gorm:"column:ari_role_id" json:"role_id"`
+	ReviewerID *uint      `gorm:"column:ari_reviewer_id;index" json:"reviewer_id"` // the user's manager, nil if none
+	Decision   string     `gorm:"column:ari_decision" json:"decision"`
+	Comment    string     `gorm:"column:ari_comment" json:"comment,omitempty"`
+	DecidedAt  *time.Time `gorm:"column:ari_decided_at" json:"decided_at"`
+	DecidedBy  string     `gorm:"column:ari_decided_by" json:"decided_by,omitempty"`
+	AppliedAt  *time.Time `gorm:"column:ari_applied_at" json:"applied_at"` // when a revocation was applied to user_roles
+	CreatedAt  time.Time  `gorm:"column:ari_created_at" json:"created_at"`
+	// Relations
+	Campaign *AccessReviewCampaign `gorm:"foreignKey:ari_campaign_id;references:arc_id" json:"-"`
+	User     *User                 `gorm:"foreignKey:ari_user_id;references:u_id" json:"user,omitempty"`
+	Role     *Role                 `gorm:"foreignKey:ari_role_id;references:role_id" json:"role,omitempty"`
+	Reviewer *User                 `gorm:"foreignKey:ari_reviewer_id;references:u_id" json:"reviewer,omitempty"`
+}
+
+// TableName overrides the table name
+func (AccessReviewItem) TableName() string {
+	return "\"user\".access_review_items"
+}
+
+// AccessReviewCampaignRequest represents payload for creating an access review campaign
+type AccessReviewCampaignRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	RoleID      *uint  `json:"role_id"`
+	DivisionID  *uint  `json:"division_id"`
+	DueDate     string `json:"due_date"` // YYYY-MM-DD
+}
+
+// AccessReviewDecisionRequest represents payload for deciding on an access review item
+type AccessReviewDecisionRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve revoke"`
+	Comment  string `json:"comment"`
+}
+
+// AccessReviewCampaignResponse represents a campaign with its progress
+type AccessReviewCampaignResponse struct {
+	AccessReviewCampaign
+	TotalItems    int64 `json:"total_items"`
+	PendingItems  int64 `json:"pending_items"`
+	ApprovedItems int64 `json:"approved_items"`
+	RevokedItems  int64 `json:"revoked_items"`
+}
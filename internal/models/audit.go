@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AuditLog represents the audit_logs table (one row per audited request)
+type AuditLog struct {
+	ID                     uint      `gorm:"primaryKey;column:al_id" json:"id"`
+	UserID                 uint      `gorm:"column:al_user_id;index" json:"user_id"`
+	EmployeeID             string    `gorm:"column:al_employee_id" json:"employee_id"`
+	ImpersonatorID         *uint     `gorm:"column:al_impersonator_id;index" json:"impersonator_id,omitempty"`
+	ImpersonatorEmployeeID string    `gorm:"column:al_impersonator_employee_id" json:"impersonator_employee_id,omitempty"`
+	Method                 string    `gorm:"column:al_method" json:"method"`
+	Path                   string    `gorm:"column:al_path" json:"path"`
+	StatusCode             int       `gorm:"column:al_status_code" json:"status_code"`
+	IPAddress              string    `gorm:"column:al_ip_address" json:"ip_address"`
+	CreatedAt              time.Time `gorm:"column:al_created_at" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (AuditLog) TableName() string {
+	return "\"user\".audit_logs"
+}
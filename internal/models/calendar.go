@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Calendar event types
+const (
+	CalendarEventBirthday        = "birthday"
+	CalendarEventWorkAnniversary = "work_anniversary"
+	CalendarEventProbationEnd    = "probation_end"
+)
+
+// CalendarEvent represents a birthday, work anniversary or probation end of an active user
+type CalendarEvent struct {
+	Date       time.Time `json:"date"`
+	Type       string    `json:"type"`
+	Years      int       `json:"years,omitempty"` // for work anniversaries
+	UserID     uint      `json:"user_id"`
+	EmployeeID string    `json:"employee_id"`
+	Name       string    `json:"name"`
+	Division   string    `json:"division"`
+}
+
+// CalendarFeed represents the calendar_feeds table (a manager's iCalendar subscription)
+type CalendarFeed struct {
+	ID        uint      `gorm:"primaryKey;column:cf_id" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex;column:cf_user_id" json:"user_id"`
+	TokenHash string    `gorm:"uniqueIndex;column:cf_token_hash" json:"-"` // SHA-256 of the token in the feed URL
+	CreatedAt time.Time `gorm:"column:cf_created_at" json:"created_at"`
+	CreatedBy string    `gorm:"column:cf_created_by" json:"created_by"`
+	// Relations
+	User *User `gorm:"foreignKey:cf_user_id;references:u_id" json:"-"`
+}
+
+// TableName overrides the table name
+func (CalendarFeed) TableName() string {
+	return "\"user\".calendar_feeds"
+}
+
+// CalendarFeedResponse represents a newly created calendar feed; the URL is only shown once
+type CalendarFeedResponse struct {
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Change request statuses
+const (
+	ChangeRequestStatusPending  = "pending"
+	ChangeRequestStatusApproved = "approved"
+	ChangeRequestStatusRejected = "rejected"
+)
+
+// ChangeRequest represents the change_requests table (a sensitive user update awaiting approval)
+type ChangeRequest struct {
+	ID              uint       `gorm:"primaryKey;column:cr_id" json:"id"`
+	TargetUserID    uint       `gorm:"column:cr_target_user_id;index" json:"target_user_id"`
+	Payload         string     `gorm:"column:cr_payload;type:jsonb" json:"-"` // the proposed UpdateUserRequest
+	Reasons         string     `gorm:"column:cr_reasons" json:"reasons"`
+	Status          string     `gorm:"column:cr_status;index" json:"status"`
+	ApproverID      *uint      `gorm:"column:cr_approver_id" json:"approver_id"`               // set by the manager rule
+	ApproverRole    string     `gorm:"column:cr_approver_role" json:"approver_role,omitempty"` // set by the role rule
+	RequestedByID   uint       `gorm:"column:cr_requested_by_id" json:"requested_by_id"`
+	RequestedBy     string     `gorm:"column:cr_requested_by" json:"requested_by"`
+	CreatedAt       time.Time  `gorm:"column:cr_created_at" json:"created_at"`
+	DecidedAt       *time.Time `gorm:"column:cr_decided_at" json:"decided_at"`
+	DecidedBy       string     `gorm:"column:cr_decided_by" json:"decided_by,omitempty"`
+	DecisionComment string     `gorm:"column:cr_decision_comment" json:"decision_comment,omitempty"`
+	// Relations
+	TargetUser *User                  `gorm:"foreignKey:cr_target_user_id;references:u_id" json:"target_user,omitempty"`
+	Comments   []ChangeRequestComment `gorm:"foreignKey:crc_change_request_id;references:cr_id" json:"comments,omitempty"`
+}
+
+// TableName overrides the table name
+func (ChangeRequest) TableName() string {
+	return "\"user\".change_requests"
+}
+
+// ChangeRequestComment represents the change_request_comments table
+type ChangeRequestComment struct {
+	ID              uint      `gorm:"primaryKey;column:crc_id" json:"id"`
+	ChangeRequestID uint      `gorm:"column:crc_change_request_id;index" json:"change_request_id"`
+	AuthorID        uint      `gorm:"column:crc_author_id" json:"author_id"`
+	Author          string    `gorm:"column:crc_author" json:"author"`
+	Body            string    `gorm:"column:crc_body" json:"body"`
+	CreatedAt       time.Time `gorm:"column:crc_created_at" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (ChangeRequestComment) TableName() string {
+	return "\"user\".change_request_comments"
+}
+
+// ChangeRequestResponse represents a change request with its proposed changes
+type ChangeRequestResponse struct {
+	ChangeRequest
+	Changes *UpdateUserRequest `json:"changes"`
+}
+
+// ChangeRequestDecisionRequest represents payload for approving or rejecting a change request
+type ChangeRequestDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ChangeRequestCommentRequest represents payload for commenting on a change request
+type ChangeRequestCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import "time"
+
+// Checklist kinds
+const (
+	ChecklistOnboarding  = "onboarding"  // created when a user is created
+	ChecklistOffboarding = "offboarding" // created when a user is deactivated
+)
+
+// ChecklistTemplate represents the checklist_templates table (tasks to run for new or leaving users)
+type ChecklistTemplate struct {
+	ID         uint      `gorm:"primaryKey;column:ct_id" json:"id"`
+	Name       string    `gorm:"column:ct_name" json:"name"`
+	Kind       string    `gorm:"column:ct_kind;index" json:"kind"`
+	DivisionID *uint     `gorm:"column:ct_division_id" json:"division_id"` // nil applies to every division
+	PositionID *uint     `gorm:"column:ct_position_id" json:"position_id"` // nil applies to every position
+	IsActive   bool      `gorm:"default:true;column:ct_is_active" json:"is_active"`
+	CreatedAt  time.Time `gorm:"column:ct_created_at" json:"created_at"`
+	CreatedBy  string    `gorm:"column:ct_created_by" json:"created_by"`
+	UpdatedAt  time.Time `gorm:"column:ct_updated_at" json:"updated_at"`
+	UpdatedBy  string    `gorm:"column:ct_updated_by" json:"updated_by"`
+	// Relations
+	Division *Division               `gorm:"foreignKey:ct_division_id;references:div_id" json:"division,omitempty"`
+	Position *Position               `gorm:"foreignKey:ct_position_id;references:pos_id" json:"position,omitempty"`
+	Tasks    []ChecklistTemplateTask `gorm:"foreignKey:ctt_template_id;references:ct_id" json:"tasks"`
+}
+
+// TableName overrides the table name
+func (ChecklistTemplate) TableName() string {
+	return "\"user\".checklist_templates"
+}
+
+// ChecklistTemplateTask represents the checklist_template_tasks table (a task of a template)
+type ChecklistTemplateTask struct {
+	ID              uint   `gorm:"primaryKey;column:ctt_id" json:"id"`
+	TemplateID      uint   `gorm:"column:ctt_template_id;index" json:"-"`
+	Title           string `gorm:"column:ctt_title" json:"title"`
+	RoleID          *uint  `gorm:"column:ctt_role_id" json:"role_id"`                                   // role whose holders do the task
+	AssignToManager bool   `gorm:"default:false;column:ctt_assign_to_manager" json:"assign_to_manager"` // the user's manager does the task
+	DueDays         int    `gorm:"column:ctt_due_days" json:"due_days"`                                 // days after the join or deactivation date, may be negative
+	Position        int    `gorm:"column:ctt_position" json:"position"`
+	// Relations
+	Role *Role `gorm:"foreignKey:ctt_role_id;references:role_id" json:"role,omitempty"`
+}
+
+// TableName overrides the table name
+func (ChecklistTemplateTask) TableName() string {
+	return "\"user\".checklist_template_tasks"
+}
+
+// Checklist represents the checklists table (a template instantiated for one user)
+type Checklist struct {
+	ID          uint       `gorm:"primaryKey;column:cl_id" json:"id"`
+	UserID      uint       `gorm:"column:cl_user_id;index" json:"user_id"`
+	TemplateID  *uint      `gorm:"column:cl_template_id" json:"template_id"` // nil once the template is deleted
+	Kind        string     `gorm:"column:cl_kind" json:"kind"`
+	Name        string     `gorm:"column:cl_name" json:"name"`
+	CompletedAt *time.Time `gorm:"column:cl_completed_at" json:"completed_at"` // set when every task is done
+	CreatedAt   time.Time  `gorm:"column:cl_created_at" json:"created_at"`
+	// Relations
+	Tasks []ChecklistTask `gorm:"foreignKey:clt_checklist_id;references:cl_id" json:"tasks"`
+}
+
+// TableName overrides the table name
+func (Checklist) TableName() string {
+	return "\"user\".checklists"
+}
+
+// ChecklistTask represents the checklist_tasks table (a task to do for one user)
+type ChecklistTask struct {
+	ID             uint       `gorm:"primaryKey;column:clt_id" json:"id"`
+	ChecklistID    uint       `gorm:"column:clt_checklist_id;index" json:"checklist_id"`
+	Title          string     `gorm:"column:clt_title" json:"title"`
+	RoleID         *uint      `gorm:"column:clt_role_id;index" json:"role_id"`
+	AssigneeUserID *uint      `gorm:"column:clt_assignee_user_id;index" json:"assignee_user_id"` // the manager, for tasks assigned to the manager
+	DueDate        time.Time  `gorm:"column:clt_due_date;type:date;index" json:"due_date"`
+	Position       int        `gorm:"column:clt_position" json:"position"`
+	CompletedAt    *time.Time `gorm:"column:clt_completed_at" json:"completed_at"`
+	CompletedBy    string     `gorm:"column:clt_completed_by" json:"completed_by,omitempty"`
+	// Relations
+	Role *Role `gorm:"foreignKey:clt_role_id;references:role_id" json:"role,omitempty"`
+}
+
+// TableName overrides the table name
+func (ChecklistTask) TableName() string {
+	return "\"user\".checklist_tasks"
+}
+
+// ChecklistTemplateRequest represents payload for creating/updating a checklist template
+type ChecklistTemplateRequest struct {
+	Name       string                         `json:"name" binding:"required"`
+	Kind       string                         `json:"kind" binding:"required,oneof=onboarding offboarding"`
+	DivisionID *uint                          `json:"division_id"`
+	PositionID *uint                          `json:"position_id"`
+	IsActive   *bool                          `json:"is_active"`
+	Tasks      []ChecklistTemplateTaskRequest `json:"tasks" binding:"required,min=1,dive"`
+}
+
+// ChecklistTemplateTaskRequest represents a task of a checklist template request; set either
+// role_id or assign_to_manager
+type ChecklistTemplateTaskRequest struct {
+	Title           string `json:"title" binding:"required"`
+	RoleID          *uint  `json:"role_id"`
+	AssignToManager bool   `json:"assign_to_manager"`
+	DueDays         int    `json:"due_days"`
+}
+
+// ChecklistTaskItem represents a checklist task with the user it is for, as listed in task reports
+type ChecklistTaskItem struct {
+	ChecklistTask
+	Kind          string `json:"kind"`
+	ChecklistName string `json:"checklist_name"`
+	UserID        uint   `json:"user_id"`
+	EmployeeID    string `json:"employee_id"`
+	UserName      string `json:"user_name"`
+	RoleName      string `json:"role_name,omitempty"`
+	DaysOverdue   int    `json:"days_overdue,omitempty"`
+}
+
+// ChecklistTaskFilter narrows a checklist task listing
+type ChecklistTaskFilter struct {
+	Status     string // open, overdue or completed; empty for every task
+	UserID     uint   // only the tasks of this user's checklists when set
+	Mine       bool   // only the tasks the actor may complete
+	ActorID    uint
+	ActorRoles []string
+}
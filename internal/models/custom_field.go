@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Custom field entities
+const (
+	CustomFieldEntityUser     = "user"
+	CustomFieldEntityDivision = "division"
+	CustomFieldEntityPosition = "position"
+)
+
+// Custom field types
+const (
+	CustomFieldTypeString  = "string"
+	CustomFieldTypeNumber  = "number"
+	CustomFieldTypeBoolean = "boolean"
+	CustomFieldTypeDate    = "date"   // YYYY-MM-DD
+	CustomFieldTypeSelect  = "select" // one of Options
+)
+
+// CustomFieldDefinition represents the custom_field_definitions table (an admin-defined attribute)
+type CustomFieldDefinition struct {
+	ID        uint       `gorm:"primaryKey;column:cfd_id" json:"id"`
+	Entity    string     `gorm:"column:cfd_entity;uniqueIndex:idx_cfd_entity_key" json:"entity"`
+	Key       string     `gorm:"column:cfd_key;uniqueIndex:idx_cfd_entity_key" json:"key"` // name in custom_fields
+	Label     string     `gorm:"column:cfd_label" json:"label"`
+	Type      string     `gorm:"column:cfd_type" json:"type"`
+	Required  bool       `gorm:"default:false;column:cfd_required" json:"required"`
+	Options   StringList `gorm:"column:cfd_options;type:text" json:"options,omitempty"` // allowed values of select fields
+	Pattern   string     `gorm:"column:cfd_pattern" json:"pattern,omitempty"`           // regular expression string values must match
+	Min       *float64   `gorm:"column:cfd_min" json:"min,omitempty"`                   // minimum number, or minimum string length
+	Max       *float64   `gorm:"column:cfd_max" json:"max,omitempty"`                   // maximum number, or maximum string length
+	CreatedAt time.Time  `gorm:"column:cfd_created_at" json:"created_at"`
+	CreatedBy string     `gorm:"column:cfd_created_by" json:"created_by"`
+	UpdatedAt time.Time  `gorm:"column:cfd_updated_at" json:"updated_at"`
+	UpdatedBy string     `gorm:"column:cfd_updated_by" json:"updated_by"`
+}
+
+// TableName overrides the table name
+func (CustomFieldDefinition) TableName() string {
+	return "\"user\".custom_field_definitions"
+}
+
+// CustomFieldDefinitionRequest represents payload for creating/updating a custom field definition.
+// Entity, key and type cannot change once created.
+type CustomFieldDefinitionRequest struct {
+	Entity   string   `json:"entity" binding:"required,oneof=user division position"`
+	Key      string   `json:"key" binding:"required"`
+	Label    string   `json:"label" binding:"required"`
+	Type     string   `json:"type" binding:"required,oneof=string number boolean date select"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options"`
+	Pattern  string   `json:"pattern"`
+	Min      *float64 `json:"min"`
+	Max      *float64 `json:"max"`
+}
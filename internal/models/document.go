@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Document types
+const (
+	DocumentTypeContract    = "contract"
+	DocumentTypeID          = "id"
+	DocumentTypeCertificate = "certificate"
+	DocumentTypeOther       = "other"
+)
+
+// Document confidentiality levels, from least to most restricted
+const (
+	ConfidentialityPublic       = "public"       // any authenticated user
+	ConfidentialityInternal     = "internal"     // the employee, their manager and HR
+	ConfidentialityConfidential = "confidential" // HR only
+	ConfidentialityRestricted   = "restricted"   // the restricted document roles only
+)
+
+// UserDocument represents the user_documents table (a file attached to a user)
+type UserDocument struct {
+	ID              uint       `gorm:"primaryKey;column:ud_id" json:"id"`
+	UserID          uint       `gorm:"column:ud_user_id;index" json:"user_id"`
+	Type            string     `gorm:"column:ud_type" json:"type"`
+	Name            string     `gorm:"column:ud_name" json:"name"` // original file name
+	ContentType     string     `gorm:"column:ud_content_type" json:"content_type"`
+	Size            int64      `gorm:"column:ud_size" json:"size"`
+	Checksum        string     `gorm:"column:ud_checksum" json:"checksum"` // hex-encoded SHA-256 of the file
+	BlobKey         string     `gorm:"column:ud_blob_key" json:"-"`
+	Confidentiality string     `gorm:"column:ud_confidentiality;default:internal" json:"confidentiality"`
+	ExpiresAt       *time.Time `gorm:"column:ud_expires_at;type:date;index" json:"expires_at"`
+	CreatedAt       time.Time  `gorm:"column:ud_created_at" json:"created_at"`
+	CreatedBy       string     `gorm:"column:ud_created_by" json:"created_by"`
+}
+
+// TableName overrides the table name
+func (UserDocument) TableName() string {
+	return "\"user\".user_documents"
+}
+
+// UserDocumentRequest represents the form fields sent with an uploaded document
+type UserDocumentRequest struct {
+	Type            string `form:"type" binding:"required,oneof=contract id certificate other"`
+	Confidentiality string `form:"confidentiality" binding:"omitempty,oneof=public internal confidential restricted"`
+	ExpiresAt       string `form:"expires_at"` // YYYY-MM-DD
+}
+
+// ExpiringDocument represents a document that expires soon, with its owner
+type ExpiringDocument struct {
+	UserDocument
+	EmployeeID string `json:"employee_id"`
+	UserName   string `json:"user_name"`
+	DaysLeft   int    `json:"days_left"`
+}
@@ -0,0 +1,266 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Event types written to the outbox
+const (
+	EventUserCreated            = "user.created"
+	EventUserUpdated            = "user.updated"
+	EventUserDeactivated        = "user.deactivated"
+	EventUserDeleted            = "user.deleted"
+	EventUserRolesChanged       = "user.roles_changed"
+	EventUserOffboarded         = "user.offboarded"
+	EventRoleCreated            = "role.created"
+	EventRoleUpdated            = "role.updated"
+	EventRoleDeleted            = "role.deleted"
+	EventDivisionCreated        = "division.created"
+	EventDivisionUpdated        = "division.updated"
+	EventDivisionRenamed        = "division.renamed"
+	EventDivisionDeleted        = "division.deleted"
+	EventPositionCreated        = "position.created"
+	EventPositionUpdated        = "position.updated"
+	EventPositionDeleted        = "position.deleted"
+	EventChecklistCreated       = "checklist.created"
+	EventChecklistTaskCompleted = "checklist.task_completed"
+	EventChecklistTaskReopened  = "checklist.task_reopened"
+)
+
+// DomainEvent is a typed change emitted by a service and stored as an outbox event payload
+type DomainEvent interface {
+	EventType() string
+	AggregateID() uint
+}
+
+// eventFactories creates an empty event of each type for decoding outbox payloads
+var eventFactories = map[string]func() DomainEvent{
+	EventUserCreated:            func() DomainEvent { return &UserCreated{} },
+	EventUserUpdated:            func() DomainEvent { return &UserUpdated{} },
+	EventUserDeactivated:        func() DomainEvent { return &UserDeactivated{} },
+	EventUserDeleted:            func() DomainEvent { return &UserDeleted{} },
+	EventUserRolesChanged:       func() DomainEvent { return &UserRolesChanged{} },
+	EventUserOffboarded:         func() DomainEvent { return &UserOffboarded{} },
+	EventRoleCreated:            func() DomainEvent { return &RoleCreated{} },
+	EventRoleUpdated:            func() DomainEvent { return &RoleUpdated{} },
+	EventRoleDeleted:            func() DomainEvent { return &RoleDeleted{} },
+	EventDivisionCreated:        func() DomainEvent { return &DivisionCreated{} },
+	EventDivisionUpdated:        func() DomainEvent { return &DivisionUpdated{} },
+	EventDivisionRenamed:        func() DomainEvent { return &DivisionRenamed{} },
+	EventDivisionDeleted:        func() DomainEvent { return &DivisionDeleted{} },
+	EventPositionCreated:        func() DomainEvent { return &PositionCreated{} },
+	EventPositionUpdated:        func() DomainEvent { return &PositionUpdated{} },
+	EventPositionDeleted:        func() DomainEvent { return &PositionDeleted{} },
+	EventChecklistCreated:       func() DomainEvent { return &ChecklistCreated{} },
+	EventChecklistTaskCompleted: func() DomainEvent { return &ChecklistTaskCompleted{} },
+	EventChecklistTaskReopened:  func() DomainEvent { return &ChecklistTaskReopened{} },
+}
+
+// EventTypes lists every event type that can be subscribed to
+var EventTypes = []string{
+	EventUserCreated,
+	EventUserUpdated,
+	EventUserDeactivated,
+	EventUserDeleted,
+	EventUserRolesChanged,
+	EventUserOffboarded,
+	EventRoleCreated,
+	EventRoleUpdated,
+	EventRoleDeleted,
+	EventDivisionCreated,
+	EventDivisionUpdated,
+	EventDivisionRenamed,
+	EventDivisionDeleted,
+	EventPositionCreated,
+	EventPositionUpdated,
+	EventPositionDeleted,
+	EventChecklistCreated,
+	EventChecklistTaskCompleted,
+	EventChecklistTaskReopened,
+}
+
+// AggregateType returns the kind of record an event type belongs to ("user" for "user.created")
+func AggregateType(eventType string) string {
+	aggregateType, _, _ := strings.Cut(eventType, ".")
+	return aggregateType
+}
+
+// DecodeEvent decodes an outbox payload into its typed event
+func DecodeEvent(eventType string, payload []byte) (DomainEvent, error) {
+	factory, ok := eventFactories[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type: %s", eventType)
+	}
+
+	event := factory()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// UserCreated is emitted when a user is created
+type UserCreated struct {
+	User *UserResponse `json:"user"`
+}
+
+func (e *UserCreated) EventType() string { return EventUserCreated }
+func (e *UserCreated) AggregateID() uint { return e.User.ID }
+
+// UserUpdated is emitted when a user's profile is updated
+type UserUpdated struct {
+	User *UserResponse `json:"user"`
+}
+
+func (e *UserUpdated) EventType() string { return EventUserUpdated }
+func (e *UserUpdated) AggregateID() uint { return e.User.ID }
+
+// UserDeactivated is emitted when an active user is deactivated
+type UserDeactivated struct {
+	User *UserResponse `json:"user"`
+}
+
+func (e *UserDeactivated) EventType() string { return EventUserDeactivated }
+func (e *UserDeactivated) AggregateID() uint { return e.User.ID }
+
+// UserDeleted is emitted when a user is deleted
+type UserDeleted struct {
+	UserID     uint   `json:"user_id"`
+	EmployeeID string `json:"employee_id"`
+	Email      string `json:"email"`
+}
+
+func (e *UserDeleted) EventType() string { return EventUserDeleted }
+func (e *UserDeleted) AggregateID() uint { return e.UserID }
+
+// UserRolesChanged is emitted when the roles a user effectively holds change
+type UserRolesChanged struct {
+	UserID  uint     `json:"user_id"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Roles   []string `json:"roles"`
+}
+
+func (e *UserRolesChanged) EventType() string { return EventUserRolesChanged }
+func (e *UserRolesChanged) AggregateID() uint { return e.UserID }
+
+// UserOffboarded is emitted when a leaver's offboarding is carried out, after the events of the
+// changes it made
+type UserOffboarded struct {
+	Offboarding *Offboarding `json:"offboarding"`
+}
+
+func (e *UserOffboarded) EventType() string { return EventUserOffboarded }
+func (e *UserOffboarded) AggregateID() uint { return e.Offboarding.UserID }
+
+// RoleCreated is emitted when a role is created
+type RoleCreated struct {
+	Role *Role `json:"role"`
+}
+
+func (e *RoleCreated) EventType() string { return EventRoleCreated }
+func (e *RoleCreated) AggregateID() uint { return e.Role.ID }
+
+// RoleUpdated is emitted when a role is updated
+type RoleUpdated struct {
+	Role *Role `json:"role"`
+}
+
+func (e *RoleUpdated) EventType() string { return EventRoleUpdated }
+func (e *RoleUpdated) AggregateID() uint { return e.Role.ID }
+
+// RoleDeleted is emitted when a role is deleted
+type RoleDeleted struct {
+	Role *Role `json:"role"`
+}
+
+func (e *RoleDeleted) EventType() string { return EventRoleDeleted }
+func (e *RoleDeleted) AggregateID() uint { return e.Role.ID }
+
+// DivisionCreated is emitted when a division is created
+type DivisionCreated struct {
+	Division *Division `json:"division"`
+}
+
+func (e *DivisionCreated) EventType() string { return EventDivisionCreated }
+func (e *DivisionCreated) AggregateID() uint { return e.Division.ID }
+
+// DivisionUpdated is emitted when a division is updated
+type DivisionUpdated struct {
+	Division *Division `json:"division"`
+}
+
+func (e *DivisionUpdated) EventType() string { return EventDivisionUpdated }
+func (e *DivisionUpdated) AggregateID() uint { return e.Division.ID }
+
+// DivisionRenamed is emitted alongside DivisionUpdated when a division's code or name changes
+type DivisionRenamed struct {
+	DivisionID uint   `json:"division_id"`
+	OldCode    string `json:"old_code"`
+	NewCode    string `json:"new_code"`
+	OldName    string `json:"old_name"`
+	NewName    string `json:"new_name"`
+}
+
+func (e *DivisionRenamed) EventType() string { return EventDivisionRenamed }
+func (e *DivisionRenamed) AggregateID() uint { return e.DivisionID }
+
+// DivisionDeleted is emitted when a division is deleted
+type DivisionDeleted struct {
+	Division *Division `json:"division"`
+}
+
+func (e *DivisionDeleted) EventType() string { return EventDivisionDeleted }
+func (e *DivisionDeleted) AggregateID() uint { return e.Division.ID }
+
+// PositionCreated is emitted when a position is created
+type PositionCreated struct {
+	Position *Position `json:"position"`
+}
+
+func (e *PositionCreated) EventType() string { return EventPositionCreated }
+func (e *PositionCreated) AggregateID() uint { return e.Position.ID }
+
+// PositionUpdated is emitted when a position is updated
+type PositionUpdated struct {
+	Position *Position `json:"position"`
+}
+
+func (e *PositionUpdated) EventType() string { return EventPositionUpdated }
+func (e *PositionUpdated) AggregateID() uint { return e.Position.ID }
+
+// PositionDeleted is emitted when a position is deleted
+type PositionDeleted struct {
+	Position *Position `json:"position"`
+}
+
+func (e *PositionDeleted) EventType() string { return EventPositionDeleted }
+func (e *PositionDeleted) AggregateID() uint { return e.Position.ID }
+
+// ChecklistCreated is emitted when an onboarding or offboarding checklist is created for a user
+type ChecklistCreated struct {
+	Checklist *Checklist `json:"checklist"`
+}
+
+func (e *ChecklistCreated) EventType() string { return EventChecklistCreated }
+func (e *ChecklistCreated) AggregateID() uint { return e.Checklist.ID }
+
+// ChecklistTaskCompleted is emitted when a checklist task is completed
+type ChecklistTaskCompleted struct {
+	UserID uint           `json:"user_id"`
+	Task   *ChecklistTask `json:"task"`
+}
+
+func (e *ChecklistTaskCompleted) EventType() string { return EventChecklistTaskCompleted }
+func (e *ChecklistTaskCompleted) AggregateID() uint { return e.Task.ChecklistID }
+
+// ChecklistTaskReopened is emitted when a completed checklist task is opened again
+type ChecklistTaskReopened struct {
+	UserID uint           `json:"user_id"`
+	Task   *ChecklistTask `json:"task"`
+}
+
+func (e *ChecklistTaskReopened) EventType() string { return EventChecklistTaskReopened }
+func (e *ChecklistTaskReopened) AggregateID() uint { return e.Task.ChecklistID }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// IdempotencyKey represents the idempotency_keys table (the stored response of a POST request
+// made with an Idempotency-Key header, replayed when the request is retried)
+type IdempotencyKey struct {
+	ID           uint       `gorm:"primaryKey;column:ik_id" json:"id"`
+	Scope        string     `gorm:"column:ik_scope;uniqueIndex:idx_ik_scope_key" json:"-"` // the authenticated user, or a SHA-256 of the Authorization header, so clients cannot share keys
+	Key          string     `gorm:"column:ik_key;uniqueIndex:idx_ik_scope_key" json:"key"`
+	RequestHash  string     `gorm:"column:ik_request_hash" json:"-"` // SHA-256 of the method, path and body
+	Method       string     `gorm:"column:ik_method" json:"method"`
+	Path         string     `gorm:"column:ik_path" json:"path"`
+	StatusCode   int        `gorm:"column:ik_status_code" json:"status_code"`
+	ContentType  string     `gorm:"column:ik_content_type" json:"-"`
+	ResponseBody string     `gorm:"column:ik_response_body;type:text" json:"-"`
+	CreatedAt    time.Time  `gorm:"column:ik_created_at" json:"created_at"`
+	CompletedAt  *time.Time `gorm:"column:ik_completed_at" json:"completed_at"` // unset while the first request is in progress
+	ExpiresAt    time.Time  `gorm:"column:ik_expires_at;index" json:"expires_at"`
+}
+
+// TableName overrides the table name
+func (IdempotencyKey) TableName() string {
+	return "\"user\".idempotency_keys"
+}
@@ -8,14 +8,16 @@ import (
 
 // Division represents the division table
 type Division struct {
-	ID        uint      `gorm:"primaryKey;column:div_id" json:"id"`
-	Code      string    `gorm:"unique;column:div_code" json:"code"`
-	Name      string    `gorm:"column:div_name" json:"name"`
-	IsActive  bool      `gorm:"default:true;column:div_is_active" json:"is_active"`
-	CreatedAt time.Time `gorm:"column:div_created_at" json:"created_at"`
-	CreatedBy string    `gorm:"column:div_created_by" json:"created_by"`
-	UpdatedAt time.Time `gorm:"column:div_updated_at" json:"updated_at"`
-	UpdatedBy string    `gorm:"column:div_updated_by" json:"updated_by"`
+	ID           uint         `gorm:"primaryKey;column:div_id" json:"id"`
+	Code         string       `gorm:"unique;column:div_code" json:"code"`
+	Name         string       `gorm:"column:div_name" json:"name"`
+	IsActive     bool         `gorm:"default:true;column:div_is_active" json:"is_active"`
+	CustomFields CustomFields `gorm:"column:div_custom_fields;type:jsonb;default:'{}'" json:"custom_fields"`
+	CreatedAt    time.Time    `gorm:"column:div_created_at" json:"created_at"`
+	CreatedBy    string       `gorm:"column:div_created_by" json:"created_by"`
+	UpdatedAt    time.Time    `gorm:"column:div_updated_at" json:"updated_at"`
+	UpdatedBy    string       `gorm:"column:div_updated_by" json:"updated_by"`
+	Version      uint         `gorm:"column:div_version;not null;default:1" json:"version"` // incremented on every change, exposed as the ETag
 }
 
 // TableName overrides the table name
@@ -25,14 +27,16 @@ func (Division) TableName() string {
 
 // Position represents the positions table
 type Position struct {
-	ID        uint      `gorm:"primaryKey;column:pos_id" json:"id"`
-	Code      string    `gorm:"unique;column:pos_code" json:"code"`
-	Name      string    `gorm:"column:pos_name" json:"name"`
-	IsActive  bool      `gorm:"default:true;column:pos_is_active" json:"is_active"`
-	CreatedAt time.Time `gorm:"column:pos_created_at" json:"created_at"`
-	CreatedBy string    `gorm:"column:pos_created_by" json:"created_by"`
-	UpdatedAt time.Time `gorm:"column:pos_updated_at" json:"updated_at"`
-	UpdatedBy string    `gorm:"column:pos_updated_by" json:"updated_by"`
+	ID           uint         `gorm:"primaryKey;column:pos_id" json:"id"`
+	Code         string       `gorm:"unique;column:pos_code" json:"code"`
+	Name         string       `gorm:"column:pos_name" json:"name"`
+	IsActive     bool         `gorm:"default:true;column:pos_is_active" json:"is_active"`
+	CustomFields CustomFields `gorm:"column:pos_custom_fields;type:jsonb;default:'{}'" json:"custom_fields"`
+	CreatedAt    time.Time    `gorm:"column:pos_created_at" json:"created_at"`
+	CreatedBy    string       `gorm:"column:pos_created_by" json:"created_by"`
+	UpdatedAt    time.Time    `gorm:"column:pos_updated_at" json:"updated_at"`
+	UpdatedBy    string       `gorm:"column:pos_updated_by" json:"updated_by"`
+	Version      uint         `gorm:"column:pos_version;not null;default:1" json:"version"` // incremented on every change, exposed as the ETag
 }
 
 // TableName overrides the table name
@@ -50,6 +54,7 @@ type Role struct {
 	CreatedBy string    `gorm:"column:role_created_by" json:"created_by"`
 	UpdatedAt time.Time `gorm:"column:role_updated_at" json:"updated_at"`
 	UpdatedBy string    `gorm:"column:role_updated_by" json:"updated_by"`
+	Version   uint      `gorm:"column:role_version;not null;default:1" json:"version"` // incremented on every change, exposed as the ETag
 	// Relations
 	Users []*User `gorm:"many2many:user_roles;foreignKey:role_id;joinForeignKey:ur_role_id;References:u_id;joinReferences:ur_user_id" json:"users,omitempty"`
 }
@@ -61,26 +66,32 @@ func (Role) TableName() string {
 
 // User represents the users table
 type User struct {
-	ID           uint       `gorm:"primaryKey;column:u_id" json:"id"`
-	UID          uuid.UUID  `gorm:"type:uuid;unique;column:u_uid;default:gen_random_uuid()" json:"uid"`
-	EmployeeID   string     `gorm:"unique;column:u_employee_id" json:"employee_id"`
-	Name         string     `gorm:"column:u_name" json:"name"`
-	Email        string     `gorm:"unique;column:u_email" json:"email"`
-	Password     string     `gorm:"column:u_password" json:"-"` // Never return password in JSON
-	Phone        string     `gorm:"column:u_phone" json:"phone"`
-	Address      string     `gorm:"column:u_address" json:"address"`
-	Birthdate    *time.Time `gorm:"column:u_birthdate" json:"birthdate"`
-	JoinDate     time.Time  `gorm:"column:u_join_date" json:"join_date"`
-	ProfileImage string     `gorm:"column:u_profile_image" json:"profile_image"`
-	DivisionID   *uint      `gorm:"column:u_division_id" json:"division_id"`
-	PositionID   *uint      `gorm:"column:u_position_id" json:"position_id"`
-	IsManager    bool       `gorm:"default:false;column:u_is_manager" json:"is_manager"`
-	ManagerID    *uint      `gorm:"column:u_manager_id" json:"manager_id"`
-	IsActive     bool       `gorm:"default:true;column:u_is_active" json:"is_active"`
-	CreatedAt    time.Time  `gorm:"column:u_created_at" json:"created_at"`
-	CreatedBy    string     `gorm:"column:u_created_by" json:"created_by"`
-	UpdatedAt    time.Time  `gorm:"column:u_updated_at" json:"updated_at"`
-	UpdatedBy    string     `gorm:"column:u_updated_by" json:"updated_by"`
+	ID            uint         `gorm:"primaryKey;column:u_id" json:"id"`
+	UID           uuid.UUID    `gorm:"type:uuid;unique;column:u_uid;default:gen_random_uuid()" json:"uid"`
+	EmployeeID    string       `gorm:"unique;column:u_employee_id" json:"employee_id"`
+	Name          string       `gorm:"column:u_name" json:"name"`
+	Email         string       `gorm:"unique;column:u_email" json:"email"`
+	Password      string       `gorm:"column:u_password" json:"-"` // Never return password in JSON
+	Phone         string       `gorm:"column:u_phone" json:"phone"`
+	Address       string       `gorm:"column:u_address" json:"address"`
+	Birthdate     *time.Time   `gorm:"column:u_birthdate" json:"birthdate"`
+	JoinDate      time.Time    `gorm:"column:u_join_date" json:"join_date"`
+	ProfileImage  string       `gorm:"column:u_profile_image" json:"profile_image"`
+	AvatarKey     string       `gorm:"column:u_avatar_key" json:"-"`  // blob key prefix of the uploaded avatar, empty when none
+	AvatarType    string       `gorm:"column:u_avatar_type" json:"-"` // content type of the uploaded avatar images
+	DivisionID    *uint        `gorm:"column:u_division_id" json:"division_id"`
+	PositionID    *uint        `gorm:"column:u_position_id" json:"position_id"`
+	IsManager     bool         `gorm:"default:false;column:u_is_manager" json:"is_manager"`
+	ManagerID     *uint        `gorm:"column:u_manager_id" json:"manager_id"`
+	CustomFields  CustomFields `gorm:"column:u_custom_fields;type:jsonb;default:'{}'" json:"custom_fields"`
+	IsActive      bool         `gorm:"default:true;column:u_is_active" json:"is_active"`
+	DeactivatedAt *time.Time   `gorm:"column:u_deactivated_at;index" json:"deactivated_at"` // Set when the user is deactivated, cleared on reactivation
+	LastLoginAt   *time.Time   `gorm:"column:u_last_login_at" json:"last_login_at"`
+	CreatedAt     time.Time    `gorm:"column:u_created_at" json:"created_at"`
+	CreatedBy     string       `gorm:"column:u_created_by" json:"created_by"`
+	UpdatedAt     time.Time    `gorm:"column:u_updated_at" json:"updated_at"`
+	UpdatedBy     string       `gorm:"column:u_updated_by" json:"updated_by"`
+	Version       uint         `gorm:"column:u_version;not null;default:1" json:"version"` // incremented on every change, exposed as the ETag
 	// Relations
 	Division  *Division  `gorm:"foreignKey:u_division_id;references:div_id" json:"division,omitempty"`
 	Position  *Position  `gorm:"foreignKey:u_position_id;references:pos_id" json:"position,omitempty"`
@@ -97,11 +108,13 @@ func (User) TableName() string {
 
 // UserRole represents the user_roles table (many-to-many relationship)
 type UserRole struct {
-	ID        uint      `gorm:"primaryKey;column:ur_id" json:"id"`
-	UserID    uint      `gorm:"column:ur_user_id" json:"user_id"`
-	RoleID    uint      `gorm:"column:ur_role_id" json:"role_id"`
-	CreatedAt time.Time `gorm:"column:ur_created_at" json:"created_at"`
-	CreatedBy string    `gorm:"column:ur_created_by" json:"created_by"`
+	ID         uint       `gorm:"primaryKey;column:ur_id" json:"id"`
+	UserID     uint       `gorm:"column:ur_user_id" json:"user_id"`
+	RoleID     uint       `gorm:"column:ur_role_id" json:"role_id"`
+	ValidFrom  *time.Time `gorm:"column:ur_valid_from" json:"valid_from"`   // nil means effective immediately
+	ValidUntil *time.Time `gorm:"column:ur_valid_until" json:"valid_until"` // nil means never expires
+	CreatedAt  time.Time  `gorm:"column:ur_created_at" json:"created_at"`
+	CreatedBy  string     `gorm:"column:ur_created_by" json:"created_by"`
 	// Relations
 	User *User `gorm:"foreignKey:ur_user_id;references:u_id" json:"user,omitempty"`
 	Role *Role `gorm:"foreignKey:ur_role_id;references:role_id" json:"role,omitempty"`
@@ -112,6 +125,23 @@ func (UserRole) TableName() string {
 	return "user.user_roles"
 }
 
+// RoleGrantExpiration represents the role_grant_expirations table (history of expired time-bound grants)
+type RoleGrantExpiration struct {
+	ID         uint       `gorm:"primaryKey;column:rge_id" json:"id"`
+	UserID     uint       `gorm:"column:rge_user_id;index" json:"user_id"`
+	RoleID     uint       `gorm:"column:rge_role_id" json:"role_id"`
+	ValidFrom  *time.Time `gorm:"column:rge_valid_from" json:"valid_from"`
+	ValidUntil time.Time  `gorm:"column:rge_valid_until" json:"valid_until"`
+	GrantedAt  time.Time  `gorm:"column:rge_granted_at" json:"granted_at"`
+	GrantedBy  string     `gorm:"column:rge_granted_by" json:"granted_by"`
+	ExpiredAt  time.Time  `gorm:"column:rge_expired_at" json:"expired_at"`
+}
+
+// TableName overrides the table name
+func (RoleGrantExpiration) TableName() string {
+	return "\"user\".role_grant_expirations"
+}
+
 // DTOs (Data Transfer Objects)
 
 // UserLoginRequest represents login request payload
@@ -122,57 +152,87 @@ type UserLoginRequest struct {
 
 // UserResponse represents user data without sensitive information
 type UserResponse struct {
-	ID           uint      `json:"id"`
-	UID          uuid.UUID `json:"uid"`
-	EmployeeID   string    `json:"employee_id"`
-	Name         string    `json:"name"`
-	Email        string    `json:"email"`
-	Phone        string    `json:"phone,omitempty"`
-	Address      string    `json:"address,omitempty"`
-	Birthdate    string    `json:"birthdate,omitempty"`
-	JoinDate     string    `json:"join_date"`
-	ProfileImage string    `json:"profile_image,omitempty"`
-	Division     string    `json:"division,omitempty"`
-	Position     string    `json:"position,omitempty"`
-	IsManager    bool      `json:"is_manager"`
-	Manager      string    `json:"manager,omitempty"`
-	IsActive     bool      `json:"is_active"`
-	Roles        []string  `json:"roles,omitempty"`
+	ID            uint         `json:"id"`
+	UID           uuid.UUID    `json:"uid"`
+	EmployeeID    string       `json:"employee_id"`
+	Name          string       `json:"name"`
+	Email         string       `json:"email"`
+	Phone         string       `json:"phone,omitempty"`
+	Address       string       `json:"address,omitempty"`
+	Birthdate     string       `json:"birthdate,omitempty"`
+	JoinDate      string       `json:"join_date"`
+	ProfileImage  string       `json:"profile_image,omitempty"`
+	Division      string       `json:"division,omitempty"`
+	Position      string       `json:"position,omitempty"`
+	IsManager     bool         `json:"is_manager"`
+	Manager       string       `json:"manager,omitempty"`
+	IsActive      bool         `json:"is_active"`
+	DeactivatedAt *time.Time   `json:"deactivated_at,omitempty"`
+	LastLoginAt   *time.Time   `json:"last_login_at"`
+	Roles         []string     `json:"roles,omitempty"`
+	CustomFields  CustomFields `json:"custom_fields"`
+	Version       uint         `json:"version"`
 }
 
 // CreateUserRequest represents payload for creating a new user
 type CreateUserRequest struct {
-	EmployeeID   string    `json:"employee_id" binding:"required"`
-	Name         string    `json:"name" binding:"required"`
-	Email        string    `json:"email" binding:"required,email"`
-	Password     string    `json:"password" binding:"required,min=6"`
-	Phone        string    `json:"phone"`
-	Address      string    `json:"address"`
-	Birthdate    string    `json:"birthdate"`
-	JoinDate     string    `json:"join_date" binding:"required"`
-	ProfileImage string    `json:"profile_image"`
-	DivisionID   *uint     `json:"division_id"`
-	PositionID   *uint     `json:"position_id"`
-	IsManager    bool      `json:"is_manager"`
-	ManagerID    *uint     `json:"manager_id"`
-	RoleIDs      []uint    `json:"role_ids"`
+	EmployeeID   string                 `json:"employee_id" binding:"required"`
+	Name         string                 `json:"name" binding:"required"`
+	Email        string                 `json:"email" binding:"required,email"`
+	Password     string                 `json:"password" binding:"required,min=6"`
+	Phone        string                 `json:"phone"`
+	Address      string                 `json:"address"`
+	Birthdate    string                 `json:"birthdate"`
+	JoinDate     string                 `json:"join_date" binding:"required"`
+	ProfileImage string                 `json:"profile_image"`
+	DivisionID   *uint                  `json:"division_id"`
+	PositionID   *uint                  `json:"position_id"`
+	IsManager    bool                   `json:"is_manager"`
+	ManagerID    *uint                  `json:"manager_id"`
+	RoleIDs      []uint                 `json:"role_ids"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 // UpdateUserRequest represents payload for updating a user
 type UpdateUserRequest struct {
-	Name         string    `json:"name"`
-	Email        string    `json:"email" binding:"email"`
-	Phone        string    `json:"phone"`
-	Address      string    `json:"address"`
-	Birthdate    string    `json:"birthdate"`
-	JoinDate     string    `json:"join_date"`
-	ProfileImage string    `json:"profile_image"`
-	DivisionID   *uint     `json:"division_id"`
-	PositionID   *uint     `json:"position_id"`
-	IsManager    *bool     `json:"is_manager"`
-	ManagerID    *uint     `json:"manager_id"`
-	IsActive     *bool     `json:"is_active"`
-	RoleIDs      []uint    `json:"role_ids"`
+	Name         string                 `json:"name"`
+	Email        string                 `json:"email" binding:"email"`
+	Phone        string                 `json:"phone"`
+	Address      string                 `json:"address"`
+	Birthdate    string                 `json:"birthdate"`
+	JoinDate     string                 `json:"join_date"`
+	ProfileImage string                 `json:"profile_image"`
+	DivisionID   *uint                  `json:"division_id"`
+	PositionID   *uint                  `json:"position_id"`
+	IsManager    *bool                  `json:"is_manager"`
+	ManagerID    *uint                  `json:"manager_id"`
+	IsActive     *bool                  `json:"is_active"`
+	RoleIDs      []uint                 `json:"role_ids"`
+	CustomFields map[string]interface{} `json:"custom_fields"`   // merged into the current values; null removes a field
+	Clear        []string               `json:"clear,omitempty"` // optional fields to reset, such as the ones a merge patch sets to null
+	Version      *uint                  `json:"-"`               // version the update expects, taken from If-Match; nil skips the check
+}
+
+// ClearableUserFields lists the user fields an update can reset to empty
+var ClearableUserFields = []string{"phone", "address", "birthdate", "profile_image", "division_id", "position_id", "manager_id", "custom_fields"}
+
+// RoleGrantRequest represents payload for granting a role to a user, optionally time-bound
+type RoleGrantRequest struct {
+	RoleID     uint   `json:"role_id" binding:"required"`
+	ValidFrom  string `json:"valid_from"`  // RFC 3339 or YYYY-MM-DD, empty means now
+	ValidUntil string `json:"valid_until"` // RFC 3339 or YYYY-MM-DD, empty means never
+}
+
+// RoleGrantResponse represents a role assigned to a user with its validity window
+type RoleGrantResponse struct {
+	RoleID     uint       `json:"role_id"`
+	RoleName   string     `json:"role_name"`
+	Level      int        `json:"level"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+	Effective  bool       `json:"effective"`
+	CreatedAt  time.Time  `json:"created_at"`
+	CreatedBy  string     `json:"created_by"`
 }
 
 // LoginResponse represents response after successful login
@@ -183,20 +243,25 @@ type LoginResponse struct {
 
 // DivisionRequest represents payload for creating/updating division
 type DivisionRequest struct {
-	Code string `json:"code" binding:"required"`
-	Name string `json:"name" binding:"required"`
+	Code         string                 `json:"code" binding:"required"`
+	Name         string                 `json:"name" binding:"required"`
+	CustomFields map[string]interface{} `json:"custom_fields"` // merged into the current values; null removes a field
+	Version      *uint                  `json:"-"`             // version the update expects, taken from If-Match; nil skips the check
 }
 
 // PositionRequest represents payload for creating/updating position
 type PositionRequest struct {
-	Code string `json:"code" binding:"required"`
-	Name string `json:"name" binding:"required"`
+	Code         string                 `json:"code" binding:"required"`
+	Name         string                 `json:"name" binding:"required"`
+	CustomFields map[string]interface{} `json:"custom_fields"` // merged into the current values; null removes a field
+	Version      *uint                  `json:"-"`             // version the update expects, taken from If-Match; nil skips the check
 }
 
 // RoleRequest represents payload for creating/updating role
 type RoleRequest struct {
-	Name  string `json:"name" binding:"required"`
-	Level int    `json:"level"`
+	Name    string `json:"name" binding:"required"`
+	Level   int    `json:"level"`
+	Version *uint  `json:"-"` // version the update expects, taken from If-Match; nil skips the check
 }
 
 // PaginatedResponse represents a paginated response
@@ -210,11 +275,13 @@ type PaginatedResponse struct {
 
 // Statistics represents dashboard statistics
 type Statistics struct {
-	TotalUsers        int64 `json:"total_users"`
-	ActiveUsers       int64 `json:"active_users"`
-	TotalDivisions    int64 `json:"total_divisions"`
-	TotalPositions    int64 `json:"total_positions"`
-	UsersPerDivision  []map[string]interface{} `json:"users_per_division"`
-	UsersPerPosition  []map[string]interface{} `json:"users_per_position"`
-	NewUsersThisMonth int64 `json:"new_users_this_month"`
+	TotalUsers            int64                    `json:"total_users"`
+	ActiveUsers           int64                    `json:"active_users"`
+	TotalDivisions        int64                    `json:"total_divisions"`
+	TotalPositions        int64                    `json:"total_positions"`
+	UsersPerDivision      []map[string]interface{} `json:"users_per_division"`
+	UsersPerPosition      []map[string]interface{} `json:"users_per_position"`
+	NewUsersThisMonth     int64                    `json:"new_users_this_month"`
+	OverdueChecklistTasks int64                    `json:"overdue_checklist_tasks"`
+	GeneratedAt           time.Time                `json:"generated_at"`
 }
\ No newline at end of file
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Offboarding statuses
+const (
+	OffboardingScheduled = "scheduled" // waiting for its effective date
+	OffboardingCompleted = "completed"
+	OffboardingCancelled = "cancelled"
+	OffboardingFailed    = "failed" // the scheduled run failed; see last_error
+)
+
+// Offboarding represents the offboardings table (the termination record of a leaver)
+type Offboarding struct {
+	ID                uint       `gorm:"primaryKey;column:ofb_id" json:"id"`
+	UserID            uint       `gorm:"column:ofb_user_id;index" json:"user_id"`
+	EffectiveAt       time.Time  `gorm:"column:ofb_effective_at;index" json:"effective_at"`
+	Reason            string     `gorm:"column:ofb_reason" json:"reason"`
+	SuccessorID       *uint      `gorm:"column:ofb_successor_id" json:"successor_id"` // new manager of the direct reports; nil uses the leaver's manager
+	Status            string     `gorm:"column:ofb_status;index" json:"status"`
+	ReassignedReports int        `gorm:"column:ofb_reassigned_reports" json:"reassigned_reports"`
+	RevokedRoles      StringList `gorm:"column:ofb_revoked_roles;type:text" json:"revoked_roles"`
+	LastError         string     `gorm:"column:ofb_last_error" json:"last_error,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:ofb_created_at" json:"created_at"`
+	CreatedBy         string     `gorm:"column:ofb_created_by" json:"created_by"`
+	CompletedAt       *time.Time `gorm:"column:ofb_completed_at" json:"completed_at"`
+	CancelledAt       *time.Time `gorm:"column:ofb_cancelled_at" json:"cancelled_at,omitempty"`
+	CancelledBy       string     `gorm:"column:ofb_cancelled_by" json:"cancelled_by,omitempty"`
+}
+
+// TableName overrides the table name
+func (Offboarding) TableName() string {
+	return "\"user\".offboardings"
+}
+
+// OffboardRequest represents payload for offboarding a user
+type OffboardRequest struct {
+	EffectiveDate string `json:"effective_date"` // YYYY-MM-DD or RFC 3339; empty or past offboards now, recording a past date as the deactivation date
+	Reason        string `json:"reason" binding:"required"`
+	SuccessorID   *uint  `json:"successor_id"`
+}
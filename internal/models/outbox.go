@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// OutboxEvent represents the outbox_events table (events written in the same transaction as the change)
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey;column:ob_id" json:"id"`
+	EventType     string     `gorm:"column:ob_event_type" json:"type"`
+	AggregateType string     `gorm:"column:ob_aggregate_type" json:"aggregate_type"`
+	AggregateID   uint       `gorm:"column:ob_aggregate_id" json:"aggregate_id"`
+	Payload       JSON       `gorm:"column:ob_payload;type:jsonb" json:"data"`
+	OccurredAt    time.Time  `gorm:"column:ob_occurred_at" json:"occurred_at"`
+	ProcessedAt   *time.Time `gorm:"column:ob_processed_at;index" json:"-"`
+}
+
+// TableName overrides the table name
+func (OutboxEvent) TableName() string {
+	return "\"user\".outbox_events"
+}
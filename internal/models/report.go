@@ -0,0 +1,126 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Report entities
+const (
+	ReportEntityUsers = "users"
+)
+
+// Report metrics
+const (
+	ReportMetricCount     = "count"      // number of users
+	ReportMetricAvgTenure = "avg_tenure" // average years from join date to today or deactivation
+)
+
+// ReportEntities lists the entities a report can be built on
+var ReportEntities = []string{ReportEntityUsers}
+
+// ReportDimensions lists the dimensions a report can be grouped by
+var ReportDimensions = []string{"division", "position", "role", "manager", "join_year"}
+
+// ReportFilterFields lists the fields a report can be filtered on
+var ReportFilterFields = []string{"division_id", "position_id", "role_id", "manager_id", "is_active", "is_manager", "join_date"}
+
+// ReportOperators lists the filter operators; "in" takes a list of values
+var ReportOperators = []string{"eq", "neq", "gt", "gte", "lt", "lte", "in"}
+
+// ReportMetrics lists the metrics a report can compute
+var ReportMetrics = []string{ReportMetricCount, ReportMetricAvgTenure}
+
+// ReportFilter represents one condition of a report definition
+type ReportFilter struct {
+	Field    string      `json:"field" binding:"required"`
+	Operator string      `json:"operator" binding:"required"`
+	Value    interface{} `json:"value"`
+}
+
+// ReportFilters holds report filters stored as a jsonb column
+type ReportFilters []ReportFilter
+
+// Value implements driver.Valuer
+func (f ReportFilters) Value() (driver.Value, error) {
+	if f == nil {
+		f = ReportFilters{}
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (f *ReportFilters) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	default:
+		return fmt.Errorf("cannot scan %T into ReportFilters", value)
+	}
+}
+
+// ReportDefinition represents the report_definitions table (a saved report of one user)
+type ReportDefinition struct {
+	ID          uint          `gorm:"primaryKey;column:rd_id" json:"id"`
+	OwnerID     uint          `gorm:"column:rd_owner_id;index" json:"owner_id"`
+	Name        string        `gorm:"column:rd_name" json:"name"`
+	Entity      string        `gorm:"column:rd_entity" json:"entity"`
+	GroupBy     StringList    `gorm:"column:rd_group_by;type:text" json:"group_by"`
+	Filters     ReportFilters `gorm:"column:rd_filters;type:jsonb" json:"filters"`
+	Metric      string        `gorm:"column:rd_metric" json:"metric"`
+	IsPinned    bool          `gorm:"default:false;column:rd_is_pinned" json:"is_pinned"` // shown as a dashboard widget
+	WidgetOrder int           `gorm:"column:rd_widget_order" json:"widget_order"`
+	CreatedAt   time.Time     `gorm:"column:rd_created_at" json:"created_at"`
+	CreatedBy   string        `gorm:"column:rd_created_by" json:"created_by"`
+	UpdatedAt   time.Time     `gorm:"column:rd_updated_at" json:"updated_at"`
+	UpdatedBy   string        `gorm:"column:rd_updated_by" json:"updated_by"`
+}
+
+// TableName overrides the table name
+func (ReportDefinition) TableName() string {
+	return "\"user\".report_definitions"
+}
+
+// ReportDefinitionRequest represents payload for creating/updating or previewing a report
+type ReportDefinitionRequest struct {
+	Name        string         `json:"name" binding:"required"`
+	Entity      string         `json:"entity"` // defaults to users
+	GroupBy     []string       `json:"group_by"`
+	Filters     []ReportFilter `json:"filters" binding:"dive"`
+	Metric      string         `json:"metric"` // defaults to count
+	IsPinned    *bool          `json:"is_pinned"`
+	WidgetOrder int            `json:"widget_order"`
+}
+
+// ReportRow represents one group of a report result
+type ReportRow struct {
+	Dimensions map[string]interface{} `json:"dimensions,omitempty"`
+	Value      float64                `json:"value"`
+}
+
+// ReportResult represents an executed report
+type ReportResult struct {
+	Report      *ReportDefinition `json:"report"`
+	Rows        []ReportRow       `json:"rows"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// ReportSchema describes what report definitions may contain
+type ReportSchema struct {
+	Entities     []string `json:"entities"`
+	Dimensions   []string `json:"dimensions"`
+	FilterFields []string `json:"filter_fields"`
+	Operators    []string `json:"operators"`
+	Metrics      []string `json:"metrics"`
+}
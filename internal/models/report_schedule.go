@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// ReportSchedule represents the report_schedules table (a recurring report email)
+type ReportSchedule struct {
+	ID              uint       `gorm:"primaryKey;column:rs_id" json:"id"`
+	Name            string     `gorm:"column:rs_name" json:"name"`
+	Cron            string     `gorm:"column:rs_cron" json:"cron"`
+	Timezone        string     `gorm:"column:rs_timezone;default:UTC" json:"timezone"`
+	DivisionID      *uint      `gorm:"column:rs_division_id" json:"division_id"`                      // nil reports on the whole organization
+	Recipients      StringList `gorm:"column:rs_recipients;type:text" json:"recipients"`              // empty sends to the division's managers
+	AnniversaryDays int        `gorm:"column:rs_anniversary_days;default:30" json:"anniversary_days"` // how far ahead to list work anniversaries
+	IsActive        bool       `gorm:"default:true;column:rs_is_active" json:"is_active"`
+	NextRunAt       *time.Time `gorm:"column:rs_next_run_at;index" json:"next_run_at"`
+	LastRunAt       *time.Time `gorm:"column:rs_last_run_at" json:"last_run_at"`
+	LastError       string     `gorm:"column:rs_last_error" json:"last_error,omitempty"`
+	CreatedAt       time.Time  `gorm:"column:rs_created_at" json:"created_at"`
+	CreatedBy       string     `gorm:"column:rs_created_by" json:"created_by"`
+	UpdatedAt       time.Time  `gorm:"column:rs_updated_at" json:"updated_at"`
+	UpdatedBy       string     `gorm:"column:rs_updated_by" json:"updated_by"`
+	// Relations
+	Division *Division `gorm:"foreignKey:rs_division_id;references:div_id" json:"division,omitempty"`
+}
+
+// TableName overrides the table name
+func (ReportSchedule) TableName() string {
+	return "\"user\".report_schedules"
+}
+
+// ReportScheduleRequest represents payload for creating/updating a report schedule
+type ReportScheduleRequest struct {
+	Name            string   `json:"name" binding:"required"`
+	Cron            string   `json:"cron" binding:"required"`
+	Timezone        string   `json:"timezone"` // IANA name, defaults to UTC
+	DivisionID      *uint    `json:"division_id"`
+	Recipients      []string `json:"recipients" binding:"dive,email"`
+	AnniversaryDays int      `json:"anniversary_days" binding:"omitempty,min=1,max=366"`
+	IsActive        *bool    `json:"is_active"`
+}
+
+// HeadcountReport represents the headcount movement of a period, optionally of one division
+type HeadcountReport struct {
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	StartHeadcount int64             `json:"start_headcount"`
+	Headcount      int64             `json:"headcount"` // at the end of the period
+	Changes        []HeadcountChange `json:"changes"`
+}
+
+// HeadcountChange represents a user who joined or left during a report period
+type HeadcountChange struct {
+	UserID     uint      `json:"user_id"`
+	EmployeeID string    `json:"employee_id"`
+	Name       string    `json:"name"`
+	Division   string    `json:"division"`
+	Position   string    `json:"position"`
+	Change     string    `json:"change"` // "joined" or "left"
+	Date       time.Time `json:"date"`
+}
+
+// WorkAnniversary represents an upcoming work anniversary of an active user
+type WorkAnniversary struct {
+	UserID     uint      `json:"user_id"`
+	EmployeeID string    `json:"employee_id"`
+	Name       string    `json:"name"`
+	Division   string    `json:"division"`
+	JoinDate   time.Time `json:"join_date"`
+	Date       time.Time `json:"date"`
+	Years      int       `json:"years"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Scheduled change statuses
+const (
+	ScheduledChangePending   = "pending"   // waiting for its effective time
+	ScheduledChangeApplied   = "applied"   // applied to the user
+	ScheduledChangeSubmitted = "submitted" // held as a change request because it needed approval
+	ScheduledChangeCancelled = "cancelled"
+	ScheduledChangeFailed    = "failed" // could not be applied; see last_error
+)
+
+// ScheduledChange represents the scheduled_changes table (a user update to apply at a later time)
+type ScheduledChange struct {
+	ID              uint       `gorm:"primaryKey;column:sc_id" json:"id"`
+	TargetUserID    uint       `gorm:"column:sc_target_user_id;index" json:"target_user_id"`
+	Payload         string     `gorm:"column:sc_payload;type:jsonb" json:"-"` // the UpdateUserRequest to apply
+	EffectiveAt     time.Time  `gorm:"column:sc_effective_at;index" json:"effective_at"`
+	Status          string     `gorm:"column:sc_status;index" json:"status"`
+	ChangeRequestID *uint      `gorm:"column:sc_change_request_id" json:"change_request_id,omitempty"` // set when the change was held for approval
+	LastError       string     `gorm:"column:sc_last_error" json:"last_error,omitempty"`
+	RequestedByID   uint       `gorm:"column:sc_requested_by_id" json:"requested_by_id"`
+	RequestedBy     string     `gorm:"column:sc_requested_by" json:"requested_by"`
+	CreatedAt       time.Time  `gorm:"column:sc_created_at" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"column:sc_updated_at" json:"updated_at"`
+	UpdatedBy       string     `gorm:"column:sc_updated_by" json:"updated_by"`
+	ProcessedAt     *time.Time `gorm:"column:sc_processed_at" json:"processed_at"` // when it was applied, submitted or failed
+	CancelledAt     *time.Time `gorm:"column:sc_cancelled_at" json:"cancelled_at,omitempty"`
+	CancelledBy     string     `gorm:"column:sc_cancelled_by" json:"cancelled_by,omitempty"`
+}
+
+// TableName overrides the table name
+func (ScheduledChange) TableName() string {
+	return "\"user\".scheduled_changes"
+}
+
+// ScheduledChangeResponse represents a scheduled change with the changes it applies
+type ScheduledChangeResponse struct {
+	ScheduledChange
+	Changes *UpdateUserRequest `json:"changes"`
+}
+
+// ScheduledChangeRequest represents payload for scheduling or editing a future user update
+type ScheduledChangeRequest struct {
+	EffectiveAt string             `json:"effective_at" binding:"required"` // RFC 3339 or YYYY-MM-DD, in the future
+	Changes     *UpdateUserRequest `json:"changes" binding:"required"`
+}
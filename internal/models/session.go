@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSession represents the user_sessions table (one row per issued token)
+type UserSession struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;column:us_id" json:"id"`
+	UserID    uint       `gorm:"column:us_user_id;index" json:"user_id"`
+	IPAddress string     `gorm:"column:us_ip_address" json:"ip_address"`
+	UserAgent string     `gorm:"column:us_user_agent" json:"user_agent"`
+	CreatedAt time.Time  `gorm:"column:us_created_at" json:"created_at"`
+	ExpiresAt time.Time  `gorm:"column:us_expires_at" json:"expires_at"`
+	RevokedAt *time.Time `gorm:"column:us_revoked_at" json:"revoked_at,omitempty"`
+	RevokedBy string     `gorm:"column:us_revoked_by" json:"revoked_by,omitempty"`
+	// Set when the session was opened by an admin impersonating the user
+	ImpersonatorID *uint `gorm:"column:us_impersonator_id" json:"impersonator_id,omitempty"`
+}
+
+// TableName overrides the table name
+func (UserSession) TableName() string {
+	return "\"user\".user_sessions"
+}
+
+// LoginEvent represents the login_events table (successful and failed logins)
+type LoginEvent struct {
+	ID            uint       `gorm:"primaryKey;column:le_id" json:"id"`
+	UserID        *uint      `gorm:"column:le_user_id;index" json:"user_id"`
+	Email         string     `gorm:"column:le_email" json:"email"`
+	IPAddress     string     `gorm:"column:le_ip_address" json:"ip_address"`
+	UserAgent     string     `gorm:"column:le_user_agent" json:"user_agent"`
+	Success       bool       `gorm:"column:le_success" json:"success"`
+	FailureReason string     `gorm:"column:le_failure_reason" json:"failure_reason,omitempty"`
+	SessionID     *uuid.UUID `gorm:"type:uuid;column:le_session_id" json:"session_id,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:le_created_at" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (LoginEvent) TableName() string {
+	return "\"user\".login_events"
+}
+
+// SessionResponse represents an active session of the current user
+type SessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Current   bool      `json:"current"`
+	// Set when the session was opened by an admin impersonating the user
+	ImpersonatorID *uint `json:"impersonator_id,omitempty"`
+}
+
+// ImpersonationResponse represents response after starting an impersonation
+type ImpersonationResponse struct {
+	Token        string       `json:"token"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	User         UserResponse `json:"user"`
+	Impersonator UserResponse `json:"impersonator"`
+}
+
+// InactiveUser represents a user in the inactive users report
+type InactiveUser struct {
+	ID          uint       `json:"id"`
+	EmployeeID  string     `json:"employee_id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Division    string     `json:"division,omitempty"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// TrendPoint represents headcount movement over one period of the trends report
+type TrendPoint struct {
+	PeriodStart    string  `json:"period_start"`
+	PeriodEnd      string  `json:"period_end"` // last day of the period
+	Group          string  `json:"group,omitempty"`
+	Hires          int64   `json:"hires"`
+	Leavers        int64   `json:"leavers"`
+	StartHeadcount int64   `json:"start_headcount"`
+	Headcount      int64   `json:"headcount"` // at the end of the period
+	NetChange      int64   `json:"net_change"`
+	TurnoverRate   float64 `json:"turnover_rate"` // leavers as a percentage of average headcount
+}
+
+// TrendsResponse represents the headcount trends report
+type TrendsResponse struct {
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Interval string       `json:"interval"`
+	GroupBy  string       `json:"group_by,omitempty"`
+	Points   []TrendPoint `json:"points"`
+}
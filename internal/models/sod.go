@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// SoDRule represents the sod_rules table (a set of roles no user may hold two of)
+type SoDRule struct {
+	ID          uint      `gorm:"primaryKey;column:sod_id" json:"id"`
+	Name        string    `gorm:"unique;column:sod_name" json:"name"`
+	Description string    `gorm:"column:sod_description" json:"description"`
+	IsActive    bool      `gorm:"default:true;column:sod_is_active" json:"is_active"`
+	CreatedAt   time.Time `gorm:"column:sod_created_at" json:"created_at"`
+	CreatedBy   string    `gorm:"column:sod_created_by" json:"created_by"`
+	UpdatedAt   time.Time `gorm:"column:sod_updated_at" json:"updated_at"`
+	UpdatedBy   string    `gorm:"column:sod_updated_by" json:"updated_by"`
+	// Relations
+	RuleRoles []SoDRuleRole `gorm:"foreignKey:sodr_rule_id;references:sod_id" json:"roles,omitempty"`
+}
+
+// TableName overrides the table name
+func (SoDRule) TableName() string {
+	return "\"user\".sod_rules"
+}
+
+// SoDRuleRole represents the sod_rule_roles table (the roles of a rule)
+type SoDRuleRole struct {
+	ID     uint `gorm:"primaryKey;column:sodr_id" json:"-"`
+	RuleID uint `gorm:"column:sodr_rule_id;index" json:"-"`
+	RoleID uint `gorm:"column:sodr_role_id" json:"role_id"`
+	// Relations
+	Role *Role `gorm:"foreignKey:sodr_role_id;references:role_id" json:"role,omitempty"`
+}
+
+// TableName overrides the table name
+func (SoDRuleRole) TableName() string {
+	return "\"user\".sod_rule_roles"
+}
+
+// SoDException represents the sod_exceptions table (a justified, expiring permission to violate a rule)
+type SoDException struct {
+	ID            uint      `gorm:"primaryKey;column:sode_id" json:"id"`
+	RuleID        uint      `gorm:"column:sode_rule_id;index" json:"rule_id"`
+	UserID        uint      `gorm:"column:sode_user_id;index" json:"user_id"`
+	Justification string    `gorm:"column:sode_justification" json:"justification"`
+	ExpiresAt     time.Time `gorm:"column:sode_expires_at" json:"expires_at"`
+	CreatedAt     time.Time `gorm:"column:sode_created_at" json:"created_at"`
+	CreatedBy     string    `gorm:"column:sode_created_by" json:"created_by"`
+	// Relations
+	Rule *SoDRule `gorm:"foreignKey:sode_rule_id;references:sod_id" json:"rule,omitempty"`
+	User *User    `gorm:"foreignKey:sode_user_id;references:u_id" json:"user,omitempty"`
+}
+
+// TableName overrides the table name
+func (SoDException) TableName() string {
+	return "\"user\".sod_exceptions"
+}
+
+// SoDRuleRequest represents payload for creating/updating a segregation-of-duties rule
+type SoDRuleRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	RoleIDs     []uint `json:"role_ids" binding:"required,min=2"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// SoDExceptionRequest represents payload for granting a segregation-of-duties exception
+type SoDExceptionRequest struct {
+	RuleID        uint   `json:"rule_id" binding:"required"`
+	UserID        uint   `json:"user_id" binding:"required"`
+	Justification string `json:"justification" binding:"required"`
+	ExpiresAt     string `json:"expires_at" binding:"required"` // RFC 3339 or YYYY-MM-DD
+}
+
+// SoDViolation represents a user holding conflicting roles without a valid exception
+type SoDViolation struct {
+	RuleID     uint     `json:"rule_id"`
+	RuleName   string   `json:"rule_name"`
+	UserID     uint     `json:"user_id"`
+	EmployeeID string   `json:"employee_id"`
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	Roles      []string `json:"roles"`
+}
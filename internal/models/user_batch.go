@@ -0,0 +1,70 @@
+package models
+
+import "encoding/json"
+
+// User batch modes
+const (
+	UserBatchAllOrNothing = "all_or_nothing" // every user is changed or none is
+	UserBatchBestEffort   = "best_effort"    // each user is changed on its own
+)
+
+// User batch operation types
+const (
+	UserBatchUpdate     = "update"      // sets the fields given in changes
+	UserBatchAddRole    = "add_role"    // assigns role_id
+	UserBatchRemoveRole = "remove_role" // removes role_id
+	UserBatchDeactivate = "deactivate"  // sets is_active to false
+)
+
+// User batch result statuses
+const (
+	UserBatchItemUpdated    = "updated"
+	UserBatchItemSubmitted  = "submitted"   // held as a change request because it needed approval
+	UserBatchItemFailed     = "failed"      // see error
+	UserBatchItemRolledBack = "rolled_back" // valid, but undone because another user failed in all_or_nothing mode
+)
+
+// UserBatchRequest represents payload for applying the same operations to many users
+type UserBatchRequest struct {
+	Mode       string               `json:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"` // defaults to all_or_nothing
+	UserIDs    []uint               `json:"user_ids"`                                                  // target users; use either user_ids or filter
+	Filter     *UserBatchFilter     `json:"filter"`
+	Operations []UserBatchOperation `json:"operations" binding:"required,min=1,dive"`
+}
+
+// UserBatchFilter selects the target users of a batch; every given criterion must match
+type UserBatchFilter struct {
+	Search       string            `json:"search"` // name, email or employee ID
+	DivisionID   *uint             `json:"division_id"`
+	PositionID   *uint             `json:"position_id"`
+	ManagerID    *uint             `json:"manager_id"`
+	IsActive     *bool             `json:"is_active"`
+	CustomFields map[string]string `json:"custom_fields"`
+}
+
+// UserBatchOperation represents one operation applied to every target user, in order
+type UserBatchOperation struct {
+	Type    string          `json:"type" binding:"required,oneof=update add_role remove_role deactivate"`
+	Changes json.RawMessage `json:"changes"` // UpdateUserRequest fields, for update
+	RoleID  uint            `json:"role_id"` // for add_role and remove_role
+}
+
+// UserBatchResult represents the outcome of a batch for one user
+type UserBatchResult struct {
+	UserID        uint                   `json:"user_id"`
+	Status        string                 `json:"status"`
+	User          *UserResponse          `json:"user,omitempty"`
+	ChangeRequest *ChangeRequestResponse `json:"change_request,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+}
+
+// UserBatchResponse represents the outcome of a batch
+type UserBatchResponse struct {
+	Mode      string            `json:"mode"`
+	Total     int               `json:"total"`
+	Updated   int               `json:"updated"`
+	Submitted int               `json:"submitted"`
+	Failed    int               `json:"failed"`
+	Results   []UserBatchResult `json:"results"`
+	Error     string            `json:"error,omitempty"` // set when an all_or_nothing batch was rolled back
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryRetrying  = "retrying"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription represents the webhook_subscriptions table
+type WebhookSubscription struct {
+	ID        uint       `gorm:"primaryKey;column:whs_id" json:"id"`
+	Name      string     `gorm:"column:whs_name" json:"name"`
+	URL       string     `gorm:"column:whs_url" json:"url"`
+	Secret    string     `gorm:"column:whs_secret" json:"-"`                // Never return the signing secret in JSON
+	Events    StringList `gorm:"column:whs_events;type:text" json:"events"` // "*" subscribes to every event
+	IsActive  bool       `gorm:"default:true;column:whs_is_active" json:"is_active"`
+	CreatedAt time.Time  `gorm:"column:whs_created_at" json:"created_at"`
+	CreatedBy string     `gorm:"column:whs_created_by" json:"created_by"`
+	UpdatedAt time.Time  `gorm:"column:whs_updated_at" json:"updated_at"`
+	UpdatedBy string     `gorm:"column:whs_updated_by" json:"updated_by"`
+}
+
+// TableName overrides the table name
+func (WebhookSubscription) TableName() string {
+	return "\"user\".webhook_subscriptions"
+}
+
+// Matches reports whether the subscription wants the event type
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	return s.Events.Contains("*") || s.Events.Contains(eventType)
+}
+
+// WebhookDelivery represents the webhook_deliveries table (one event sent to one subscription)
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey;column:whd_id" json:"id"`
+	SubscriptionID uint       `gorm:"column:whd_subscription_id;uniqueIndex:idx_whd_subscription_event" json:"subscription_id"`
+	OutboxEventID  uint       `gorm:"column:whd_outbox_event_id;uniqueIndex:idx_whd_subscription_event" json:"event_id"`
+	EventType      string     `gorm:"column:whd_event_type" json:"event_type"`
+	Payload        JSON       `gorm:"column:whd_payload;type:jsonb" json:"payload"` // the request body sent
+	Status         string     `gorm:"column:whd_status;index" json:"status"`
+	Attempts       int        `gorm:"column:whd_attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"column:whd_next_attempt_at;index" json:"next_attempt_at"`
+	LastStatusCode int        `gorm:"column:whd_last_status_code" json:"last_status_code,omitempty"`
+	LastError      string     `gorm:"column:whd_last_error" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `gorm:"column:whd_delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"column:whd_created_at" json:"created_at"`
+	// Relations
+	Subscription *WebhookSubscription     `gorm:"foreignKey:whd_subscription_id;references:whs_id" json:"-"`
+	AttemptLog   []WebhookDeliveryAttempt `gorm:"foreignKey:wha_delivery_id;references:whd_id" json:"attempt_log,omitempty"`
+}
+
+// TableName overrides the table name
+func (WebhookDelivery) TableName() string {
+	return "\"user\".webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt represents the webhook_delivery_attempts table (the delivery log)
+type WebhookDeliveryAttempt struct {
+	ID          uint      `gorm:"primaryKey;column:wha_id" json:"id"`
+	DeliveryID  uint      `gorm:"column:wha_delivery_id;index" json:"delivery_id"`
+	AttemptedAt time.Time `gorm:"column:wha_attempted_at" json:"attempted_at"`
+	StatusCode  int       `gorm:"column:wha_status_code" json:"status_code,omitempty"`
+	Error       string    `gorm:"column:wha_error" json:"error,omitempty"`
+	DurationMs  int64     `gorm:"column:wha_duration_ms" json:"duration_ms"`
+}
+
+// TableName overrides the table name
+func (WebhookDeliveryAttempt) TableName() string {
+	return "\"user\".webhook_delivery_attempts"
+}
+
+// WebhookSubscriptionRequest represents payload for creating/updating a webhook subscription
+type WebhookSubscriptionRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	URL      string   `json:"url" binding:"required,url"`
+	Secret   string   `json:"secret"` // required on create, kept when empty on update
+	Events   []string `json:"events" binding:"required,min=1"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// WebhookEnvelope represents the JSON body posted to a webhook subscriber
+type WebhookEnvelope struct {
+	ID         uint      `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       JSON      `json:"data"`
+}
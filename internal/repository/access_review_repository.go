@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AccessReviewRepository handles access review database operations
+type AccessReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessReviewRepository creates a new access review repository
+func NewAccessReviewRepository(db *gorm.DB) *AccessReviewRepository {
+	return &AccessReviewRepository{
+		db: db,
+	}
+}
+
+// CreateCampaign creates a campaign and generates one review item per effective
+// user-role pair in its scope, assigned to the user's manager
+func (r *AccessReviewRepository) CreateCampaign(campaign *models.AccessReviewCampaign, createdBy string) error {
+	// Set creation info
+	campaign.CreatedAt = time.Now()
+	campaign.CreatedBy = createdBy
+	campaign.Status = models.AccessReviewStatusOpen
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO "user".access_review_items
+				(ari_campaign_id, ari_user_id, ari_role_id, ari_reviewer_id, ari_decision, ari_created_at)
+			SELECT ?, u.u_id, ur.ur_role_id, u.u_manager_id, ?, ?
+			FROM "user".user_roles ur
+			JOIN "user".users u ON u.u_id = ur.ur_user_id
+			WHERE u.u_is_active = true AND ` + effectiveUserRoleCondition
+		args := []interface{}{campaign.ID, models.AccessReviewDecisionPending, campaign.CreatedAt}
+
+		// Apply scope
+		if campaign.RoleID != nil {
+			query += " AND ur.ur_role_id = ?"
+			args = append(args, *campaign.RoleID)
+		}
+		if campaign.DivisionID != nil {
+			query += " AND u.u_division_id = ?"
+			args = append(args, *campaign.DivisionID)
+		}
+
+		return tx.Exec(query, args...).Error
+	})
+}
+
+// FindCampaignByID finds a campaign by ID
+func (r *AccessReviewRepository) FindCampaignByID(id uint) (*models.AccessReviewCampaign, error) {
+	var campaign models.AccessReviewCampaign
+	result := r.db.Preload("Role").Preload("Division").First(&campaign, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &campaign, nil
+}
+
+// ListCampaigns lists campaigns with pagination
+func (r *AccessReviewRepository) ListCampaigns(page, limit int, status string) (*models.PaginatedResponse, error) {
+	var campaigns []models.AccessReviewCampaign
+	var totalItems int64
+
+	// Base query
+	query := r.db.Model(&models.AccessReviewCampaign{}).Preload("Role").Preload("Division")
+
+	// Apply status filter if provided
+	if status != "" {
+		query = query.Where("arc_status = ?", status)
+	}
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (page - 1) * limit
+	if err := query.Order("arc_created_at DESC").Offset(offset).Limit(limit).Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + int64(limit) - 1) / int64(limit)
+
+	// Create response
+	response := &models.PaginatedResponse{
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: int64(page),
+		PageSize:    int64(limit),
+		Items:       campaigns,
+	}
+
+	return response, nil
+}
+
+// CountItemsByDecision counts a campaign's items per decision
+func (r *AccessReviewRepository) CountItemsByDecision(campaignID uint) (map[string]int64, error) {
+	var rows []struct {
+		Decision string
+		Count    int64
+	}
+	err := r.db.Model(&models.AccessReviewItem{}).
+		Select("ari_decision as decision, COUNT(*) as count").
+		Where("ari_campaign_id = ?", campaignID).
+		Group("ari_decision").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		counts[row.Decision] = row.Count
+	}
+	return counts, nil
+}
+
+// ListItems lists a campaign's items, optionally filtered by decision and reviewer
+func (r *AccessReviewRepository) ListItems(campaignID uint, decision string, reviewerID uint) ([]models.AccessReviewItem, error) {
+	var items []models.AccessReviewItem
+
+	// Base query
+	query := r.db.Preload("User.Division").Preload("Role").Preload("Reviewer").
+		Where("ari_campaign_id = ?", campaignID)
+
+	// Apply filters if provided
+	if decision != "" {
+		query = query.Where("ari_decision = ?", decision)
+	}
+	if reviewerID != 0 {
+		query = query.Where("ari_reviewer_id = ?", reviewerID)
+	}
+
+	if err := query.Order("ari_id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListReviewerItems lists pending items of open campaigns assigned to a reviewer
+func (r *AccessReviewRepository) ListReviewerItems(reviewerID uint) ([]models.AccessReviewItem, error) {
+	var items []models.AccessReviewItem
+	err := r.db.Preload("User").Preload("Role").
+		Joins(`JOIN "user".access_review_campaigns arc ON arc.arc_id = "user".access_review_items.ari_campaign_id`).
+		Where("ari_reviewer_id = ? AND ari_decision = ? AND arc.arc_status = ?",
+			reviewerID, models.AccessReviewDecisionPending, models.AccessReviewStatusOpen).
+		Order("ari_id").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindItemByID finds an item by ID together with its campaign
+func (r *AccessReviewRepository) FindItemByID(id uint) (*models.AccessReviewItem, error) {
+	var item models.AccessReviewItem
+	result := r.db.Preload("Campaign").Preload("User").Preload("Role").First(&item, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// DecideItem records a reviewer's decision on an item
+func (r *AccessReviewRepository) DecideItem(id uint, decision, comment, decidedBy string) error {
+	return r.db.Model(&models.AccessReviewItem{}).Where("ari_id = ?", id).Updates(map[string]interface{}{
+		"ari_decision":   decision,
+		"ari_comment":    comment,
+		"ari_decided_at": time.Now(),
+		"ari_decided_by": decidedBy,
+	}).Error
+}
+
+// ListUnappliedRevocations lists the revoked items of a campaign that were not applied yet
+func (r *AccessReviewRepository) ListUnappliedRevocations(campaignID uint) ([]models.AccessReviewItem, error) {
+	var items []models.AccessReviewItem
+	err := r.db.Where("ari_campaign_id = ? AND ari_decision = ? AND ari_applied_at IS NULL",
+		campaignID, models.AccessReviewDecisionRevoked).
+		Order("ari_id").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkItemApplied records when a revoked item was applied
+func (r *AccessReviewRepository) MarkItemApplied(id uint, appliedAt time.Time) error {
+	return r.db.Model(&models.AccessReviewItem{}).Where("ari_id = ?", id).
+		Update("ari_applied_at", appliedAt).Error
+}
+
+// CloseCampaign marks a campaign as closed
+func (r *AccessReviewRepository) CloseCampaign(campaignID uint, closedAt time.Time, closedBy string) error {
+	return r.db.Model(&models.AccessReviewCampaign{}).Where("arc_id = ?", campaignID).Updates(map[string]interface{}{
+		"arc_status":    models.AccessReviewStatusClosed,
+		"arc_closed_at": closedAt,
+		"arc_closed_by": closedBy,
+	}).Error
+}
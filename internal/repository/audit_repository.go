@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository handles audit log database operations
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+// Record records an audit log entry
+func (r *AuditRepository) Record(entry *models.AuditLog) error {
+	entry.CreatedAt = time.Now()
+	return r.db.Create(entry).Error
+}
+
+// List lists audit log entries with pagination, optionally filtered by user or impersonation
+func (r *AuditRepository) List(page, limit int, userID uint, impersonatedOnly bool) (*models.PaginatedResponse, error) {
+	var entries []models.AuditLog
+	var totalItems int64
+
+	// Base query
+	query := r.db.Model(&models.AuditLog{})
+
+	// Apply filters if provided
+	if userID != 0 {
+		query = query.Where("al_user_id = ? OR al_impersonator_id = ?", userID, userID)
+	}
+
+	if impersonatedOnly {
+		query = query.Where("al_impersonator_id IS NOT NULL")
+	}
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (page - 1) * limit
+	if err := query.Order("al_created_at DESC").Offset(offset).Limit(limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + int64(limit) - 1) / int64(limit)
+
+	// Create response
+	response := &models.PaginatedResponse{
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: int64(page),
+		PageSize:    int64(limit),
+		Items:       entries,
+	}
+
+	return response, nil
+}
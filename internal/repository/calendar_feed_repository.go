@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarFeedRepository handles calendar feed database operations
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository(db *gorm.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		db: db,
+	}
+}
+
+// FindByTokenHash finds a feed by the hash of its token, with its user
+func (r *CalendarFeedRepository) FindByTokenHash(tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	result := r.db.Preload("User").Where("cf_token_hash = ?", tokenHash).First(&feed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &feed, nil
+}
+
+// Save creates a user's feed, replacing the token of an existing one
+func (r *CalendarFeedRepository) Save(feed *models.CalendarFeed, createdBy string) error {
+	// Set creation info
+	feed.CreatedAt = time.Now()
+	feed.CreatedBy = createdBy
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cf_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cf_token_hash", "cf_created_at", "cf_created_by"}),
+	}).Create(feed).Error
+}
+
+// DeleteByUser deletes a user's feed
+func (r *CalendarFeedRepository) DeleteByUser(userID uint) error {
+	result := r.db.Where("cf_user_id = ?", userID).Delete(&models.CalendarFeed{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ChangeRequestRepository handles change request database operations
+type ChangeRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewChangeRequestRepository creates a new change request repository
+func NewChangeRequestRepository(db *gorm.DB) *ChangeRequestRepository {
+	return &ChangeRequestRepository{
+		db: db,
+	}
+}
+
+// Create creates a new pending change request
+func (r *ChangeRequestRepository) Create(changeRequest *models.ChangeRequest) error {
+	changeRequest.CreatedAt = time.Now()
+	changeRequest.Status = models.ChangeRequestStatusPending
+	return r.db.Create(changeRequest).Error
+}
+
+// FindByID finds a change request by ID with its target user and comments
+func (r *ChangeRequestRepository) FindByID(id uint) (*models.ChangeRequest, error) {
+	var changeRequest models.ChangeRequest
+	result := r.db.Preload("TargetUser").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("crc_created_at")
+		}).
+		First(&changeRequest, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &changeRequest, nil
+}
+
+// List lists change requests with pagination
+func (r *ChangeRequestRepository) List(page, limit int, status string, targetUserID uint) (*models.PaginatedResponse, error) {
+	var changeRequests []models.ChangeRequest
+	var totalItems int64
+
+	// Base query
+	query := r.db.Model(&models.ChangeRequest{}).Preload("TargetUser")
+
+	// Apply filters if provided
+	if status != "" {
+		query = query.Where("cr_status = ?", status)
+	}
+	if targetUserID != 0 {
+		query = query.Where("cr_target_user_id = ?", targetUserID)
+	}
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (page - 1) * limit
+	if err := query.Order("cr_created_at DESC").Offset(offset).Limit(limit).Find(&changeRequests).Error; err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + int64(limit) - 1) / int64(limit)
+
+	// Create response
+	response := &models.PaginatedResponse{
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: int64(page),
+		PageSize:    int64(limit),
+		Items:       changeRequests,
+	}
+
+	return response, nil
+}
+
+// Approve applies a pending change request and marks it approved in a single transaction.
+// The apply callback receives the transaction so the change rolls back if the status update fails.
+func (r *ChangeRequestRepository) Approve(id uint, decidedBy, comment string, apply func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := apply(tx); err != nil {
+			return err
+		}
+		return decide(tx, id, models.ChangeRequestStatusApproved, decidedBy, comment)
+	})
+}
+
+// Reject marks a pending change request rejected
+func (r *ChangeRequestRepository) Reject(id uint, decidedBy, comment string) error {
+	return decide(r.db, id, models.ChangeRequestStatusRejected, decidedBy, comment)
+}
+
+// AddComment adds a comment to a change request
+func (r *ChangeRequestRepository) AddComment(comment *models.ChangeRequestComment) error {
+	comment.CreatedAt = time.Now()
+	return r.db.Create(comment).Error
+}
+
+// decide moves a pending change request to its final status, failing if it was decided concurrently
+func decide(db *gorm.DB, id uint, status, decidedBy, comment string) error {
+	result := db.Model(&models.ChangeRequest{}).
+		Where("cr_id = ? AND cr_status = ?", id, models.ChangeRequestStatusPending).
+		Updates(map[string]interface{}{
+			"cr_status":           status,
+			"cr_decided_at":       time.Now(),
+			"cr_decided_by":       decidedBy,
+			"cr_decision_comment": comment,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("change request is no longer pending")
+	}
+	return nil
+}
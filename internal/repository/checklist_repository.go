@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ChecklistRepository handles checklist template and checklist database operations
+type ChecklistRepository struct {
+	db *gorm.DB
+}
+
+// NewChecklistRepository creates a new checklist repository
+func NewChecklistRepository(db *gorm.DB) *ChecklistRepository {
+	return &ChecklistRepository{
+		db: db,
+	}
+}
+
+// FindTemplateByID finds a template by ID with its tasks
+func (r *ChecklistRepository) FindTemplateByID(id uint) (*models.ChecklistTemplate, error) {
+	var template models.ChecklistTemplate
+	result := r.templateQuery().First(&template, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &template, nil
+}
+
+// ListTemplates lists the templates of a kind, or of every kind when kind is empty
+func (r *ChecklistRepository) ListTemplates(kind string) ([]models.ChecklistTemplate, error) {
+	var templates []models.ChecklistTemplate
+	query := r.templateQuery().Order("ct_kind, ct_name")
+	if kind != "" {
+		query = query.Where("ct_kind = ?", kind)
+	}
+	if err := query.Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// FindMatchingTemplates finds the active templates of a kind that apply to a division and position.
+// Templates without a division or position apply to every division or position.
+func (r *ChecklistRepository) FindMatchingTemplates(kind string, divisionID, positionID *uint) ([]models.ChecklistTemplate, error) {
+	var templates []models.ChecklistTemplate
+	query := r.db.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("ctt_position")
+	}).Where("ct_kind = ? AND ct_is_active = ?", kind, true)
+
+	if divisionID != nil {
+		query = query.Where("ct_division_id IS NULL OR ct_division_id = ?", *divisionID)
+	} else {
+		query = query.Where("ct_division_id IS NULL")
+	}
+	if positionID != nil {
+		query = query.Where("ct_position_id IS NULL OR ct_position_id = ?", *positionID)
+	} else {
+		query = query.Where("ct_position_id IS NULL")
+	}
+
+	if err := query.Order("ct_id").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// CreateTemplate creates a template with its tasks
+func (r *ChecklistRepository) CreateTemplate(template *models.ChecklistTemplate, createdBy string) error {
+	// Set creation info
+	now := time.Now()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	template.CreatedBy = createdBy
+	template.UpdatedBy = createdBy
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tasks", "Division", "Position").Create(template).Error; err != nil {
+			return err
+		}
+		return setTemplateTasks(tx, template)
+	})
+}
+
+// UpdateTemplate updates a template and replaces its tasks. Checklists already created from the
+// template keep their tasks.
+func (r *ChecklistRepository) UpdateTemplate(template *models.ChecklistTemplate, updatedBy string) error {
+	// Set update info
+	template.UpdatedAt = time.Now()
+	template.UpdatedBy = updatedBy
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ChecklistTemplate{}).Where("ct_id = ?", template.ID).Updates(map[string]interface{}{
+			"ct_name":        template.Name,
+			"ct_kind":        template.Kind,
+			"ct_division_id": template.DivisionID,
+			"ct_position_id": template.PositionID,
+			"ct_is_active":   template.IsActive,
+			"ct_updated_at":  template.UpdatedAt,
+			"ct_updated_by":  template.UpdatedBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("ctt_template_id = ?", template.ID).Delete(&models.ChecklistTemplateTask{}).Error; err != nil {
+			return err
+		}
+		return setTemplateTasks(tx, template)
+	})
+}
+
+// DeleteTemplate deletes a template with its tasks. Checklists created from it are kept.
+func (r *ChecklistRepository) DeleteTemplate(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Checklist{}).Where("cl_template_id = ?", id).Update("cl_template_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ctt_template_id = ?", id).Delete(&models.ChecklistTemplateTask{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.ChecklistTemplate{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// CreateChecklist creates a checklist with its tasks
+func (r *ChecklistRepository) CreateChecklist(checklist *models.Checklist) error {
+	checklist.CreatedAt = time.Now()
+	return r.db.Create(checklist).Error
+}
+
+// ListByUser lists the checklists of a user with their tasks, newest first
+func (r *ChecklistRepository) ListByUser(userID uint) ([]models.Checklist, error) {
+	var checklists []models.Checklist
+	if err := r.db.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("clt_position")
+	}).Preload("Tasks.Role").
+		Where("cl_user_id = ?", userID).
+		Order("cl_created_at DESC, cl_id DESC").
+		Find(&checklists).Error; err != nil {
+		return nil, err
+	}
+	return checklists, nil
+}
+
+// FindTaskByID finds a checklist task by ID with its role
+func (r *ChecklistRepository) FindTaskByID(id uint) (*models.ChecklistTask, error) {
+	var task models.ChecklistTask
+	result := r.db.Preload("Role").First(&task, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &task, nil
+}
+
+// FindChecklistByID finds a checklist by ID without its tasks
+func (r *ChecklistRepository) FindChecklistByID(id uint) (*models.Checklist, error) {
+	var checklist models.Checklist
+	result := r.db.First(&checklist, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &checklist, nil
+}
+
+// SetTaskCompletion marks a task as completed, or as open again when completedAt is nil, and
+// updates the completion of its checklist
+func (r *ChecklistRepository) SetTaskCompletion(task *models.ChecklistTask, completedAt *time.Time, completedBy string) error {
+	// Run in a transaction (nested as a savepoint when the repository is bound to one)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ChecklistTask{}).Where("clt_id = ?", task.ID).Updates(map[string]interface{}{
+			"clt_completed_at": completedAt,
+			"clt_completed_by": completedBy,
+		}).Error; err != nil {
+			return err
+		}
+		task.CompletedAt = completedAt
+		task.CompletedBy = completedBy
+
+		var open int64
+		if err := tx.Model(&models.ChecklistTask{}).
+			Where("clt_checklist_id = ? AND clt_completed_at IS NULL", task.ChecklistID).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		var checklistCompletedAt *time.Time
+		if open == 0 {
+			now := time.Now()
+			checklistCompletedAt = &now
+		}
+		return tx.Model(&models.Checklist{}).Where("cl_id = ?", task.ChecklistID).
+			Update("cl_completed_at", checklistCompletedAt).Error
+	})
+}
+
+// ListTasks lists checklist tasks with the user they are for, ordered by due date
+func (r *ChecklistRepository) ListTasks(filter *models.ChecklistTaskFilter, today time.Time) ([]models.ChecklistTaskItem, error) {
+	var tasks []models.ChecklistTaskItem
+
+	// Base query
+	query := r.db.Table("\"user\".checklist_tasks AS t").
+		Select("t.*, c.cl_kind AS kind, c.cl_name AS checklist_name, u.u_id AS user_id, " +
+			"u.u_employee_id AS employee_id, u.u_name AS user_name, COALESCE(r.role_name, '') AS role_name").
+		Joins("JOIN \"user\".checklists c ON c.cl_id = t.clt_checklist_id").
+		Joins("JOIN \"user\".users u ON u.u_id = c.cl_user_id").
+		Joins("LEFT JOIN \"user\".roles r ON r.role_id = t.clt_role_id")
+
+	// Apply filters if provided
+	switch filter.Status {
+	case "open":
+		query = query.Where("t.clt_completed_at IS NULL")
+	case "overdue":
+		query = query.Where("t.clt_completed_at IS NULL AND t.clt_due_date < ?", today.Format("2006-01-02"))
+	case "completed":
+		query = query.Where("t.clt_completed_at IS NOT NULL")
+	}
+	if filter.UserID != 0 {
+		query = query.Where("c.cl_user_id = ?", filter.UserID)
+	}
+	if filter.Mine {
+		if len(filter.ActorRoles) > 0 {
+			query = query.Where("t.clt_assignee_user_id = ? OR r.role_name IN ?", filter.ActorID, filter.ActorRoles)
+		} else {
+			query = query.Where("t.clt_assignee_user_id = ?", filter.ActorID)
+		}
+	}
+
+	if err := query.Order("t.clt_due_date, u.u_name, t.clt_position").Scan(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// DeleteByUser deletes every checklist of a user with its tasks
+func (r *ChecklistRepository) DeleteByUser(userID uint) error {
+	// Run in a transaction (nested as a savepoint when the repository is bound to one)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("clt_checklist_id IN (?)",
+			tx.Model(&models.Checklist{}).Select("cl_id").Where("cl_user_id = ?", userID),
+		).Delete(&models.ChecklistTask{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("cl_user_id = ?", userID).Delete(&models.Checklist{}).Error
+	})
+}
+
+// templateQuery returns a query that loads templates with their ordered tasks and relations
+func (r *ChecklistRepository) templateQuery() *gorm.DB {
+	return r.db.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("ctt_position")
+	}).Preload("Tasks.Role").Preload("Division").Preload("Position")
+}
+
+// setTemplateTasks creates the tasks of a template in order
+func setTemplateTasks(tx *gorm.DB, template *models.ChecklistTemplate) error {
+	for i := range template.Tasks {
+		template.Tasks[i].ID = 0
+		template.Tasks[i].TemplateID = template.ID
+		template.Tasks[i].Position = i
+		if err := tx.Omit("Role").Create(&template.Tasks[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
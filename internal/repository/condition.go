@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Condition represents a single column filter applied to a query.
+// Column names must come from a whitelist maintained by the caller.
+type Condition struct {
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+// allowedOperators lists the SQL operators a Condition may use
+var allowedOperators = map[string]bool{
+	"=":           true,
+	"<>":          true,
+	"ILIKE":       true,
+	"NOT ILIKE":   true,
+	"IS NOT NULL": true,
+	"IS NULL":     true,
+}
+
+// applyConditions adds the given conditions to the query
+func applyConditions(query *gorm.DB, conditions []Condition) (*gorm.DB, error) {
+	for _, condition := range conditions {
+		if !allowedOperators[condition.Operator] {
+			return nil, fmt.Errorf("unsupported operator %q", condition.Operator)
+		}
+
+		switch condition.Operator {
+		case "IS NOT NULL", "IS NULL":
+			query = query.Where(fmt.Sprintf("%s %s", condition.Column, condition.Operator))
+		default:
+			query = query.Where(fmt.Sprintf("%s %s ?", condition.Column, condition.Operator), condition.Value)
+		}
+	}
+	return query, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// customFieldColumns maps each custom field entity to its table and jsonb column
+var customFieldColumns = map[string]struct{ table, column string }{
+	models.CustomFieldEntityUser:     {"\"user\".users", "u_custom_fields"},
+	models.CustomFieldEntityDivision: {"\"user\".divisions", "div_custom_fields"},
+	models.CustomFieldEntityPosition: {"\"user\".positions", "pos_custom_fields"},
+}
+
+// CustomFieldRepository handles custom field definition database operations
+type CustomFieldRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository creates a new custom field repository
+func NewCustomFieldRepository(db *gorm.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a definition by ID
+func (r *CustomFieldRepository) FindByID(id uint) (*models.CustomFieldDefinition, error) {
+	var definition models.CustomFieldDefinition
+	result := r.db.First(&definition, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &definition, nil
+}
+
+// FindByKey finds an entity's definition by key
+func (r *CustomFieldRepository) FindByKey(entity, key string) (*models.CustomFieldDefinition, error) {
+	var definition models.CustomFieldDefinition
+	result := r.db.Where("cfd_entity = ? AND cfd_key = ?", entity, key).First(&definition)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &definition, nil
+}
+
+// List lists the definitions of an entity, or of every entity when entity is empty
+func (r *CustomFieldRepository) List(entity string) ([]models.CustomFieldDefinition, error) {
+	var definitions []models.CustomFieldDefinition
+	query := r.db.Order("cfd_entity, cfd_id")
+	if entity != "" {
+		query = query.Where("cfd_entity = ?", entity)
+	}
+	if err := query.Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// Create creates a definition
+func (r *CustomFieldRepository) Create(definition *models.CustomFieldDefinition, createdBy string) error {
+	// Set creation info
+	now := time.Now()
+	definition.CreatedAt = now
+	definition.UpdatedAt = now
+	definition.CreatedBy = createdBy
+	definition.UpdatedBy = createdBy
+
+	return r.db.Create(definition).Error
+}
+
+// Update updates a definition's label and validation rules
+func (r *CustomFieldRepository) Update(definition *models.CustomFieldDefinition, updatedBy string) error {
+	// Set update info
+	definition.UpdatedAt = time.Now()
+	definition.UpdatedBy = updatedBy
+
+	return r.db.Model(&models.CustomFieldDefinition{}).Where("cfd_id = ?", definition.ID).Updates(map[string]interface{}{
+		"cfd_label":      definition.Label,
+		"cfd_required":   definition.Required,
+		"cfd_options":    definition.Options,
+		"cfd_pattern":    definition.Pattern,
+		"cfd_min":        definition.Min,
+		"cfd_max":        definition.Max,
+		"cfd_updated_at": definition.UpdatedAt,
+		"cfd_updated_by": definition.UpdatedBy,
+	}).Error
+}
+
+// Delete deletes a definition and removes its value from every row of the entity
+func (r *CustomFieldRepository) Delete(definition *models.CustomFieldDefinition) error {
+	target := customFieldColumns[definition.Entity]
+
+	// Run in a transaction (nested as a savepoint when the repository is bound to one)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.CustomFieldDefinition{}, definition.ID).Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE "+target.table+" SET "+target.column+" = "+target.column+" - ?::text WHERE jsonb_exists("+target.column+", ?::text)",
+			definition.Key, definition.Key).Error
+	})
+}
@@ -58,13 +58,25 @@ func (r *DivisionRepository) Update(division *models.Division, updatedBy string)
 	division.UpdatedAt = time.Now()
 	division.UpdatedBy = updatedBy
 	
-	return r.db.Model(&models.Division{}).Where("div_id = ?", division.ID).Updates(map[string]interface{}{
-		"div_code":       division.Code,
-		"div_name":       division.Name,
-		"div_is_active":  division.IsActive,
-		"div_updated_at": division.UpdatedAt,
-		"div_updated_by": division.UpdatedBy,
-	}).Error
+	// Update division, unless it changed since it was read
+	result := r.db.Model(&models.Division{}).Where("div_id = ? AND div_version = ?", division.ID, division.Version).Updates(map[string]interface{}{
+		"div_code":          division.Code,
+		"div_name":          division.Name,
+		"div_is_active":     division.IsActive,
+		"div_custom_fields": division.CustomFields,
+		"div_updated_at":    division.UpdatedAt,
+		"div_updated_by":    division.UpdatedBy,
+		"div_version":       gorm.Expr("div_version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	division.Version++
+	return nil
 }
 
 // Delete deletes a division
@@ -82,7 +94,10 @@ func (r *DivisionRepository) Delete(id uint) error {
 	}
 	
 	if count > 0 {
-		return r.db.Model(&models.Division{}).Where("div_id = ?", id).Update("div_is_active", false).Error
+		return r.db.Model(&models.Division{}).Where("div_id = ?", id).Updates(map[string]interface{}{
+			"div_is_active": false,
+			"div_version":   gorm.Expr("div_version + 1"),
+		}).Error
 	}
 	
 	// Delete the division
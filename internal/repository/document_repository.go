@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DocumentRepository handles user document database operations
+type DocumentRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentRepository creates a new document repository
+func NewDocumentRepository(db *gorm.DB) *DocumentRepository {
+	return &DocumentRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a user's document by ID
+func (r *DocumentRepository) FindByID(userID, id uint) (*models.UserDocument, error) {
+	var document models.UserDocument
+	result := r.db.Where("ud_user_id = ?", userID).First(&document, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
+// ListByUser lists a user's documents with one of the given confidentiality levels
+func (r *DocumentRepository) ListByUser(userID uint, levels []string) ([]models.UserDocument, error) {
+	var documents []models.UserDocument
+	if err := r.db.Where("ud_user_id = ? AND ud_confidentiality IN ?", userID, levels).
+		Order("ud_created_at DESC").
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// ListAllByUser lists every document of a user regardless of confidentiality
+func (r *DocumentRepository) ListAllByUser(userID uint) ([]models.UserDocument, error) {
+	var documents []models.UserDocument
+	if err := r.db.Where("ud_user_id = ?", userID).Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// FindExpiring lists documents with one of the given confidentiality levels that expire between
+// from and to (inclusive dates), soonest first
+func (r *DocumentRepository) FindExpiring(from, to time.Time, levels []string) ([]models.ExpiringDocument, error) {
+	var documents []models.ExpiringDocument
+	if err := r.db.Table("\"user\".user_documents AS ud").
+		Select("ud.*, u.u_employee_id AS employee_id, u.u_name AS user_name").
+		Joins("JOIN \"user\".users u ON u.u_id = ud.ud_user_id").
+		Where("ud.ud_expires_at BETWEEN ? AND ? AND ud.ud_confidentiality IN ?",
+			from.Format("2006-01-02"), to.Format("2006-01-02"), levels).
+		Order("ud.ud_expires_at, ud.ud_id").
+		Scan(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// Create creates a document
+func (r *DocumentRepository) Create(document *models.UserDocument, createdBy string) error {
+	// Set creation info
+	document.CreatedAt = time.Now()
+	document.CreatedBy = createdBy
+
+	return r.db.Create(document).Error
+}
+
+// Delete deletes a document
+func (r *DocumentRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.UserDocument{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByUser deletes every document of a user
+func (r *DocumentRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("ud_user_id = ?", userID).Delete(&models.UserDocument{}).Error
+}
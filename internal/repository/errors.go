@@ -0,0 +1,6 @@
+package repository
+
+import "errors"
+
+// ErrVersionConflict is returned when a versioned record was changed after it was read
+var ErrVersionConflict = errors.New("the record was modified by another request")
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository handles idempotency key database operations
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Begin reserves a key for a request. It returns nil when the key was reserved, or the record
+// already stored under the key.
+func (r *IdempotencyRepository) Begin(record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	// An expired key can be used again
+	if err := r.db.Where("ik_scope = ? AND ik_key = ? AND ik_expires_at <= ?", record.Scope, record.Key, time.Now()).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return nil, err
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyKey
+	if err := r.db.Where("ik_scope = ? AND ik_key = ?", record.Scope, record.Key).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// Complete stores the response of a reserved key
+func (r *IdempotencyRepository) Complete(record *models.IdempotencyKey) error {
+	now := time.Now()
+	record.CompletedAt = &now
+
+	return r.db.Model(&models.IdempotencyKey{}).Where("ik_id = ?", record.ID).Updates(map[string]interface{}{
+		"ik_status_code":   record.StatusCode,
+		"ik_content_type":  record.ContentType,
+		"ik_response_body": record.ResponseBody,
+		"ik_completed_at":  record.CompletedAt,
+	}).Error
+}
+
+// Release deletes a reserved key whose request failed, so that it can be retried
+func (r *IdempotencyRepository) Release(record *models.IdempotencyKey) error {
+	return r.db.Delete(&models.IdempotencyKey{}, record.ID).Error
+}
+
+// DeleteExpired deletes the keys that expired by now
+func (r *IdempotencyRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("ik_expires_at <= ?", now).Delete(&models.IdempotencyKey{}).Error
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OffboardingRepository handles offboarding database operations
+type OffboardingRepository struct {
+	db *gorm.DB
+}
+
+// NewOffboardingRepository creates a new offboarding repository
+func NewOffboardingRepository(db *gorm.DB) *OffboardingRepository {
+	return &OffboardingRepository{
+		db: db,
+	}
+}
+
+// FindByID finds an offboarding by ID
+func (r *OffboardingRepository) FindByID(id uint) (*models.Offboarding, error) {
+	var offboarding models.Offboarding
+	result := r.db.First(&offboarding, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &offboarding, nil
+}
+
+// FindScheduledByUser finds the offboarding scheduled for a user
+func (r *OffboardingRepository) FindScheduledByUser(userID uint) (*models.Offboarding, error) {
+	var offboarding models.Offboarding
+	result := r.db.Where("ofb_user_id = ? AND ofb_status = ?", userID, models.OffboardingScheduled).First(&offboarding)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &offboarding, nil
+}
+
+// List lists offboardings, optionally filtered by user and status, newest effective date first
+func (r *OffboardingRepository) List(userID uint, status string) ([]models.Offboarding, error) {
+	var offboardings []models.Offboarding
+
+	// Base query
+	query := r.db.Order("ofb_effective_at DESC, ofb_id DESC")
+
+	// Apply filters if provided
+	if userID != 0 {
+		query = query.Where("ofb_user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("ofb_status = ?", status)
+	}
+
+	if err := query.Find(&offboardings).Error; err != nil {
+		return nil, err
+	}
+	return offboardings, nil
+}
+
+// FindDue lists scheduled offboardings whose effective date has come
+func (r *OffboardingRepository) FindDue(now time.Time, limit int) ([]models.Offboarding, error) {
+	var offboardings []models.Offboarding
+	if err := r.db.Where("ofb_status = ? AND ofb_effective_at <= ?", models.OffboardingScheduled, now).
+		Order("ofb_effective_at, ofb_id").
+		Limit(limit).
+		Find(&offboardings).Error; err != nil {
+		return nil, err
+	}
+	return offboardings, nil
+}
+
+// Create creates an offboarding
+func (r *OffboardingRepository) Create(offboarding *models.Offboarding, createdBy string) error {
+	// Set creation info
+	offboarding.CreatedAt = time.Now()
+	offboarding.CreatedBy = createdBy
+
+	return r.db.Create(offboarding).Error
+}
+
+// Complete records the outcome of a scheduled offboarding. It returns gorm.ErrRecordNotFound
+// when the offboarding is no longer scheduled.
+func (r *OffboardingRepository) Complete(offboarding *models.Offboarding) error {
+	result := r.db.Model(&models.Offboarding{}).
+		Where("ofb_id = ? AND ofb_status = ?", offboarding.ID, models.OffboardingScheduled).
+		Updates(map[string]interface{}{
+			"ofb_status":             offboarding.Status,
+			"ofb_successor_id":       offboarding.SuccessorID,
+			"ofb_reassigned_reports": offboarding.ReassignedReports,
+			"ofb_revoked_roles":      offboarding.RevokedRoles,
+			"ofb_last_error":         offboarding.LastError,
+			"ofb_completed_at":       offboarding.CompletedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Cancel cancels a scheduled offboarding. It returns gorm.ErrRecordNotFound when the
+// offboarding is no longer scheduled.
+func (r *OffboardingRepository) Cancel(id uint, cancelledBy string) error {
+	result := r.db.Model(&models.Offboarding{}).
+		Where("ofb_id = ? AND ofb_status = ?", id, models.OffboardingScheduled).
+		Updates(map[string]interface{}{
+			"ofb_status":       models.OffboardingCancelled,
+			"ofb_cancelled_at": time.Now(),
+			"ofb_cancelled_by": cancelledBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
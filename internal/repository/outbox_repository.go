@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository handles outbox database operations
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// Transaction runs fn in a transaction; repositories created on tx commit together with the outbox writes
+func (r *OutboxRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+// Append writes an event to the outbox
+func (r *OutboxRepository) Append(event *models.OutboxEvent) error {
+	event.OccurredAt = time.Now()
+	return r.db.Create(event).Error
+}
+
+// ProcessBatch locks up to limit unprocessed events in order, hands them to handle inside the
+// transaction and marks them processed. Concurrent workers skip each other's locked rows.
+func (r *OutboxRepository) ProcessBatch(limit int, handle func(tx *gorm.DB, events []models.OutboxEvent) error) (int, error) {
+	var processed int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var events []models.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("ob_processed_at IS NULL").
+			Order("ob_id").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := handle(tx, events); err != nil {
+			return err
+		}
+
+		ids := make([]uint, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		if err := tx.Model(&models.OutboxEvent{}).Where("ob_id IN ?", ids).
+			Update("ob_processed_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		processed = len(events)
+		return nil
+	})
+	return processed, err
+}
+
+// LatestID returns the ID of the most recent outbox event, or 0 when there is none
+func (r *OutboxRepository) LatestID() (uint, error) {
+	var id uint
+	if err := r.db.Model(&models.OutboxEvent{}).Select("COALESCE(MAX(ob_id), 0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
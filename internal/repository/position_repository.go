@@ -58,13 +58,25 @@ func (r *PositionRepository) Update(position *models.Position, updatedBy string)
 	position.UpdatedAt = time.Now()
 	position.UpdatedBy = updatedBy
 	
-	return r.db.Model(&models.Position{}).Where("pos_id = ?", position.ID).Updates(map[string]interface{}{
-		"pos_code":       position.Code,
-		"pos_name":       position.Name,
-		"pos_is_active":  position.IsActive,
-		"pos_updated_at": position.UpdatedAt,
-		"pos_updated_by": position.UpdatedBy,
-	}).Error
+	// Update position, unless it changed since it was read
+	result := r.db.Model(&models.Position{}).Where("pos_id = ? AND pos_version = ?", position.ID, position.Version).Updates(map[string]interface{}{
+		"pos_code":          position.Code,
+		"pos_name":          position.Name,
+		"pos_is_active":     position.IsActive,
+		"pos_custom_fields": position.CustomFields,
+		"pos_updated_at":    position.UpdatedAt,
+		"pos_updated_by":    position.UpdatedBy,
+		"pos_version":       gorm.Expr("pos_version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	position.Version++
+	return nil
 }
 
 // Delete deletes a position
@@ -82,7 +94,10 @@ func (r *PositionRepository) Delete(id uint) error {
 	}
 	
 	if count > 0 {
-		return r.db.Model(&models.Position{}).Where("pos_id = ?", id).Update("pos_is_active", false).Error
+		return r.db.Model(&models.Position{}).Where("pos_id = ?", id).Updates(map[string]interface{}{
+			"pos_is_active": false,
+			"pos_version":   gorm.Expr("pos_version + 1"),
+		}).Error
 	}
 	
 	// Delete the position
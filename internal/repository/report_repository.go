@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// maxReportRows limits the number of groups a report returns
+const maxReportRows = 1000
+
+// Joins used by report dimensions, keyed so each is added once
+const (
+	reportJoinDivision = `LEFT JOIN "user".divisions d ON d.div_id = u.u_division_id`
+	reportJoinPosition = `LEFT JOIN "user".positions p ON p.pos_id = u.u_position_id`
+	reportJoinManager  = `LEFT JOIN "user".users m ON m.u_id = u.u_manager_id`
+	reportJoinRole     = `JOIN "user".user_roles ur ON ur.ur_user_id = u.u_id AND ` + effectiveUserRoleCondition + `
+        JOIN "user".roles r ON r.role_id = ur.ur_role_id`
+)
+
+// reportDimension is the SQL behind a whitelisted group-by dimension
+type reportDimension struct {
+	expr string
+	join string
+}
+
+// reportDimensions maps dimension names to SQL; nothing outside these maps reaches the query text
+var reportDimensions = map[string]reportDimension{
+	"division":  {expr: "COALESCE(d.div_name, 'Unassigned')", join: reportJoinDivision},
+	"position":  {expr: "COALESCE(p.pos_name, 'Unassigned')", join: reportJoinPosition},
+	"role":      {expr: "r.role_name", join: reportJoinRole},
+	"manager":   {expr: "COALESCE(m.u_name, 'None')", join: reportJoinManager},
+	"join_year": {expr: "CAST(EXTRACT(YEAR FROM u.u_join_date) AS integer)"},
+}
+
+// reportFilterColumns maps filter fields to columns; role_id is matched through user_roles
+var reportFilterColumns = map[string]string{
+	"division_id": "u.u_division_id",
+	"position_id": "u.u_position_id",
+	"manager_id":  "u.u_manager_id",
+	"is_active":   "u.u_is_active",
+	"is_manager":  "u.u_is_manager",
+	"join_date":   "u.u_join_date",
+}
+
+// reportOperators maps filter operators to SQL
+var reportOperators = map[string]string{
+	"eq":  "=",
+	"neq": "<>",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+	"in":  "IN",
+}
+
+// reportMetrics maps metrics to aggregate expressions
+var reportMetrics = map[string]string{
+	models.ReportMetricCount:     "CAST(COUNT(DISTINCT u.u_id) AS double precision)",
+	models.ReportMetricAvgTenure: "CAST(COALESCE(AVG((COALESCE(CAST(u.u_deactivated_at AS date), CURRENT_DATE) - u.u_join_date) / 365.25), 0) AS double precision)",
+}
+
+// ReportRepository handles saved reports and executes report definitions
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a report definition by ID
+func (r *ReportRepository) FindByID(id uint) (*models.ReportDefinition, error) {
+	var report models.ReportDefinition
+	result := r.db.First(&report, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+// ListByOwner lists a user's report definitions
+func (r *ReportRepository) ListByOwner(ownerID uint, pinnedOnly bool) ([]models.ReportDefinition, error) {
+	var reports []models.ReportDefinition
+	query := r.db.Where("rd_owner_id = ?", ownerID)
+	if pinnedOnly {
+		query = query.Where("rd_is_pinned = ?", true)
+	}
+	if err := query.Order("rd_widget_order, rd_id").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Create creates a report definition
+func (r *ReportRepository) Create(report *models.ReportDefinition, createdBy string) error {
+	// Set creation info
+	now := time.Now()
+	report.CreatedAt = now
+	report.UpdatedAt = now
+	report.CreatedBy = createdBy
+	report.UpdatedBy = createdBy
+
+	return r.db.Create(report).Error
+}
+
+// Update updates a report definition
+func (r *ReportRepository) Update(report *models.ReportDefinition, updatedBy string) error {
+	// Set update info
+	report.UpdatedAt = time.Now()
+	report.UpdatedBy = updatedBy
+
+	return r.db.Model(&models.ReportDefinition{}).Where("rd_id = ?", report.ID).Updates(map[string]interface{}{
+		"rd_name":         report.Name,
+		"rd_entity":       report.Entity,
+		"rd_group_by":     report.GroupBy,
+		"rd_filters":      report.Filters,
+		"rd_metric":       report.Metric,
+		"rd_is_pinned":    report.IsPinned,
+		"rd_widget_order": report.WidgetOrder,
+		"rd_updated_at":   report.UpdatedAt,
+		"rd_updated_by":   report.UpdatedBy,
+	}).Error
+}
+
+// Delete deletes a report definition
+func (r *ReportRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ReportDefinition{}, id).Error
+}
+
+// Run executes a report definition. The query is assembled only from the whitelists above;
+// filter values are always bound as parameters.
+func (r *ReportRepository) Run(report *models.ReportDefinition) ([]models.ReportRow, error) {
+	if report.Entity != models.ReportEntityUsers {
+		return nil, fmt.Errorf("unknown report entity: %s", report.Entity)
+	}
+	metric, ok := reportMetrics[report.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown report metric: %s", report.Metric)
+	}
+
+	query := r.db.Table(`"user".users u`)
+
+	// Group-by dimensions
+	selects := make([]string, 0, len(report.GroupBy)+1)
+	groups := make([]string, 0, len(report.GroupBy))
+	joined := make(map[string]bool)
+	for i, name := range report.GroupBy {
+		dimension, ok := reportDimensions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown report dimension: %s", name)
+		}
+		if dimension.join != "" && !joined[dimension.join] {
+			query = query.Joins(dimension.join)
+			joined[dimension.join] = true
+		}
+		alias := "dim_" + strconv.Itoa(i)
+		selects = append(selects, dimension.expr+" AS "+alias)
+		groups = append(groups, alias)
+	}
+	selects = append(selects, metric+" AS value")
+
+	// Filters
+	for _, filter := range report.Filters {
+		operator, ok := reportOperators[filter.Operator]
+		if !ok {
+			return nil, fmt.Errorf("unknown report operator: %s", filter.Operator)
+		}
+		placeholder := "?"
+		if operator == "IN" {
+			placeholder = "(?)"
+		}
+
+		if filter.Field == "role_id" {
+			query = query.Where(`EXISTS (SELECT 1 FROM "user".user_roles ur WHERE ur.ur_user_id = u.u_id AND ur.ur_role_id `+
+				operator+" "+placeholder+" AND "+effectiveUserRoleCondition+")", filter.Value)
+			continue
+		}
+
+		column, ok := reportFilterColumns[filter.Field]
+		if !ok {
+			return nil, fmt.Errorf("unknown report filter field: %s", filter.Field)
+		}
+		query = query.Where(column+" "+operator+" "+placeholder, filter.Value)
+	}
+
+	query = query.Select(strings.Join(selects, ", "))
+	for _, group := range groups {
+		query = query.Group(group).Order(group)
+	}
+
+	var results []map[string]interface{}
+	if err := query.Limit(maxReportRows).Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.ReportRow, len(results))
+	for i, result := range results {
+		row := models.ReportRow{Value: toFloat(result["value"])}
+		if len(report.GroupBy) > 0 {
+			row.Dimensions = make(map[string]interface{}, len(report.GroupBy))
+			for j, name := range report.GroupBy {
+				row.Dimensions[name] = result["dim_"+strconv.Itoa(j)]
+			}
+		}
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+// toFloat converts an aggregate scanned without a destination type to a float
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReportScheduleRepository handles report schedule database operations
+type ReportScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewReportScheduleRepository creates a new report schedule repository
+func NewReportScheduleRepository(db *gorm.DB) *ReportScheduleRepository {
+	return &ReportScheduleRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a schedule by ID
+func (r *ReportScheduleRepository) FindByID(id uint) (*models.ReportSchedule, error) {
+	var schedule models.ReportSchedule
+	result := r.db.Preload("Division").First(&schedule, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &schedule, nil
+}
+
+// List lists all schedules
+func (r *ReportScheduleRepository) List() ([]models.ReportSchedule, error) {
+	var schedules []models.ReportSchedule
+	if err := r.db.Preload("Division").Order("rs_id").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// FindDue lists active schedules whose next run is due
+func (r *ReportScheduleRepository) FindDue(now time.Time, limit int) ([]models.ReportSchedule, error) {
+	var schedules []models.ReportSchedule
+	if err := r.db.Preload("Division").
+		Where("rs_is_active = ? AND rs_next_run_at <= ?", true, now).
+		Order("rs_next_run_at").
+		Limit(limit).
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Create creates a schedule
+func (r *ReportScheduleRepository) Create(schedule *models.ReportSchedule, createdBy string) error {
+	// Set creation info
+	now := time.Now()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	schedule.CreatedBy = createdBy
+	schedule.UpdatedBy = createdBy
+
+	return r.db.Create(schedule).Error
+}
+
+// Update updates a schedule
+func (r *ReportScheduleRepository) Update(schedule *models.ReportSchedule, updatedBy string) error {
+	// Set update info
+	schedule.UpdatedAt = time.Now()
+	schedule.UpdatedBy = updatedBy
+
+	return r.db.Model(&models.ReportSchedule{}).Where("rs_id = ?", schedule.ID).Updates(map[string]interface{}{
+		"rs_name":             schedule.Name,
+		"rs_cron":             schedule.Cron,
+		"rs_timezone":         schedule.Timezone,
+		"rs_division_id":      schedule.DivisionID,
+		"rs_recipients":       schedule.Recipients,
+		"rs_anniversary_days": schedule.AnniversaryDays,
+		"rs_is_active":        schedule.IsActive,
+		"rs_next_run_at":      schedule.NextRunAt,
+		"rs_updated_at":       schedule.UpdatedAt,
+		"rs_updated_by":       schedule.UpdatedBy,
+	}).Error
+}
+
+// RecordRun stores the outcome of a run and when the schedule runs next
+func (r *ReportScheduleRepository) RecordRun(id uint, ranAt time.Time, nextRunAt *time.Time, lastError string) error {
+	return r.db.Model(&models.ReportSchedule{}).Where("rs_id = ?", id).Updates(map[string]interface{}{
+		"rs_last_run_at": ranAt,
+		"rs_next_run_at": nextRunAt,
+		"rs_last_error":  lastError,
+	}).Error
+}
+
+// Delete deletes a schedule
+func (r *ReportScheduleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ReportSchedule{}, id).Error
+}
@@ -1,13 +1,18 @@
 package repository
 
 import (
+	"slices"
 	"time"
 
 	"admin-dashboard/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// effectiveUserRoleCondition limits user_roles rows (aliased ur) to grants effective right now
+const effectiveUserRoleCondition = `(ur.ur_valid_from IS NULL OR ur.ur_valid_from <= NOW()) AND (ur.ur_valid_until IS NULL OR ur.ur_valid_until > NOW())`
+
 // RoleRepository handles role-related database operations
 type RoleRepository struct {
 	db *gorm.DB
@@ -58,13 +63,24 @@ func (r *RoleRepository) Update(role *models.Role, updatedBy string) error {
 	role.UpdatedAt = time.Now()
 	role.UpdatedBy = updatedBy
 	
-	return r.db.Model(&models.Role{}).Where("role_id = ?", role.ID).Updates(map[string]interface{}{
+	// Update role, unless it changed since it was read
+	result := r.db.Model(&models.Role{}).Where("role_id = ? AND role_version = ?", role.ID, role.Version).Updates(map[string]interface{}{
 		"role_name":      role.Name,
 		"role_level":     role.Level,
 		"role_is_active": role.IsActive,
 		"role_updated_at": role.UpdatedAt,
 		"role_updated_by": role.UpdatedBy,
-	}).Error
+		"role_version":    gorm.Expr("role_version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	role.Version++
+	return nil
 }
 
 // Delete deletes a role
@@ -82,7 +98,10 @@ func (r *RoleRepository) Delete(id uint) error {
 	}
 	
 	if count > 0 {
-		return r.db.Model(&models.Role{}).Where("role_id = ?", id).Update("role_is_active", false).Error
+		return r.db.Model(&models.Role{}).Where("role_id = ?", id).Updates(map[string]interface{}{
+			"role_is_active": false,
+			"role_version":   gorm.Expr("role_version + 1"),
+		}).Error
 	}
 	
 	// Delete the role
@@ -142,7 +161,7 @@ func (r *RoleRepository) ListAll() ([]models.Role, error) {
 	return roles, nil
 }
 
-// GetUserRoles gets all roles for a user
+// GetUserRoles gets all currently effective roles for a user
 func (r *RoleRepository) GetUserRoles(userID uint) ([]models.Role, error) {
     var roles []models.Role
     // Gunakan Raw SQL untuk menghindari masalah dengan schema
@@ -150,11 +169,236 @@ func (r *RoleRepository) GetUserRoles(userID uint) ([]models.Role, error) {
         SELECT r.* 
         FROM "user".roles r
         JOIN "user".user_roles ur ON r.role_id = ur.ur_role_id
-        WHERE ur.ur_user_id = ?
-    `
+        WHERE ur.ur_user_id = ? AND ` + effectiveUserRoleCondition
     err := r.db.Raw(query, userID).Scan(&roles).Error
     if err != nil {
         return nil, err
     }
     return roles, nil
-}
\ No newline at end of file
+}
+
+// ListByConditions lists roles matching the given conditions using offset pagination
+func (r *RoleRepository) ListByConditions(conditions []Condition, offset, limit int) ([]models.Role, int64, error) {
+	var roles []models.Role
+	var totalItems int64
+
+	// Base query
+	query, err := applyConditions(r.db.Model(&models.Role{}), conditions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		return roles, totalItems, nil
+	}
+
+	// Apply pagination
+	if err := query.Order("role_id").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return roles, totalItems, nil
+}
+
+// GetRoleUsers gets all users currently holding a role
+func (r *RoleRepository) GetRoleUsers(roleID uint) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Model(&models.User{}).
+		Joins(`JOIN "user".user_roles ur ON ur.ur_user_id = "user".users.u_id`).
+		Where("ur.ur_role_id = ?", roleID).
+		Where(effectiveUserRoleCondition).
+		Order("u_id").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AddUserRole assigns a role to a user if it is not already assigned
+func (r *RoleRepository) AddUserRole(userID, roleID uint, createdBy string) error {
+	var count int64
+	if err := r.db.Model(&models.UserRole{}).
+		Where("ur_user_id = ? AND ur_role_id = ?", userID, roleID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	// Reject conflicting roles
+	heldRoles, err := heldRoleIDs(r.db, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkRoleConflicts(r.db, userID, append(heldRoles, roleID)); err != nil {
+		return err
+	}
+
+	userRole := models.UserRole{
+		UserID:    userID,
+		RoleID:    roleID,
+		CreatedAt: time.Now(),
+		CreatedBy: createdBy,
+	}
+	return r.db.Create(&userRole).Error
+}
+
+// RemoveUserRole removes a role from a user
+func (r *RoleRepository) RemoveUserRole(userID, roleID uint) error {
+	return r.db.Where("ur_user_id = ? AND ur_role_id = ?", userID, roleID).Delete(&models.UserRole{}).Error
+}
+
+// SetRoleUsers replaces all users assigned to a role, keeping the validity windows of the
+// remaining members
+func (r *RoleRepository) SetRoleUsers(roleID uint, userIDs []uint, createdBy string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Delete the assignments of users who are no longer members
+		removed := tx.Where("ur_role_id = ?", roleID)
+		if len(userIDs) > 0 {
+			removed = removed.Where("ur_user_id NOT IN ?", userIDs)
+		}
+		if err := removed.Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		var assigned []uint
+		if err := tx.Model(&models.UserRole{}).Where("ur_role_id = ?", roleID).Pluck("ur_user_id", &assigned).Error; err != nil {
+			return err
+		}
+
+		// Assign new members
+		now := time.Now()
+		for _, userID := range userIDs {
+			if slices.Contains(assigned, userID) {
+				continue
+			}
+			assigned = append(assigned, userID)
+
+			// Reject conflicting roles
+			heldRoles, err := heldRoleIDs(tx, userID)
+			if err != nil {
+				return err
+			}
+			if err := checkRoleConflicts(tx, userID, append(heldRoles, roleID)); err != nil {
+				return err
+			}
+
+			userRole := models.UserRole{
+				UserID:    userID,
+				RoleID:    roleID,
+				CreatedAt: now,
+				CreatedBy: createdBy,
+			}
+			if err := tx.Create(&userRole).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListUserRoleGrants lists all role assignments of a user, including future and not yet swept ones
+func (r *RoleRepository) ListUserRoleGrants(userID uint) ([]models.UserRole, error) {
+	var grants []models.UserRole
+	err := r.db.Preload("Role").
+		Where("ur_user_id = ?", userID).
+		Order("ur_created_at").
+		Find(&grants).Error
+	if err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// GrantUserRole assigns a role to a user, replacing the validity window of an existing assignment
+func (r *RoleRepository) GrantUserRole(grant *models.UserRole) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Replace any existing assignment of the same role
+		if err := tx.Where("ur_user_id = ? AND ur_role_id = ?", grant.UserID, grant.RoleID).
+			Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		// Reject conflicting roles
+		heldRoles, err := heldRoleIDs(tx, grant.UserID)
+		if err != nil {
+			return err
+		}
+		if err := checkRoleConflicts(tx, grant.UserID, append(heldRoles, grant.RoleID)); err != nil {
+			return err
+		}
+
+		grant.CreatedAt = time.Now()
+		return tx.Create(grant).Error
+	})
+}
+
+// FindUsersWithExpiredRoles lists the users holding assignments whose validity ended
+func (r *RoleRepository) FindUsersWithExpiredRoles(now time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.UserRole{}).
+		Where("ur_valid_until IS NOT NULL AND ur_valid_until <= ?", now).
+		Distinct().
+		Order("ur_user_id").
+		Pluck("ur_user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// ExpireUserRoles removes a user's assignments whose validity ended and records them as expired.
+// Assignments another instance is expiring are skipped, so each is recorded only once.
+func (r *RoleRepository) ExpireUserRoles(userID uint, now time.Time) ([]models.RoleGrantExpiration, error) {
+	var expirations []models.RoleGrantExpiration
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var grants []models.UserRole
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("ur_user_id = ? AND ur_valid_until IS NOT NULL AND ur_valid_until <= ?", userID, now).
+			Find(&grants).Error; err != nil {
+			return err
+		}
+
+		for _, grant := range grants {
+			deleted := tx.Delete(&models.UserRole{}, grant.ID)
+			if deleted.Error != nil {
+				return deleted.Error
+			}
+			if deleted.RowsAffected == 0 {
+				continue
+			}
+
+			expiration := models.RoleGrantExpiration{
+				UserID:     grant.UserID,
+				RoleID:     grant.RoleID,
+				ValidFrom:  grant.ValidFrom,
+				ValidUntil: *grant.ValidUntil,
+				GrantedAt:  grant.CreatedAt,
+				GrantedBy:  grant.CreatedBy,
+				ExpiredAt:  now,
+			}
+			if err := tx.Create(&expiration).Error; err != nil {
+				return err
+			}
+
+			expirations = append(expirations, expiration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expirations, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ScheduledChangeRepository handles scheduled user change database operations
+type ScheduledChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledChangeRepository creates a new scheduled change repository
+func NewScheduledChangeRepository(db *gorm.DB) *ScheduledChangeRepository {
+	return &ScheduledChangeRepository{
+		db: db,
+	}
+}
+
+// FindByID finds a scheduled change by ID
+func (r *ScheduledChangeRepository) FindByID(id uint) (*models.ScheduledChange, error) {
+	var change models.ScheduledChange
+	result := r.db.First(&change, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &change, nil
+}
+
+// List lists scheduled changes, optionally filtered by user and status, in effective order
+func (r *ScheduledChangeRepository) List(userID uint, status string) ([]models.ScheduledChange, error) {
+	var changes []models.ScheduledChange
+
+	// Base query
+	query := r.db.Order("sc_effective_at, sc_id")
+
+	// Apply filters if provided
+	if userID != 0 {
+		query = query.Where("sc_target_user_id = ?", userID)
+	}
+	if status != "" {
+		query = query.Where("sc_status = ?", status)
+	}
+
+	if err := query.Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// FindDue lists pending changes whose effective time has come, oldest first
+func (r *ScheduledChangeRepository) FindDue(now time.Time, limit int) ([]models.ScheduledChange, error) {
+	var changes []models.ScheduledChange
+	if err := r.db.Where("sc_status = ? AND sc_effective_at <= ?", models.ScheduledChangePending, now).
+		Order("sc_effective_at, sc_id").
+		Limit(limit).
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Create creates a scheduled change
+func (r *ScheduledChangeRepository) Create(change *models.ScheduledChange) error {
+	// Set creation info
+	now := time.Now()
+	change.CreatedAt = now
+	change.UpdatedAt = now
+	change.UpdatedBy = change.RequestedBy
+
+	return r.db.Create(change).Error
+}
+
+// Update updates the payload and effective time of a pending change. It returns
+// gorm.ErrRecordNotFound when the change is no longer pending.
+func (r *ScheduledChangeRepository) Update(change *models.ScheduledChange, updatedBy string) error {
+	// Set update info
+	change.UpdatedAt = time.Now()
+	change.UpdatedBy = updatedBy
+
+	result := r.db.Model(&models.ScheduledChange{}).
+		Where("sc_id = ? AND sc_status = ?", change.ID, models.ScheduledChangePending).
+		Updates(map[string]interface{}{
+			"sc_payload":      change.Payload,
+			"sc_effective_at": change.EffectiveAt,
+			"sc_updated_at":   change.UpdatedAt,
+			"sc_updated_by":   change.UpdatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RecordOutcome records how a pending change was processed. It returns gorm.ErrRecordNotFound
+// when the change is no longer pending.
+func (r *ScheduledChangeRepository) RecordOutcome(id uint, status string, changeRequestID *uint, lastError string) error {
+	result := r.db.Model(&models.ScheduledChange{}).
+		Where("sc_id = ? AND sc_status = ?", id, models.ScheduledChangePending).
+		Updates(map[string]interface{}{
+			"sc_status":            status,
+			"sc_change_request_id": changeRequestID,
+			"sc_last_error":        lastError,
+			"sc_processed_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Cancel cancels a pending change. It returns gorm.ErrRecordNotFound when the change is no
+// longer pending.
+func (r *ScheduledChangeRepository) Cancel(id uint, cancelledBy string) error {
+	result := r.db.Model(&models.ScheduledChange{}).
+		Where("sc_id = ? AND sc_status = ?", id, models.ScheduledChangePending).
+		Updates(map[string]interface{}{
+			"sc_status":       models.ScheduledChangeCancelled,
+			"sc_cancelled_at": time.Now(),
+			"sc_cancelled_by": cancelledBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionRepository handles session and login history database operations
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+// Create creates a new session
+func (r *SessionRepository) Create(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID finds a session by ID
+func (r *SessionRepository) FindByID(id uuid.UUID) (*models.UserSession, error) {
+	var session models.UserSession
+	result := r.db.Where("us_id = ?", id).First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// IsActive reports whether a session exists, is not revoked and has not expired
+func (r *SessionRepository) IsActive(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserSession{}).
+		Where("us_id = ? AND us_revoked_at IS NULL AND us_expires_at > ?", id, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListActiveByUser lists all active sessions of a user
+func (r *SessionRepository) ListActiveByUser(userID uint) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := r.db.Where("us_user_id = ? AND us_revoked_at IS NULL AND us_expires_at > ?", userID, time.Now()).
+		Order("us_created_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Revoke revokes a single session
+func (r *SessionRepository) Revoke(id uuid.UUID, revokedBy string) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("us_id = ? AND us_revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"us_revoked_at": time.Now(),
+			"us_revoked_by": revokedBy,
+		}).Error
+}
+
+// RevokeAllByUser revokes every active session of a user
+func (r *SessionRepository) RevokeAllByUser(userID uint, revokedBy string) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("us_user_id = ? AND us_revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"us_revoked_at": time.Now(),
+			"us_revoked_by": revokedBy,
+		}).Error
+}
+
+// RecordLogin records a successful or failed login attempt
+func (r *SessionRepository) RecordLogin(event *models.LoginEvent) error {
+	event.CreatedAt = time.Now()
+	return r.db.Create(event).Error
+}
+
+// ListLogins lists the login history of a user with pagination
+func (r *SessionRepository) ListLogins(userID uint, page, limit int) (*models.PaginatedResponse, error) {
+	var events []models.LoginEvent
+	var totalItems int64
+
+	// Base query
+	query := r.db.Model(&models.LoginEvent{}).Where("le_user_id = ?", userID)
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (page - 1) * limit
+	if err := query.Order("le_created_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + int64(limit) - 1) / int64(limit)
+
+	// Create response
+	response := &models.PaginatedResponse{
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: int64(page),
+		PageSize:    int64(limit),
+		Items:       events,
+	}
+
+	return response, nil
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RoleConflictError reports roles that would be held together in violation of a segregation-of-duties rule
+type RoleConflictError struct {
+	Rule  string
+	Roles []string
+}
+
+// Error implements the error interface
+func (e *RoleConflictError) Error() string {
+	return fmt.Sprintf("roles %s conflict under segregation-of-duties rule %q", strings.Join(e.Roles, ", "), e.Rule)
+}
+
+// SoDRepository handles segregation-of-duties rule and exception database operations
+type SoDRepository struct {
+	db *gorm.DB
+}
+
+// NewSoDRepository creates a new segregation-of-duties repository
+func NewSoDRepository(db *gorm.DB) *SoDRepository {
+	return &SoDRepository{
+		db: db,
+	}
+}
+
+// FindRuleByID finds a rule by ID with its roles
+func (r *SoDRepository) FindRuleByID(id uint) (*models.SoDRule, error) {
+	var rule models.SoDRule
+	result := r.db.Preload("RuleRoles.Role").First(&rule, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rule, nil
+}
+
+// ListRules lists all rules with their roles
+func (r *SoDRepository) ListRules() ([]models.SoDRule, error) {
+	var rules []models.SoDRule
+	if err := r.db.Preload("RuleRoles.Role").Order("sod_name").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule creates a rule with its roles
+func (r *SoDRepository) CreateRule(rule *models.SoDRule, roleIDs []uint, createdBy string) error {
+	// Set creation info
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	rule.CreatedBy = createdBy
+	rule.UpdatedBy = createdBy
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("RuleRoles").Create(rule).Error; err != nil {
+			return err
+		}
+		return setRuleRoles(tx, rule.ID, roleIDs)
+	})
+}
+
+// UpdateRule updates a rule and replaces its roles
+func (r *SoDRepository) UpdateRule(rule *models.SoDRule, roleIDs []uint, updatedBy string) error {
+	// Set update info
+	rule.UpdatedAt = time.Now()
+	rule.UpdatedBy = updatedBy
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SoDRule{}).Where("sod_id = ?", rule.ID).Updates(map[string]interface{}{
+			"sod_name":        rule.Name,
+			"sod_description": rule.Description,
+			"sod_is_active":   rule.IsActive,
+			"sod_updated_at":  rule.UpdatedAt,
+			"sod_updated_by":  rule.UpdatedBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("sodr_rule_id = ?", rule.ID).Delete(&models.SoDRuleRole{}).Error; err != nil {
+			return err
+		}
+		return setRuleRoles(tx, rule.ID, roleIDs)
+	})
+}
+
+// DeleteRule deletes a rule with its roles and exceptions
+func (r *SoDRepository) DeleteRule(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sode_rule_id = ?", id).Delete(&models.SoDException{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sodr_rule_id = ?", id).Delete(&models.SoDRuleRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.SoDRule{}, id).Error
+	})
+}
+
+// ListExceptions lists exceptions, optionally filtered by user and by whether they are still valid
+func (r *SoDRepository) ListExceptions(userID uint, activeOnly bool) ([]models.SoDException, error) {
+	var exceptions []models.SoDException
+
+	// Base query
+	query := r.db.Preload("Rule").Preload("User")
+
+	// Apply filters if provided
+	if userID != 0 {
+		query = query.Where("sode_user_id = ?", userID)
+	}
+	if activeOnly {
+		query = query.Where("sode_expires_at > ?", time.Now())
+	}
+
+	if err := query.Order("sode_expires_at").Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+// CreateException creates an exception
+func (r *SoDRepository) CreateException(exception *models.SoDException, createdBy string) error {
+	exception.CreatedAt = time.Now()
+	exception.CreatedBy = createdBy
+	return r.db.Create(exception).Error
+}
+
+// DeleteException deletes an exception
+func (r *SoDRepository) DeleteException(id uint) error {
+	result := r.db.Delete(&models.SoDException{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ScanViolations finds active users whose effective roles break an active rule without a valid exception
+func (r *SoDRepository) ScanViolations() ([]models.SoDViolation, error) {
+	var rows []struct {
+		RuleID     uint
+		RuleName   string
+		UserID     uint
+		EmployeeID string
+		Name       string
+		Email      string
+		Roles      string
+	}
+
+	now := time.Now()
+	err := r.db.Raw(`
+		SELECT s.sod_id AS rule_id, s.sod_name AS rule_name,
+		       u.u_id AS user_id, u.u_employee_id AS employee_id, u.u_name AS name, u.u_email AS email,
+		       string_agg(DISTINCT r.role_name, ',' ORDER BY r.role_name) AS roles
+		FROM "user".sod_rules s
+		JOIN "user".sod_rule_roles sr ON sr.sodr_rule_id = s.sod_id
+		JOIN "user".user_roles ur ON ur.ur_role_id = sr.sodr_role_id
+		JOIN "user".users u ON u.u_id = ur.ur_user_id
+		JOIN "user".roles r ON r.role_id = ur.ur_role_id
+		WHERE s.sod_is_active = true AND u.u_is_active = true AND `+effectiveUserRoleCondition+`
+		  AND NOT EXISTS (
+		      SELECT 1 FROM "user".sod_exceptions e
+		      WHERE e.sode_rule_id = s.sod_id AND e.sode_user_id = u.u_id AND e.sode_expires_at > ?
+		  )
+		GROUP BY s.sod_id, s.sod_name, u.u_id, u.u_employee_id, u.u_name, u.u_email
+		HAVING COUNT(DISTINCT ur.ur_role_id) >= 2
+		ORDER BY s.sod_name, u.u_name
+	`, now).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]models.SoDViolation, len(rows))
+	for i, row := range rows {
+		violations[i] = models.SoDViolation{
+			RuleID:     row.RuleID,
+			RuleName:   row.RuleName,
+			UserID:     row.UserID,
+			EmployeeID: row.EmployeeID,
+			Name:       row.Name,
+			Email:      row.Email,
+			Roles:      strings.Split(row.Roles, ","),
+		}
+	}
+	return violations, nil
+}
+
+// setRuleRoles assigns the roles of a rule, skipping duplicates
+func setRuleRoles(tx *gorm.DB, ruleID uint, roleIDs []uint) error {
+	seen := make(map[uint]bool, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if seen[roleID] {
+			continue
+		}
+		seen[roleID] = true
+
+		if err := tx.Create(&models.SoDRuleRole{RuleID: ruleID, RoleID: roleID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoleConflicts returns a RoleConflictError when the user holding roleIDs together would break
+// an active rule for which the user has no valid exception
+func checkRoleConflicts(tx *gorm.DB, userID uint, roleIDs []uint) error {
+	if len(roleIDs) < 2 {
+		return nil
+	}
+
+	var rows []struct {
+		RuleName string
+		RoleName string
+	}
+	err := tx.Raw(`
+		SELECT s.sod_name AS rule_name, r.role_name AS role_name
+		FROM "user".sod_rules s
+		JOIN "user".sod_rule_roles sr ON sr.sodr_rule_id = s.sod_id
+		JOIN "user".roles r ON r.role_id = sr.sodr_role_id
+		WHERE s.sod_is_active = true AND sr.sodr_role_id IN ?
+		  AND NOT EXISTS (
+		      SELECT 1 FROM "user".sod_exceptions e
+		      WHERE e.sode_rule_id = s.sod_id AND e.sode_user_id = ? AND e.sode_expires_at > ?
+		  )
+		ORDER BY s.sod_name, r.role_name
+	`, roleIDs, userID, time.Now()).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	// Report the first rule matched by two or more roles
+	for i := 0; i < len(rows); {
+		j := i
+		var roles []string
+		for ; j < len(rows) && rows[j].RuleName == rows[i].RuleName; j++ {
+			roles = append(roles, rows[j].RoleName)
+		}
+		if len(roles) >= 2 {
+			return &RoleConflictError{Rule: rows[i].RuleName, Roles: roles}
+		}
+		i = j
+	}
+	return nil
+}
+
+// heldRoleIDs lists the roles a user holds now or will hold later, ignoring expired grants
+func heldRoleIDs(tx *gorm.DB, userID uint) ([]uint, error) {
+	var roleIDs []uint
+	err := tx.Model(&models.UserRole{}).
+		Where("ur_user_id = ? AND (ur_valid_until IS NULL OR ur_valid_until > ?)", userID, time.Now()).
+		Distinct().
+		Pluck("ur_role_id", &roleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return roleIDs, nil
+}
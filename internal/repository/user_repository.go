@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"admin-dashboard/internal/models"
@@ -29,11 +30,13 @@ func (r *UserRepository) FindByID(id uint) (*models.User, error) {
 	result := r.db.Preload("Division").
 		Preload("Position").
 		Preload("Manager").
-		Preload("Roles").
 		First(&user, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	if err := r.attachEffectiveRoles(&user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
@@ -43,12 +46,14 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	result := r.db.Preload("Division").
 		Preload("Position").
 		Preload("Manager").
-		Preload("Roles").
 		Where("u_email = ?", email).
 		First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	if err := r.attachEffectiveRoles(&user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
@@ -58,12 +63,14 @@ func (r *UserRepository) FindByEmployeeID(employeeID string) (*models.User, erro
 	result := r.db.Preload("Division").
 		Preload("Position").
 		Preload("Manager").
-		Preload("Roles").
 		Where("u_employee_id = ?", employeeID).
 		First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	if err := r.attachEffectiveRoles(&user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
@@ -73,12 +80,14 @@ func (r *UserRepository) FindByUID(uid uuid.UUID) (*models.User, error) {
 	result := r.db.Preload("Division").
 		Preload("Position").
 		Preload("Manager").
-		Preload("Roles").
 		Where("u_uid = ?", uid).
 		First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	if err := r.attachEffectiveRoles(&user); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
@@ -98,94 +107,164 @@ func (r *UserRepository) Create(user *models.User, roleIDs []uint, createdBy str
 	user.CreatedBy = createdBy
 	user.UpdatedBy = createdBy
 
-	// Start a transaction
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
-
-	// Create the user
-	if err := tx.Create(user).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// Assign roles to the user
-	for _, roleID := range roleIDs {
-		userRole := models.UserRole{
-			UserID:    user.ID,
-			RoleID:    roleID,
-			CreatedAt: now,
-			CreatedBy: createdBy,
+	// Run in a transaction (nested as a savepoint when the repository is bound to one)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Create the user
+		if err := tx.Create(user).Error; err != nil {
+			return err
 		}
-		if err := tx.Create(&userRole).Error; err != nil {
-			tx.Rollback()
+
+		// Reject conflicting roles
+		if err := checkRoleConflicts(tx, user.ID, roleIDs); err != nil {
 			return err
 		}
-	}
 
-	// Commit the transaction
-	return tx.Commit().Error
+		// Assign roles to the user
+		for _, roleID := range roleIDs {
+			userRole := models.UserRole{
+				UserID:    user.ID,
+				RoleID:    roleID,
+				CreatedAt: now,
+				CreatedBy: createdBy,
+			}
+			if err := tx.Create(&userRole).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
-// Update updates a user
+// Update updates a user. A user who is deactivated is recorded as deactivated at
+// user.DeactivatedAt when set, otherwise now.
 func (r *UserRepository) Update(user *models.User, roleIDs []uint, updatedBy string) error {
 	// Set update info
 	user.UpdatedAt = time.Now()
 	user.UpdatedBy = updatedBy
 
-	// Start a transaction
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
-
-	// Update user
-	if err := tx.Model(&models.User{}).Where("u_id = ?", user.ID).Updates(map[string]interface{}{
-		"u_name":          user.Name,
-		"u_email":         user.Email,
-		"u_phone":         user.Phone,
-		"u_address":       user.Address,
-		"u_birthdate":     user.Birthdate,
-		"u_join_date":     user.JoinDate,
-		"u_profile_image": user.ProfileImage,
-		"u_division_id":   user.DivisionID,
-		"u_position_id":   user.PositionID,
-		"u_is_manager":    user.IsManager,
-		"u_manager_id":    user.ManagerID,
-		"u_is_active":     user.IsActive,
-		"u_updated_at":    user.UpdatedAt,
-		"u_updated_by":    user.UpdatedBy,
-	}).Error; err != nil {
-		tx.Rollback()
-		return err
+	deactivatedAt := user.UpdatedAt
+	if !user.IsActive && user.DeactivatedAt != nil {
+		deactivatedAt = *user.DeactivatedAt
 	}
 
-	// If roleIDs are provided, update user roles
-	if roleIDs != nil {
-		// Delete existing user roles
-		if err := tx.Where("ur_user_id = ?", user.ID).Delete(&models.UserRole{}).Error; err != nil {
-			tx.Rollback()
-			return err
+	// Run in a transaction (nested as a savepoint when the repository is bound to one)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Update user, unless it changed since it was read
+		result := tx.Model(&models.User{}).Where("u_id = ? AND u_version = ?", user.ID, user.Version).Updates(map[string]interface{}{
+			"u_employee_id":    user.EmployeeID,
+			"u_name":           user.Name,
+			"u_email":          user.Email,
+			"u_phone":          user.Phone,
+			"u_address":        user.Address,
+			"u_birthdate":      user.Birthdate,
+			"u_join_date":      user.JoinDate,
+			"u_profile_image":  user.ProfileImage,
+			"u_division_id":    user.DivisionID,
+			"u_position_id":    user.PositionID,
+			"u_is_manager":     user.IsManager,
+			"u_manager_id":     user.ManagerID,
+			"u_custom_fields":  user.CustomFields,
+			"u_is_active":      user.IsActive,
+			// Record when the user was deactivated (SET expressions see the row before the update)
+			"u_deactivated_at": gorm.Expr("CASE WHEN ? THEN NULL WHEN u_is_active THEN ? ELSE u_deactivated_at END", user.IsActive, deactivatedAt),
+			"u_updated_at":     user.UpdatedAt,
+			"u_updated_by":     user.UpdatedBy,
+			"u_version":        gorm.Expr("u_version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
 		}
 
-		// Assign new roles
-		for _, roleID := range roleIDs {
-			userRole := models.UserRole{
-				UserID:    user.ID,
-				RoleID:    roleID,
-				CreatedAt: time.Now(),
-				CreatedBy: updatedBy,
+		// If roleIDs are provided, update user roles
+		if roleIDs != nil {
+			// Reject conflicting roles
+			if err := checkRoleConflicts(tx, user.ID, roleIDs); err != nil {
+				return err
 			}
-			if err := tx.Create(&userRole).Error; err != nil {
-				tx.Rollback()
+
+			// Delete the assignments of roles that are no longer listed, keeping the
+			// validity windows of the remaining ones
+			removed := tx.Where("ur_user_id = ?", user.ID)
+			if len(roleIDs) > 0 {
+				removed = removed.Where("ur_role_id NOT IN ?", roleIDs)
+			}
+			if err := removed.Delete(&models.UserRole{}).Error; err != nil {
 				return err
 			}
+
+			var assigned []uint
+			if err := tx.Model(&models.UserRole{}).Where("ur_user_id = ?", user.ID).Pluck("ur_role_id", &assigned).Error; err != nil {
+				return err
+			}
+
+			// Assign new roles
+			for _, roleID := range roleIDs {
+				if slices.Contains(assigned, roleID) {
+					continue
+				}
+				assigned = append(assigned, roleID)
+				userRole := models.UserRole{
+					UserID:    user.ID,
+					RoleID:    roleID,
+					CreatedAt: time.Now(),
+					CreatedBy: updatedBy,
+				}
+				if err := tx.Create(&userRole).Error; err != nil {
+					return err
+				}
+			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Commit the transaction
-	return tx.Commit().Error
+	user.Version++
+	return nil
+}
+
+// UpdateAvatar sets a user's uploaded avatar and the profile image URL that serves it
+func (r *UserRepository) UpdateAvatar(userID uint, avatarKey, avatarType, profileImage string, updatedBy string) error {
+	return r.db.Model(&models.User{}).Where("u_id = ?", userID).Updates(map[string]interface{}{
+		"u_avatar_key":    avatarKey,
+		"u_avatar_type":   avatarType,
+		"u_profile_image": profileImage,
+		"u_updated_at":    time.Now(),
+		"u_updated_by":    updatedBy,
+		"u_version":       gorm.Expr("u_version + 1"),
+	}).Error
+}
+
+// ReassignReports moves the direct reports of a manager to another manager, or leaves them
+// without one when newManagerID is nil, and returns the IDs of the moved users
+func (r *UserRepository) ReassignReports(managerID uint, newManagerID *uint, updatedBy string) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.User{}).Where("u_manager_id = ?", managerID).Order("u_id").Pluck("u_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	err := r.db.Model(&models.User{}).Where("u_id IN ?", ids).Updates(map[string]interface{}{
+		"u_manager_id": newManagerID,
+		"u_updated_at": time.Now(),
+		"u_updated_by": updatedBy,
+		"u_version":    gorm.Expr("u_version + 1"),
+	}).Error
+	return ids, err
+}
+
+// IncrementVersion marks a user as changed by something stored outside the users table,
+// such as its role assignments
+func (r *UserRepository) IncrementVersion(userID uint) error {
+	return r.db.Model(&models.User{}).Where("u_id = ?", userID).Update("u_version", gorm.Expr("u_version + 1")).Error
 }
 
 // UpdatePassword updates a user's password
@@ -204,6 +283,11 @@ func (r *UserRepository) UpdatePassword(userID uint, password string, updatedBy
 	}).Error
 }
 
+// UpdateLastLogin records the time of a user's last successful login
+func (r *UserRepository) UpdateLastLogin(userID uint, loginAt time.Time) error {
+	return r.db.Model(&models.User{}).Where("u_id = ?", userID).Update("u_last_login_at", loginAt).Error
+}
+
 // Delete deletes a user
 func (r *UserRepository) Delete(id uint) error {
 	// Check if the user exists
@@ -212,30 +296,20 @@ func (r *UserRepository) Delete(id uint) error {
 		return err
 	}
 
-	// Start a transaction
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
-
-	// Delete user roles
-	if err := tx.Where("ur_user_id = ?", id).Delete(&models.UserRole{}).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	// Delete the user
-	if err := tx.Delete(&models.User{}, id).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+	// Run in a transaction (nested as a savepoint when the repository is bound to one)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Delete user roles
+		if err := tx.Where("ur_user_id = ?", id).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
 
-	// Commit the transaction
-	return tx.Commit().Error
+		// Delete the user
+		return tx.Delete(&models.User{}, id).Error
+	})
 }
 
-// List lists all users with pagination
-func (r *UserRepository) List(page, limit int, search string) (*models.PaginatedResponse, error) {
+// List lists all users with pagination, optionally narrowed by conditions
+func (r *UserRepository) List(page, limit int, search string, conditions []Condition) (*models.PaginatedResponse, error) {
 	var users []models.User
 	var totalItems int64
 	
@@ -243,14 +317,17 @@ func (r *UserRepository) List(page, limit int, search string) (*models.Paginated
 	query := r.db.Model(&models.User{}).
 		Preload("Division").
 		Preload("Position").
-		Preload("Manager").
-		Preload("Roles")
+		Preload("Manager")
 	
 	// Apply search if provided
 	if search != "" {
 		searchTerm := "%" + search + "%"
 		query = query.Where("u_name ILIKE ? OR u_email ILIKE ? OR u_employee_id ILIKE ?", searchTerm, searchTerm, searchTerm)
 	}
+	query, err := applyConditions(query, conditions)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Get total count
 	if err := query.Count(&totalItems).Error; err != nil {
@@ -262,6 +339,9 @@ func (r *UserRepository) List(page, limit int, search string) (*models.Paginated
 	if err := query.Offset(offset).Limit(limit).Find(&users).Error; err != nil {
 		return nil, err
 	}
+	if err := r.attachEffectiveRoles(userPointers(users)...); err != nil {
+		return nil, err
+	}
 	
 	// Calculate total pages
 	totalPages := (totalItems + int64(limit) - 1) / int64(limit)
@@ -278,6 +358,108 @@ func (r *UserRepository) List(page, limit int, search string) (*models.Paginated
 	return response, nil
 }
 
+// ListByConditions lists users matching the given conditions using offset pagination
+func (r *UserRepository) ListByConditions(conditions []Condition, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var totalItems int64
+
+	// Base query
+	query, err := applyConditions(r.db.Model(&models.User{}), conditions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		return users, totalItems, nil
+	}
+
+	// Apply pagination
+	err = query.Preload("Manager").
+		Order("u_id").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.attachEffectiveRoles(userPointers(users)...); err != nil {
+		return nil, 0, err
+	}
+
+	return users, totalItems, nil
+}
+
+// FindIDs lists the IDs of users matching the search term and conditions, in ID order
+func (r *UserRepository) FindIDs(search string, conditions []Condition) ([]uint, error) {
+	query := r.db.Model(&models.User{})
+
+	// Apply search if provided
+	if search != "" {
+		searchTerm := "%" + search + "%"
+		query = query.Where("u_name ILIKE ? OR u_email ILIKE ? OR u_employee_id ILIKE ?", searchTerm, searchTerm, searchTerm)
+	}
+	query, err := applyConditions(query, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	if err := query.Order("u_id").Pluck("u_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// attachEffectiveRoles replaces each user's Roles with the roles currently effective for them
+func (r *UserRepository) attachEffectiveRoles(users ...*models.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+		user.Roles = nil
+	}
+
+	var rows []struct {
+		UserID      uint `gorm:"column:ur_user_id"`
+		models.Role `gorm:"embedded"`
+	}
+	query := `
+		SELECT ur.ur_user_id, r.*
+		FROM "user".roles r
+		JOIN "user".user_roles ur ON r.role_id = ur.ur_role_id
+		WHERE ur.ur_user_id IN ? AND ` + effectiveUserRoleCondition
+	if err := r.db.Raw(query, userIDs).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	byUser := make(map[uint][]*models.Role)
+	for i := range rows {
+		byUser[rows[i].UserID] = append(byUser[rows[i].UserID], &rows[i].Role)
+	}
+	for _, user := range users {
+		user.Roles = byUser[user.ID]
+	}
+
+	return nil
+}
+
+// userPointers returns pointers to each user in the slice
+func userPointers(users []models.User) []*models.User {
+	pointers := make([]*models.User, len(users))
+	for i := range users {
+		pointers[i] = &users[i]
+	}
+	return pointers
+}
+
 // Authenticate authenticates a user with email and password
 func (r *UserRepository) Authenticate(email, password string) (*models.User, error) {
 	// Find user by email
@@ -301,4 +483,17 @@ func (r *UserRepository) Authenticate(email, password string) (*models.User, err
 	}
 	
 	return user, nil
-}
\ No newline at end of file
+}
+
+// FindManagerEmails lists the email addresses of active managers, of one division when given
+func (r *UserRepository) FindManagerEmails(divisionID *uint) ([]string, error) {
+	var emails []string
+	query := r.db.Model(&models.User{}).Where("u_is_manager = ? AND u_is_active = ?", true, true)
+	if divisionID != nil {
+		query = query.Where("u_division_id = ?", *divisionID)
+	}
+	if err := query.Order("u_email").Pluck("u_email", &emails).Error; err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"time"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository handles webhook subscription and delivery database operations
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// FindSubscriptionByID finds a subscription by ID
+func (r *WebhookRepository) FindSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	result := r.db.First(&subscription, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &subscription, nil
+}
+
+// ListSubscriptions lists all subscriptions
+func (r *WebhookRepository) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	if err := r.db.Order("whs_id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ListActiveSubscriptions lists subscriptions that receive events
+func (r *WebhookRepository) ListActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	if err := r.db.Where("whs_is_active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// CreateSubscription creates a subscription
+func (r *WebhookRepository) CreateSubscription(subscription *models.WebhookSubscription, createdBy string) error {
+	// Set creation info
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	subscription.CreatedBy = createdBy
+	subscription.UpdatedBy = createdBy
+
+	return r.db.Create(subscription).Error
+}
+
+// UpdateSubscription updates a subscription
+func (r *WebhookRepository) UpdateSubscription(subscription *models.WebhookSubscription, updatedBy string) error {
+	// Set update info
+	subscription.UpdatedAt = time.Now()
+	subscription.UpdatedBy = updatedBy
+
+	return r.db.Model(&models.WebhookSubscription{}).Where("whs_id = ?", subscription.ID).Updates(map[string]interface{}{
+		"whs_name":       subscription.Name,
+		"whs_url":        subscription.URL,
+		"whs_secret":     subscription.Secret,
+		"whs_events":     subscription.Events,
+		"whs_is_active":  subscription.IsActive,
+		"whs_updated_at": subscription.UpdatedAt,
+		"whs_updated_by": subscription.UpdatedBy,
+	}).Error
+}
+
+// DeleteSubscription deletes a subscription with its deliveries and delivery log
+func (r *WebhookRepository) DeleteSubscription(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wha_delivery_id IN (?)",
+			tx.Model(&models.WebhookDelivery{}).Select("whd_id").Where("whd_subscription_id = ?", id),
+		).Delete(&models.WebhookDeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("whd_subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookSubscription{}, id).Error
+	})
+}
+
+// CreateDeliveries queues deliveries, ignoring any already queued for the same subscription and event
+func (r *WebhookRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ClaimDueDeliveries locks up to limit deliveries that are due and pushes their next attempt out by
+// lease, so other workers leave them alone while they are being sent
+func (r *WebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("whd_status IN ? AND whd_next_attempt_at <= ?",
+				[]string{models.WebhookDeliveryPending, models.WebhookDeliveryRetrying}, now).
+			Order("whd_next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(deliveries))
+		subscriptionIDs := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+			subscriptionIDs[i] = delivery.SubscriptionID
+		}
+
+		if err := tx.Model(&models.WebhookDelivery{}).Where("whd_id IN ?", ids).
+			Update("whd_next_attempt_at", now.Add(lease)).Error; err != nil {
+			return err
+		}
+
+		// Attach subscriptions
+		var subscriptions []models.WebhookSubscription
+		if err := tx.Where("whs_id IN ?", subscriptionIDs).Find(&subscriptions).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.WebhookSubscription, len(subscriptions))
+		for i := range subscriptions {
+			byID[subscriptions[i].ID] = &subscriptions[i]
+		}
+		for i := range deliveries {
+			deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt logs a delivery attempt and saves the delivery's resulting state
+func (r *WebhookRepository) RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt.DeliveryID = delivery.ID
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).Where("whd_id = ?", delivery.ID).Updates(map[string]interface{}{
+			"whd_status":           delivery.Status,
+			"whd_attempts":         delivery.Attempts,
+			"whd_next_attempt_at":  delivery.NextAttemptAt,
+			"whd_last_status_code": delivery.LastStatusCode,
+			"whd_last_error":       delivery.LastError,
+			"whd_delivered_at":     delivery.DeliveredAt,
+		}).Error
+	})
+}
+
+// ListDeliveries lists a subscription's deliveries with pagination
+func (r *WebhookRepository) ListDeliveries(subscriptionID uint, status string, page, limit int) (*models.PaginatedResponse, error) {
+	var deliveries []models.WebhookDelivery
+	var totalItems int64
+
+	// Base query
+	query := r.db.Model(&models.WebhookDelivery{}).Where("whd_subscription_id = ?", subscriptionID)
+
+	// Apply status filter if provided
+	if status != "" {
+		query = query.Where("whd_status = ?", status)
+	}
+
+	// Get total count
+	if err := query.Count(&totalItems).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (page - 1) * limit
+	if err := query.Order("whd_id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + int64(limit) - 1) / int64(limit)
+
+	// Create response
+	response := &models.PaginatedResponse{
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: int64(page),
+		PageSize:    int64(limit),
+		Items:       deliveries,
+	}
+
+	return response, nil
+}
+
+// FindDeliveryByID finds a delivery by ID with its attempt log
+func (r *WebhookRepository) FindDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	result := r.db.Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+		return db.Order("wha_attempted_at")
+	}).First(&delivery, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+// Redeliver queues a delivery to be sent again right away with a fresh retry budget
+func (r *WebhookRepository) Redeliver(id uint) error {
+	return r.db.Model(&models.WebhookDelivery{}).Where("whd_id = ?", id).Updates(map[string]interface{}{
+		"whd_status":          models.WebhookDeliveryPending,
+		"whd_attempts":        0,
+		"whd_next_attempt_at": time.Now(),
+		"whd_delivered_at":    nil,
+	}).Error
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// AccessReviewService handles access review campaigns
+type AccessReviewService struct {
+	accessReviewRepository *repository.AccessReviewRepository
+	roleRepository         *repository.RoleRepository
+	divisionRepository     *repository.DivisionRepository
+	outboxRepository       *repository.OutboxRepository
+	userService            *UserService
+	config                 *config.AuthConfig
+}
+
+// NewAccessReviewService creates a new access review service
+func NewAccessReviewService(
+	accessReviewRepository *repository.AccessReviewRepository,
+	roleRepository *repository.RoleRepository,
+	divisionRepository *repository.DivisionRepository,
+	outboxRepository *repository.OutboxRepository,
+	userService *UserService,
+	config *config.AuthConfig,
+) *AccessReviewService {
+	return &AccessReviewService{
+		accessReviewRepository: accessReviewRepository,
+		roleRepository:         roleRepository,
+		divisionRepository:     divisionRepository,
+		outboxRepository:       outboxRepository,
+		userService:            userService,
+		config:                 config,
+	}
+}
+
+// CreateCampaign creates a campaign scoped to a role, a division or both
+func (s *AccessReviewService) CreateCampaign(request *models.AccessReviewCampaignRequest, createdBy string) (*models.AccessReviewCampaignResponse, error) {
+	if request.RoleID == nil && request.DivisionID == nil {
+		return nil, errors.New("role_id or division_id is required")
+	}
+
+	// Validate scope
+	if request.RoleID != nil {
+		if _, err := s.roleRepository.FindByID(*request.RoleID); err != nil {
+			return nil, errors.New("role not found")
+		}
+	}
+	if request.DivisionID != nil {
+		if _, err := s.divisionRepository.FindByID(*request.DivisionID); err != nil {
+			return nil, errors.New("division not found")
+		}
+	}
+
+	campaign := &models.AccessReviewCampaign{
+		Name:        request.Name,
+		Description: request.Description,
+		RoleID:      request.RoleID,
+		DivisionID:  request.DivisionID,
+	}
+
+	if request.DueDate != "" {
+		dueDate, err := time.Parse("2006-01-02", request.DueDate)
+		if err != nil {
+			return nil, errors.New("invalid due date format, use YYYY-MM-DD")
+		}
+		campaign.DueDate = &dueDate
+	}
+
+	if err := s.accessReviewRepository.CreateCampaign(campaign, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.GetCampaign(campaign.ID)
+}
+
+// GetCampaign gets a campaign with its progress
+func (s *AccessReviewService) GetCampaign(id uint) (*models.AccessReviewCampaignResponse, error) {
+	campaign, err := s.accessReviewRepository.FindCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.accessReviewRepository.CountItemsByDecision(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AccessReviewCampaignResponse{
+		AccessReviewCampaign: *campaign,
+		TotalItems:           counts[models.AccessReviewDecisionPending] + counts[models.AccessReviewDecisionApproved] + counts[models.AccessReviewDecisionRevoked],
+		PendingItems:         counts[models.AccessReviewDecisionPending],
+		ApprovedItems:        counts[models.AccessReviewDecisionApproved],
+		RevokedItems:         counts[models.AccessReviewDecisionRevoked],
+	}, nil
+}
+
+// ListCampaigns lists campaigns with pagination
+func (s *AccessReviewService) ListCampaigns(page, pageSize int, status string) (*models.PaginatedResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.accessReviewRepository.ListCampaigns(page, pageSize, status)
+}
+
+// ListItems lists the items of a campaign
+func (s *AccessReviewService) ListItems(campaignID uint, decision string, reviewerID uint) ([]models.AccessReviewItem, error) {
+	if _, err := s.accessReviewRepository.FindCampaignByID(campaignID); err != nil {
+		return nil, err
+	}
+
+	return s.accessReviewRepository.ListItems(campaignID, decision, reviewerID)
+}
+
+// ListReviewerItems lists the pending items assigned to a reviewer
+func (s *AccessReviewService) ListReviewerItems(reviewerID uint) ([]models.AccessReviewItem, error) {
+	return s.accessReviewRepository.ListReviewerItems(reviewerID)
+}
+
+// Decide records an approve or revoke decision; only the assigned reviewer or an admin may decide
+func (s *AccessReviewService) Decide(itemID uint, request *models.AccessReviewDecisionRequest, actorID uint, actorRoles []string, decidedBy string) (*models.AccessReviewItem, error) {
+	item, err := s.accessReviewRepository.FindItemByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	isReviewer := item.ReviewerID != nil && *item.ReviewerID == actorID
+	if !isReviewer && !hasAnyRole(actorRoles, s.config.AdminRoles) {
+		return nil, fmt.Errorf("%w: you are not the reviewer of this item", ErrForbidden)
+	}
+
+	if item.Campaign == nil || item.Campaign.Status != models.AccessReviewStatusOpen {
+		return nil, errors.New("campaign is closed")
+	}
+
+	decision := models.AccessReviewDecisionApproved
+	if request.Decision == "revoke" {
+		decision = models.AccessReviewDecisionRevoked
+	}
+
+	if err := s.accessReviewRepository.DecideItem(itemID, decision, request.Comment, decidedBy); err != nil {
+		return nil, err
+	}
+
+	return s.accessReviewRepository.FindItemByID(itemID)
+}
+
+// CloseCampaign closes a campaign and applies its revocations in one transaction. Each revoked
+// role is removed like any other role change, and the sessions of affected users are revoked so
+// their current tokens no longer carry the role.
+func (s *AccessReviewService) CloseCampaign(id uint, closedBy string) (*models.AccessReviewCampaignResponse, error) {
+	campaign, err := s.accessReviewRepository.FindCampaignByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != models.AccessReviewStatusOpen {
+		return nil, errors.New("campaign is already closed")
+	}
+
+	now := time.Now()
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		accessReviewRepository := repository.NewAccessReviewRepository(tx)
+		sessionRepository := repository.NewSessionRepository(tx)
+		userService := s.userService.WithTx(tx)
+
+		items, err := accessReviewRepository.ListUnappliedRevocations(id)
+		if err != nil {
+			return err
+		}
+
+		// Apply revocations
+		var affected []uint
+		for _, item := range items {
+			err := userService.changeRoles(item.UserID, func(txService *UserService) error {
+				return txService.roleRepository.RemoveUserRole(item.UserID, item.RoleID)
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := accessReviewRepository.MarkItemApplied(item.ID, now); err != nil {
+				return err
+			}
+
+			if !slices.Contains(affected, item.UserID) {
+				affected = append(affected, item.UserID)
+			}
+		}
+
+		for _, userID := range affected {
+			if err := sessionRepository.RevokeAllByUser(userID, closedBy); err != nil {
+				return err
+			}
+		}
+
+		return accessReviewRepository.CloseCampaign(id, now, closedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCampaign(id)
+}
+
+// Report renders the campaign's decisions as CSV for auditors
+func (s *AccessReviewService) Report(id uint) (*models.AccessReviewCampaign, []byte, error) {
+	campaign, err := s.accessReviewRepository.FindCampaignByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := s.accessReviewRepository.ListItems(id, "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{
+		"campaign", "employee_id", "name", "email", "division", "role",
+		"reviewer", "decision", "comment", "decided_by", "decided_at", "applied_at",
+	})
+
+	for _, item := range items {
+		var employeeID, name, email, division, role, reviewer string
+		if item.User != nil {
+			employeeID = item.User.EmployeeID
+			name = item.User.Name
+			email = item.User.Email
+			if item.User.Division != nil {
+				division = item.User.Division.Name
+			}
+		}
+		if item.Role != nil {
+			role = item.Role.Name
+		}
+		if item.Reviewer != nil {
+			reviewer = item.Reviewer.EmployeeID
+		}
+
+		writer.Write([]string{
+			campaign.Name, employeeID, name, email, division, role,
+			reviewer, item.Decision, item.Comment, item.DecidedBy,
+			formatOptionalTime(item.DecidedAt), formatOptionalTime(item.AppliedAt),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	return campaign, buf.Bytes(), nil
+}
+
+// hasAnyRole reports whether any of the role names is in the allowed list
+func hasAnyRole(roles []string, allowed []string) bool {
+	for _, role := range roles {
+		for _, name := range allowed {
+			if role == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatOptionalTime formats a nullable timestamp as RFC 3339, or empty when nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
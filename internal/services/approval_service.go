@@ -0,0 +1,317 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// Approver rules
+const (
+	ApproverRuleManager = "manager"
+	ApproverRuleRole    = "role"
+)
+
+// ApprovalService holds sensitive user changes for approval before applying them
+type ApprovalService struct {
+	changeRequestRepository *repository.ChangeRequestRepository
+	userRepository          *repository.UserRepository
+	roleRepository          *repository.RoleRepository
+	userService             *UserService
+	config                  *config.ApprovalConfig
+	authConfig              *config.AuthConfig
+}
+
+// NewApprovalService creates a new approval service
+func NewApprovalService(
+	changeRequestRepository *repository.ChangeRequestRepository,
+	userRepository *repository.UserRepository,
+	roleRepository *repository.RoleRepository,
+	userService *UserService,
+	config *config.ApprovalConfig,
+	authConfig *config.AuthConfig,
+) *ApprovalService {
+	return &ApprovalService{
+		changeRequestRepository: changeRequestRepository,
+		userRepository:          userRepository,
+		roleRepository:          roleRepository,
+		userService:             userService,
+		config:                  config,
+		authConfig:              authConfig,
+	}
+}
+
+// Submit opens a change request when the update is sensitive; it returns nil when the update may apply immediately
+func (s *ApprovalService) Submit(userID uint, request *models.UpdateUserRequest, requesterID uint, requestedBy string) (*models.ChangeRequestResponse, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons, err := s.approvalReasons(user, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	// Validate now so approvers only see changes that can apply
+	if err := s.userService.ValidateUpdate(userID, request); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	changeRequest := &models.ChangeRequest{
+		TargetUserID:  userID,
+		Payload:       string(payload),
+		Reasons:       strings.Join(reasons, "; "),
+		RequestedByID: requesterID,
+		RequestedBy:   requestedBy,
+	}
+
+	// Resolve approver
+	if s.config.ApproverRule == ApproverRuleRole {
+		changeRequest.ApproverRole = s.config.ApproverRole
+	} else {
+		changeRequest.ApproverID = user.ManagerID
+	}
+
+	if err := s.changeRequestRepository.Create(changeRequest); err != nil {
+		return nil, err
+	}
+
+	return s.Get(changeRequest.ID)
+}
+
+// RoleRequiresApproval reports whether granting the role needs approval
+func (s *ApprovalService) RoleRequiresApproval(roleID uint) (bool, error) {
+	if s.config.RoleLevel <= 0 {
+		return false, nil
+	}
+
+	role, err := s.roleRepository.FindByID(roleID)
+	if err != nil {
+		return false, err
+	}
+
+	return role.Level >= s.config.RoleLevel, nil
+}
+
+// WithTx returns a copy of the service whose repositories run inside the given transaction
+func (s *ApprovalService) WithTx(tx *gorm.DB) *ApprovalService {
+	return NewApprovalService(
+		repository.NewChangeRequestRepository(tx),
+		repository.NewUserRepository(tx),
+		repository.NewRoleRepository(tx),
+		s.userService.WithTx(tx),
+		s.config,
+		s.authConfig,
+	)
+}
+
+// Get gets a change request by ID
+func (s *ApprovalService) Get(id uint) (*models.ChangeRequestResponse, error) {
+	changeRequest, err := s.changeRequestRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newChangeRequestResponse(changeRequest)
+}
+
+// List lists change requests with pagination
+func (s *ApprovalService) List(page, pageSize int, status string, targetUserID uint) (*models.PaginatedResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	paginatedResponse, err := s.changeRequestRepository.List(page, pageSize, status, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert change requests to responses
+	changeRequests := paginatedResponse.Items.([]models.ChangeRequest)
+	responses := make([]models.ChangeRequestResponse, len(changeRequests))
+	for i := range changeRequests {
+		response, err := newChangeRequestResponse(&changeRequests[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+	paginatedResponse.Items = responses
+
+	return paginatedResponse, nil
+}
+
+// Approve applies a pending change request on behalf of its requester
+func (s *ApprovalService) Approve(id uint, actorID uint, actorRoles []string, decidedBy, comment string) (*models.ChangeRequestResponse, error) {
+	changeRequest, err := s.pendingForApprover(id, actorID, actorRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	var request models.UpdateUserRequest
+	if err := json.Unmarshal([]byte(changeRequest.Payload), &request); err != nil {
+		return nil, err
+	}
+
+	err = s.changeRequestRepository.Approve(id, decidedBy, comment, func(tx *gorm.DB) error {
+		_, err := s.userService.WithTx(tx).Update(changeRequest.TargetUserID, &request, changeRequest.RequestedBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Reject rejects a pending change request
+func (s *ApprovalService) Reject(id uint, actorID uint, actorRoles []string, decidedBy, comment string) (*models.ChangeRequestResponse, error) {
+	if _, err := s.pendingForApprover(id, actorID, actorRoles); err != nil {
+		return nil, err
+	}
+
+	if err := s.changeRequestRepository.Reject(id, decidedBy, comment); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// Comment adds a comment; the requester, eligible approvers and admins may comment
+func (s *ApprovalService) Comment(id uint, body string, actorID uint, actorRoles []string, author string) (*models.ChangeRequestResponse, error) {
+	changeRequest, err := s.changeRequestRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeRequest.RequestedByID != actorID && !s.canApprove(changeRequest, actorID, actorRoles) {
+		return nil, fmt.Errorf("%w: you are not involved in this change request", ErrForbidden)
+	}
+
+	comment := &models.ChangeRequestComment{
+		ChangeRequestID: id,
+		AuthorID:        actorID,
+		Author:          author,
+		Body:            body,
+	}
+	if err := s.changeRequestRepository.AddComment(comment); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// pendingForApprover loads a change request and checks that the actor may decide on it
+func (s *ApprovalService) pendingForApprover(id uint, actorID uint, actorRoles []string) (*models.ChangeRequest, error) {
+	changeRequest, err := s.changeRequestRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeRequest.RequestedByID == actorID {
+		return nil, fmt.Errorf("%w: you cannot decide on your own change request", ErrForbidden)
+	}
+
+	if !s.canApprove(changeRequest, actorID, actorRoles) {
+		return nil, fmt.Errorf("%w: you are not an approver of this change request", ErrForbidden)
+	}
+
+	if changeRequest.Status != models.ChangeRequestStatusPending {
+		return nil, fmt.Errorf("change request is already %s", changeRequest.Status)
+	}
+
+	return changeRequest, nil
+}
+
+// canApprove reports whether the actor satisfies the change request's approver rule or is an admin
+func (s *ApprovalService) canApprove(changeRequest *models.ChangeRequest, actorID uint, actorRoles []string) bool {
+	if hasAnyRole(actorRoles, s.authConfig.AdminRoles) {
+		return true
+	}
+
+	if changeRequest.ApproverID != nil && *changeRequest.ApproverID == actorID {
+		return true
+	}
+
+	return changeRequest.ApproverRole != "" && hasAnyRole(actorRoles, []string{changeRequest.ApproverRole})
+}
+
+// approvalReasons lists why an update needs approval, empty when it may apply immediately
+func (s *ApprovalService) approvalReasons(user *models.User, request *models.UpdateUserRequest) ([]string, error) {
+	var reasons []string
+
+	// Granting high-level roles
+	if s.config.RoleLevel > 0 && request.RoleIDs != nil {
+		// Updates keep the existing assignments of listed roles, including future ones
+		grants, err := s.roleRepository.ListUserRoleGrants(user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		held := make(map[uint]bool, len(grants))
+		for _, grant := range grants {
+			held[grant.RoleID] = true
+		}
+
+		for _, roleID := range request.RoleIDs {
+			if held[roleID] {
+				continue
+			}
+			role, err := s.roleRepository.FindByID(roleID)
+			if err != nil {
+				return nil, err
+			}
+			if role.Level >= s.config.RoleLevel {
+				reasons = append(reasons, fmt.Sprintf("grants role %s (level %d)", role.Name, role.Level))
+			}
+		}
+	}
+
+	// Deactivation
+	if s.config.Deactivation && request.IsActive != nil && !*request.IsActive && user.IsActive {
+		reasons = append(reasons, "deactivates the user")
+	}
+
+	// Division change
+	if s.config.DivisionChange && request.DivisionID != nil &&
+		(user.DivisionID == nil || *user.DivisionID != *request.DivisionID) {
+		reasons = append(reasons, "changes the user's division")
+	} else if s.config.DivisionChange && user.DivisionID != nil && containsString(request.Clear, "division_id") {
+		reasons = append(reasons, "removes the user's division")
+	}
+
+	return reasons, nil
+}
+
+// newChangeRequestResponse decodes the proposed changes of a change request
+func newChangeRequestResponse(changeRequest *models.ChangeRequest) (*models.ChangeRequestResponse, error) {
+	var changes models.UpdateUserRequest
+	if err := json.Unmarshal([]byte(changeRequest.Payload), &changes); err != nil {
+		return nil, err
+	}
+
+	return &models.ChangeRequestResponse{
+		ChangeRequest: *changeRequest,
+		Changes:       &changes,
+	}, nil
+}
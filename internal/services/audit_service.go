@@ -0,0 +1,32 @@
+package services
+
+import (
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+)
+
+// AuditService handles audit log operations
+type AuditService struct {
+	auditRepository *repository.AuditRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepository *repository.AuditRepository) *AuditService {
+	return &AuditService{
+		auditRepository: auditRepository,
+	}
+}
+
+// List lists audit log entries with pagination
+func (s *AuditService) List(page, pageSize int, userID uint, impersonatedOnly bool) (*models.PaginatedResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.auditRepository.List(page, pageSize, userID, impersonatedOnly)
+}
@@ -1,36 +1,45 @@
 package services
 
 import (
+	"log"
+	"time"
+
 	"admin-dashboard/internal/models"
 	"admin-dashboard/internal/repository"
 	"admin-dashboard/internal/utils"
+
+	"github.com/google/uuid"
 )
 
 // AuthService handles authentication related operations
 type AuthService struct {
-	userRepository *repository.UserRepository
-	roleRepository *repository.RoleRepository
-	jwtManager     *utils.JWTManager
+	userRepository    *repository.UserRepository
+	roleRepository    *repository.RoleRepository
+	sessionRepository *repository.SessionRepository
+	jwtManager        *utils.JWTManager
 }
 
 // NewAuthService creates a new auth service
 func NewAuthService(
 	userRepository *repository.UserRepository,
 	roleRepository *repository.RoleRepository,
+	sessionRepository *repository.SessionRepository,
 	jwtManager *utils.JWTManager,
 ) *AuthService {
 	return &AuthService{
-		userRepository: userRepository,
-		roleRepository: roleRepository,
-		jwtManager:     jwtManager,
+		userRepository:    userRepository,
+		roleRepository:    roleRepository,
+		sessionRepository: sessionRepository,
+		jwtManager:        jwtManager,
 	}
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *AuthService) Login(email, password string) (*models.LoginResponse, error) {
+// Login authenticates a user, opens a session and returns a JWT token
+func (s *AuthService) Login(email, password, ipAddress, userAgent string) (*models.LoginResponse, error) {
 	// Authenticate user
 	user, err := s.userRepository.Authenticate(email, password)
 	if err != nil {
+		s.recordFailedLogin(email, ipAddress, userAgent, err)
 		return nil, err
 	}
 	
@@ -46,52 +55,47 @@ func (s *AuthService) Login(email, password string) (*models.LoginResponse, erro
 		roleNames[i] = role.Name
 	}
 	
-	// Generate JWT token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.UID, user.EmployeeID, user.Email, roleNames)
-	if err != nil {
-		return nil, err
+	// Open a session for the token
+	now := time.Now()
+	session := &models.UserSession{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.jwtManager.TokenLifetime()),
 	}
-	
-	// Format birthdate and join date
-	var birthdateStr string
-	if user.Birthdate != nil {
-		birthdateStr = user.Birthdate.Format("2006-01-02")
-	}
-	
-	// Create user response
-	userResponse := models.UserResponse{
-		ID:           user.ID,
-		UID:          user.UID,
-		EmployeeID:   user.EmployeeID,
-		Name:         user.Name,
-		Email:        user.Email,
-		Phone:        user.Phone,
-		Address:      user.Address,
-		Birthdate:    birthdateStr,
-		JoinDate:     user.JoinDate.Format("2006-01-02"),
-		ProfileImage: user.ProfileImage,
-		IsManager:    user.IsManager,
-		IsActive:     user.IsActive,
-		Roles:        roleNames,
+	if err := s.sessionRepository.Create(session); err != nil {
+		return nil, err
 	}
 	
-	// Add related information if available
-	if user.Division != nil {
-		userResponse.Division = user.Division.Name
+	// Generate JWT token
+	token, err := s.jwtManager.GenerateToken(user.ID, user.UID, user.EmployeeID, user.Email, roleNames, session.ID.String())
+	if err != nil {
+		return nil, err
 	}
 	
-	if user.Position != nil {
-		userResponse.Position = user.Position.Name
+	// Record the successful login
+	if err := s.userRepository.UpdateLastLogin(user.ID, now); err != nil {
+		return nil, err
 	}
+	user.LastLoginAt = &now
 	
-	if user.Manager != nil {
-		userResponse.Manager = user.Manager.Name
+	if err := s.sessionRepository.RecordLogin(&models.LoginEvent{
+		UserID:    &user.ID,
+		Email:     email,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Success:   true,
+		SessionID: &session.ID,
+	}); err != nil {
+		log.Printf("Failed to record login for %s: %v", email, err)
 	}
 	
 	// Create login response
 	response := &models.LoginResponse{
 		Token: token,
-		User:  userResponse,
+		User:  *newUserResponse(user, roleNames),
 	}
 	
 	return response, nil
@@ -117,41 +121,24 @@ func (s *AuthService) GetUserByID(id uint) (*models.UserResponse, error) {
 		roleNames[i] = role.Name
 	}
 	
-	// Format birthdate and join date
-	var birthdateStr string
-	if user.Birthdate != nil {
-		birthdateStr = user.Birthdate.Format("2006-01-02")
-	}
-	
-	// Create user response
-	userResponse := &models.UserResponse{
-		ID:           user.ID,
-		UID:          user.UID,
-		EmployeeID:   user.EmployeeID,
-		Name:         user.Name,
-		Email:        user.Email,
-		Phone:        user.Phone,
-		Address:      user.Address,
-		Birthdate:    birthdateStr,
-		JoinDate:     user.JoinDate.Format("2006-01-02"),
-		ProfileImage: user.ProfileImage,
-		IsManager:    user.IsManager,
-		IsActive:     user.IsActive,
-		Roles:        roleNames,
-	}
-	
-	// Add related information if available
-	if user.Division != nil {
-		userResponse.Division = user.Division.Name
+	return newUserResponse(user, roleNames), nil
+}
+
+// recordFailedLogin records a failed login attempt, linking it to the user when the email is known
+func (s *AuthService) recordFailedLogin(email, ipAddress, userAgent string, reason error) {
+	event := &models.LoginEvent{
+		Email:         email,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+		Success:       false,
+		FailureReason: reason.Error(),
 	}
 	
-	if user.Position != nil {
-		userResponse.Position = user.Position.Name
+	if user, err := s.userRepository.FindByEmail(email); err == nil {
+		event.UserID = &user.ID
 	}
 	
-	if user.Manager != nil {
-		userResponse.Manager = user.Manager.Name
+	if err := s.sessionRepository.RecordLogin(event); err != nil {
+		log.Printf("Failed to record failed login for %s: %v", email, err)
 	}
-	
-	return userResponse, nil
-}
\ No newline at end of file
+}
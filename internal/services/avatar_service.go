@@ -0,0 +1,264 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+	"admin-dashboard/internal/utils"
+)
+
+// Avatar sizes
+const (
+	AvatarSizeOriginal = "original" // longest side at most 1024 pixels
+	AvatarSizeMedium   = "medium"   // 256x256 square
+	AvatarSizeSmall    = "small"    // 64x64 square
+)
+
+// avatarMaxPixels rejects images that would take too much memory to decode
+const avatarMaxPixels = 40 * 1000 * 1000
+
+// avatarSize describes how one avatar size is derived from the upload
+type avatarSize struct {
+	max    int
+	square bool
+}
+
+var avatarSizes = map[string]avatarSize{
+	AvatarSizeOriginal: {max: 1024},
+	AvatarSizeMedium:   {max: 256, square: true},
+	AvatarSizeSmall:    {max: 64, square: true},
+}
+
+// Avatar upload errors
+var (
+	ErrAvatarTooLarge    = errors.New("avatar file is too large")
+	ErrAvatarUnsupported = errors.New("avatar must be a JPEG, PNG or GIF image")
+	ErrAvatarSize        = errors.New("size must be original, medium or small")
+)
+
+// Avatar is one stored avatar image
+type Avatar struct {
+	Data        []byte
+	ContentType string
+	Version     string // changes with every upload
+}
+
+// AvatarService handles profile image uploads
+type AvatarService struct {
+	userRepository *repository.UserRepository
+	userService    *UserService
+	blobStore      BlobStore
+	authConfig     *config.AuthConfig
+	maxSize        int
+}
+
+// NewAvatarService creates a new avatar service
+func NewAvatarService(
+	userRepository *repository.UserRepository,
+	userService *UserService,
+	blobStore BlobStore,
+	authConfig *config.AuthConfig,
+	storageConfig *config.StorageConfig,
+) *AvatarService {
+	return &AvatarService{
+		userRepository: userRepository,
+		userService:    userService,
+		blobStore:      blobStore,
+		authConfig:     authConfig,
+		maxSize:        storageConfig.AvatarMaxSize,
+	}
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *AvatarService) MaxSize() int {
+	return s.maxSize
+}
+
+// Upload validates an image, stores it without metadata in every avatar size and makes it the
+// user's profile image. Users may change their own avatar; admins may change anyone's.
+func (s *AvatarService) Upload(ctx context.Context, userID uint, data []byte, actorID uint, actorRoles []string, updatedBy string) (*models.UserResponse, error) {
+	if actorID != userID && !hasAnyRole(actorRoles, s.authConfig.AdminRoles) {
+		return nil, ErrForbidden
+	}
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	images, contentType, err := s.process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store every size under a new prefix, so cached copies of the previous avatar stay valid
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	prefix := fmt.Sprintf("avatars/%d/%s", userID, version)
+	for size, image := range images {
+		if err := s.blobStore.Put(ctx, avatarBlobKey(prefix, size, contentType), image, contentType); err != nil {
+			s.deleteBlobs(prefix, contentType)
+			return nil, err
+		}
+	}
+
+	profileImage := fmt.Sprintf("/api/users/%d/avatar?v=%s", userID, version)
+	response, err := s.userService.SetAvatar(userID, prefix, contentType, profileImage, updatedBy)
+	if err != nil {
+		s.deleteBlobs(prefix, contentType)
+		return nil, err
+	}
+
+	if user.AvatarKey != "" {
+		s.deleteBlobs(user.AvatarKey, user.AvatarType)
+	}
+	return response, nil
+}
+
+// Get returns one size of a user's avatar; ErrBlobNotFound when the user has none
+func (s *AvatarService) Get(ctx context.Context, userID uint, size string) (*Avatar, error) {
+	if size == "" {
+		size = AvatarSizeOriginal
+	}
+	if _, ok := avatarSizes[size]; !ok {
+		return nil, ErrAvatarSize
+	}
+
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AvatarKey == "" {
+		return nil, ErrBlobNotFound
+	}
+
+	data, err := s.blobStore.Get(ctx, avatarBlobKey(user.AvatarKey, size, user.AvatarType))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Avatar{
+		Data:        data,
+		ContentType: user.AvatarType,
+		Version:     path.Base(user.AvatarKey),
+	}, nil
+}
+
+// Delete removes a user's uploaded avatar and clears the profile image
+func (s *AvatarService) Delete(ctx context.Context, userID uint, actorID uint, actorRoles []string, updatedBy string) (*models.UserResponse, error) {
+	if actorID != userID && !hasAnyRole(actorRoles, s.authConfig.AdminRoles) {
+		return nil, ErrForbidden
+	}
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AvatarKey == "" {
+		return nil, ErrBlobNotFound
+	}
+
+	response, err := s.userService.SetAvatar(userID, "", "", "", updatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.deleteBlobs(user.AvatarKey, user.AvatarType)
+	return response, nil
+}
+
+// process checks an upload and renders every avatar size. Re-encoding the decoded pixels drops
+// EXIF and any other metadata; the EXIF orientation is applied first so photos stay upright.
+func (s *AvatarService) process(data []byte) (map[string][]byte, string, error) {
+	if len(data) > s.maxSize {
+		return nil, "", ErrAvatarTooLarge
+	}
+
+	detected := http.DetectContentType(data)
+	switch detected {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, "", ErrAvatarUnsupported
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrAvatarUnsupported
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width*cfg.Height > avatarMaxPixels {
+		return nil, "", fmt.Errorf("avatar must be at most %d megapixels", avatarMaxPixels/1000000)
+	}
+
+	var img image.Image
+	switch detected {
+	case "image/jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+		if err == nil {
+			img = utils.ApplyOrientation(img, utils.JPEGOrientation(data))
+		}
+	case "image/png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "image/gif":
+		img, err = gif.Decode(bytes.NewReader(data)) // the first frame
+	}
+	if err != nil {
+		return nil, "", ErrAvatarUnsupported
+	}
+
+	// Photos stay JPEG; PNG and GIF become PNG to keep transparency
+	contentType := "image/png"
+	if detected == "image/jpeg" {
+		contentType = "image/jpeg"
+	}
+
+	images := make(map[string][]byte, len(avatarSizes))
+	for name, size := range avatarSizes {
+		resized := img
+		if size.square {
+			resized = utils.CropSquare(resized)
+		}
+		resized = utils.ResizeToFit(resized, size.max)
+
+		var buf bytes.Buffer
+		if contentType == "image/jpeg" {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		images[name] = buf.Bytes()
+	}
+
+	return images, contentType, nil
+}
+
+// deleteBlobs removes every size of an avatar. Failures only leave orphaned files behind, so
+// they are logged rather than returned.
+func (s *AvatarService) deleteBlobs(prefix, contentType string) {
+	for size := range avatarSizes {
+		if err := s.blobStore.Delete(context.Background(), avatarBlobKey(prefix, size, contentType)); err != nil {
+			log.Printf("Failed to delete avatar %s: %v", prefix, err)
+		}
+	}
+}
+
+// avatarBlobKey returns the blob key of one avatar size
+func avatarBlobKey(prefix, size, contentType string) string {
+	if contentType == "image/jpeg" {
+		return prefix + "/" + size + ".jpg"
+	}
+	return prefix + "/" + size + ".png"
+}
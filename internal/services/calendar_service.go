@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// calendarFeedPast is how far back a calendar feed lists events
+	calendarFeedPast = 30 * 24 * time.Hour
+	// calendarFeedAhead is how far ahead a calendar feed lists events
+	calendarFeedAhead = 335 * 24 * time.Hour
+)
+
+// CalendarService handles managers' iCalendar feeds of their direct reports
+type CalendarService struct {
+	calendarFeedRepository *repository.CalendarFeedRepository
+	userRepository         *repository.UserRepository
+	dashboardService       *DashboardService
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(
+	calendarFeedRepository *repository.CalendarFeedRepository,
+	userRepository *repository.UserRepository,
+	dashboardService *DashboardService,
+) *CalendarService {
+	return &CalendarService{
+		calendarFeedRepository: calendarFeedRepository,
+		userRepository:         userRepository,
+		dashboardService:       dashboardService,
+	}
+}
+
+// CreateFeed creates a manager's feed and returns its secret token. An existing feed gets a new
+// token, so the old subscription URL stops working.
+func (s *CalendarService) CreateFeed(userID uint, createdBy string) (string, *models.CalendarFeed, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if !user.IsManager {
+		return "", nil, ErrForbidden
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	feed := &models.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashFeedToken(token),
+	}
+	if err := s.calendarFeedRepository.Save(feed, createdBy); err != nil {
+		return "", nil, err
+	}
+
+	return token, feed, nil
+}
+
+// DeleteFeed deletes a manager's feed
+func (s *CalendarService) DeleteFeed(userID uint) error {
+	return s.calendarFeedRepository.DeleteByUser(userID)
+}
+
+// RenderFeed renders the iCalendar feed for a token. Feeds of users who are no longer active
+// managers are not found.
+func (s *CalendarService) RenderFeed(token string) ([]byte, error) {
+	feed, err := s.calendarFeedRepository.FindByTokenHash(hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed.User == nil || !feed.User.IsActive || !feed.User.IsManager {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	now := time.Now().UTC()
+	events, err := s.dashboardService.GetCalendar(now.Add(-calendarFeedPast), now.Add(calendarFeedAhead), nil, &feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderICalendar(feed.User.Name+" - team calendar", events, now), nil
+}
+
+// hashFeedToken hashes a feed token for storage and lookup
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// renderICalendar renders calendar events as all-day iCalendar (RFC 5545) events
+func renderICalendar(name string, events []models.CalendarEvent, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:-//admin-dashboard//calendar//EN")
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	writeICalLine(&buf, "X-WR-CALNAME:"+escapeICalText(name))
+
+	stamp := now.Format("20060102T150405Z")
+	for _, event := range events {
+		date := event.Date.Format("20060102")
+
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, fmt.Sprintf("UID:%s-%d-%s@admin-dashboard", event.Type, event.UserID, date))
+		writeICalLine(&buf, "DTSTAMP:"+stamp)
+		writeICalLine(&buf, "DTSTART;VALUE=DATE:"+date)
+		writeICalLine(&buf, "DTEND;VALUE=DATE:"+event.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeICalLine(&buf, "SUMMARY:"+escapeICalText(calendarEventSummary(&event)))
+		if event.Division != "" {
+			writeICalLine(&buf, "DESCRIPTION:"+escapeICalText(event.EmployeeID+", "+event.Division))
+		} else {
+			writeICalLine(&buf, "DESCRIPTION:"+escapeICalText(event.EmployeeID))
+		}
+		writeICalLine(&buf, "TRANSP:TRANSPARENT")
+		writeICalLine(&buf, "END:VEVENT")
+	}
+
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// calendarEventSummary returns the title of a calendar event
+func calendarEventSummary(event *models.CalendarEvent) string {
+	switch event.Type {
+	case models.CalendarEventBirthday:
+		return "Birthday: " + event.Name
+	case models.CalendarEventWorkAnniversary:
+		if event.Years == 1 {
+			return "1 year at the company: " + event.Name
+		}
+		return fmt.Sprintf("%d years at the company: %s", event.Years, event.Name)
+	case models.CalendarEventProbationEnd:
+		return "Probation ends: " + event.Name
+	}
+	return event.Name
+}
+
+// escapeICalText escapes a TEXT property value
+func escapeICalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeICalLine writes a content line, folded at 75 octets without splitting UTF-8 characters
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ChecklistService handles onboarding and offboarding checklists
+type ChecklistService struct {
+	checklistRepository *repository.ChecklistRepository
+	userRepository      *repository.UserRepository
+	roleRepository      *repository.RoleRepository
+	divisionRepository  *repository.DivisionRepository
+	positionRepository  *repository.PositionRepository
+	outboxRepository    *repository.OutboxRepository
+	authConfig          *config.AuthConfig
+}
+
+// NewChecklistService creates a new checklist service
+func NewChecklistService(
+	checklistRepository *repository.ChecklistRepository,
+	userRepository *repository.UserRepository,
+	roleRepository *repository.RoleRepository,
+	divisionRepository *repository.DivisionRepository,
+	positionRepository *repository.PositionRepository,
+	outboxRepository *repository.OutboxRepository,
+	authConfig *config.AuthConfig,
+) *ChecklistService {
+	return &ChecklistService{
+		checklistRepository: checklistRepository,
+		userRepository:      userRepository,
+		roleRepository:      roleRepository,
+		divisionRepository:  divisionRepository,
+		positionRepository:  positionRepository,
+		outboxRepository:    outboxRepository,
+		authConfig:          authConfig,
+	}
+}
+
+// ListTemplates lists the templates of a kind, or of every kind when kind is empty
+func (s *ChecklistService) ListTemplates(kind string) ([]models.ChecklistTemplate, error) {
+	return s.checklistRepository.ListTemplates(kind)
+}
+
+// GetTemplate gets a template by ID
+func (s *ChecklistService) GetTemplate(id uint) (*models.ChecklistTemplate, error) {
+	return s.checklistRepository.FindTemplateByID(id)
+}
+
+// CreateTemplate creates a template
+func (s *ChecklistService) CreateTemplate(request *models.ChecklistTemplateRequest, createdBy string) (*models.ChecklistTemplate, error) {
+	template := &models.ChecklistTemplate{IsActive: true}
+	if err := s.applyTemplateRequest(template, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.checklistRepository.CreateTemplate(template, createdBy); err != nil {
+		return nil, err
+	}
+	return s.checklistRepository.FindTemplateByID(template.ID)
+}
+
+// UpdateTemplate updates a template. Checklists already created from it are not changed.
+func (s *ChecklistService) UpdateTemplate(id uint, request *models.ChecklistTemplateRequest, updatedBy string) (*models.ChecklistTemplate, error) {
+	template, err := s.checklistRepository.FindTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyTemplateRequest(template, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.checklistRepository.UpdateTemplate(template, updatedBy); err != nil {
+		return nil, err
+	}
+	return s.checklistRepository.FindTemplateByID(id)
+}
+
+// DeleteTemplate deletes a template
+func (s *ChecklistService) DeleteTemplate(id uint) error {
+	return s.checklistRepository.DeleteTemplate(id)
+}
+
+// ListByUser lists a user's checklists. Admins, the user and the user's manager may see them.
+func (s *ChecklistService) ListByUser(userID uint, actorID uint, actorRoles []string) ([]models.Checklist, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAnyRole(actorRoles, s.authConfig.AdminRoles) && user.ID != actorID &&
+		(user.ManagerID == nil || *user.ManagerID != actorID) {
+		return nil, ErrForbidden
+	}
+	return s.checklistRepository.ListByUser(userID)
+}
+
+// ListTasks lists checklist tasks. Non-admins only see the tasks they may complete.
+func (s *ChecklistService) ListTasks(filter *models.ChecklistTaskFilter) ([]models.ChecklistTaskItem, error) {
+	switch filter.Status {
+	case "", "open", "overdue", "completed":
+	default:
+		return nil, errors.New("status must be open, overdue or completed")
+	}
+	if !hasAnyRole(filter.ActorRoles, s.authConfig.AdminRoles) {
+		filter.Mine = true
+	}
+
+	now := today()
+	tasks, err := s.checklistRepository.ListTasks(filter, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].CompletedAt == nil && tasks[i].DueDate.Before(now) {
+			tasks[i].DaysOverdue = daysBetween(tasks[i].DueDate, now)
+		}
+	}
+	return tasks, nil
+}
+
+// CompleteTask marks a task as completed
+func (s *ChecklistService) CompleteTask(id uint, actorID uint, actorEmployeeID string, actorRoles []string) (*models.ChecklistTask, error) {
+	return s.setTaskCompletion(id, true, actorID, actorEmployeeID, actorRoles)
+}
+
+// ReopenTask marks a completed task as open again
+func (s *ChecklistService) ReopenTask(id uint, actorID uint, actorEmployeeID string, actorRoles []string) (*models.ChecklistTask, error) {
+	return s.setTaskCompletion(id, false, actorID, actorEmployeeID, actorRoles)
+}
+
+// HandleEvent creates onboarding checklists for created users and offboarding checklists for
+// deactivated users, and removes the checklists of deleted users. It is subscribed to the event
+// bus and runs in the dispatcher's transaction.
+func (s *ChecklistService) HandleEvent(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error {
+	checklistRepository := repository.NewChecklistRepository(tx)
+
+	var kind string
+	switch e := event.(type) {
+	case *models.UserCreated:
+		kind = models.ChecklistOnboarding
+	case *models.UserDeactivated:
+		kind = models.ChecklistOffboarding
+	case *models.UserDeleted:
+		return checklistRepository.DeleteByUser(e.UserID)
+	default:
+		return nil
+	}
+
+	user, err := repository.NewUserRepository(tx).FindByID(record.AggregateID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Deleted before the event was dispatched
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	templates, err := checklistRepository.FindMatchingTemplates(kind, user.DivisionID, user.PositionID)
+	if err != nil {
+		return err
+	}
+
+	// Due dates count from the join date when onboarding and the deactivation date when offboarding
+	base := user.JoinDate
+	if kind == models.ChecklistOffboarding {
+		base = time.Now()
+		if user.DeactivatedAt != nil {
+			base = *user.DeactivatedAt
+		}
+	}
+	base = time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, time.UTC)
+
+	outboxRepository := repository.NewOutboxRepository(tx)
+	for i := range templates {
+		template := &templates[i]
+		templateID := template.ID
+		checklist := &models.Checklist{
+			UserID:     user.ID,
+			TemplateID: &templateID,
+			Kind:       kind,
+			Name:       template.Name,
+			Tasks:      make([]models.ChecklistTask, len(template.Tasks)),
+		}
+		for j, task := range template.Tasks {
+			checklist.Tasks[j] = models.ChecklistTask{
+				Title:    task.Title,
+				RoleID:   task.RoleID,
+				DueDate:  base.AddDate(0, 0, task.DueDays),
+				Position: task.Position,
+			}
+			// Without a manager the task is left to admins
+			if task.AssignToManager {
+				checklist.Tasks[j].AssigneeUserID = user.ManagerID
+			}
+		}
+
+		if err := checklistRepository.CreateChecklist(checklist); err != nil {
+			return err
+		}
+		if err := appendEvent(outboxRepository, &models.ChecklistCreated{Checklist: checklist}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTaskCompletion completes or reopens a task. Admins, holders of the task's role and the
+// assigned manager may change a task.
+func (s *ChecklistService) setTaskCompletion(id uint, completed bool, actorID uint, actorEmployeeID string, actorRoles []string) (*models.ChecklistTask, error) {
+	task, err := s.checklistRepository.FindTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canComplete(task, actorID, actorRoles) {
+		return nil, ErrForbidden
+	}
+	if (task.CompletedAt != nil) == completed {
+		return task, nil
+	}
+
+	checklist, err := s.checklistRepository.FindChecklistByID(task.ChecklistID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		checklistRepository := repository.NewChecklistRepository(tx)
+		outboxRepository := repository.NewOutboxRepository(tx)
+
+		if !completed {
+			if err := checklistRepository.SetTaskCompletion(task, nil, ""); err != nil {
+				return err
+			}
+			return appendEvent(outboxRepository, &models.ChecklistTaskReopened{UserID: checklist.UserID, Task: task})
+		}
+
+		now := time.Now()
+		if err := checklistRepository.SetTaskCompletion(task, &now, actorEmployeeID); err != nil {
+			return err
+		}
+		return appendEvent(outboxRepository, &models.ChecklistTaskCompleted{UserID: checklist.UserID, Task: task})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// canComplete reports whether the actor may complete or reopen a task
+func (s *ChecklistService) canComplete(task *models.ChecklistTask, actorID uint, actorRoles []string) bool {
+	if hasAnyRole(actorRoles, s.authConfig.AdminRoles) {
+		return true
+	}
+	if task.AssigneeUserID != nil && *task.AssigneeUserID == actorID {
+		return true
+	}
+	return task.Role != nil && containsString(actorRoles, task.Role.Name)
+}
+
+// applyTemplateRequest validates a template request and copies it onto a template
+func (s *ChecklistService) applyTemplateRequest(template *models.ChecklistTemplate, request *models.ChecklistTemplateRequest) error {
+	if request.DivisionID != nil {
+		if _, err := s.divisionRepository.FindByID(*request.DivisionID); err != nil {
+			return errors.New("division not found")
+		}
+	}
+	if request.PositionID != nil {
+		if _, err := s.positionRepository.FindByID(*request.PositionID); err != nil {
+			return errors.New("position not found")
+		}
+	}
+
+	tasks := make([]models.ChecklistTemplateTask, len(request.Tasks))
+	for i, task := range request.Tasks {
+		if (task.RoleID != nil) == task.AssignToManager {
+			return errors.New("each task needs either a role_id or assign_to_manager")
+		}
+		if task.RoleID != nil {
+			if _, err := s.roleRepository.FindByID(*task.RoleID); err != nil {
+				return errors.New("role not found")
+			}
+		}
+		tasks[i] = models.ChecklistTemplateTask{
+			Title:           task.Title,
+			RoleID:          task.RoleID,
+			AssignToManager: task.AssignToManager,
+			DueDays:         task.DueDays,
+		}
+	}
+
+	template.Name = request.Name
+	template.Kind = request.Kind
+	template.DivisionID = request.DivisionID
+	template.PositionID = request.PositionID
+	if request.IsActive != nil {
+		template.IsActive = *request.IsActive
+	}
+	template.Tasks = tasks
+	return nil
+}
+
+// daysBetween returns the number of whole calendar days from one date to a later one
+func daysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// customFieldKeyPattern restricts keys to names that are safe to use in jsonb queries
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// ErrUnknownCustomField is returned for values or filters of fields that are not defined
+var ErrUnknownCustomField = errors.New("unknown custom field")
+
+// CustomFieldService handles admin-defined custom field definitions
+type CustomFieldService struct {
+	customFieldRepository *repository.CustomFieldRepository
+}
+
+// NewCustomFieldService creates a new custom field service
+func NewCustomFieldService(customFieldRepository *repository.CustomFieldRepository) *CustomFieldService {
+	return &CustomFieldService{
+		customFieldRepository: customFieldRepository,
+	}
+}
+
+// List lists the definitions of an entity, or of every entity when entity is empty
+func (s *CustomFieldService) List(entity string) ([]models.CustomFieldDefinition, error) {
+	return s.customFieldRepository.List(entity)
+}
+
+// Get gets a definition by ID
+func (s *CustomFieldService) Get(id uint) (*models.CustomFieldDefinition, error) {
+	return s.customFieldRepository.FindByID(id)
+}
+
+// Create creates a definition
+func (s *CustomFieldService) Create(request *models.CustomFieldDefinitionRequest, createdBy string) (*models.CustomFieldDefinition, error) {
+	if !customFieldKeyPattern.MatchString(request.Key) {
+		return nil, errors.New("key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	_, err := s.customFieldRepository.FindByKey(request.Entity, request.Key)
+	if err == nil {
+		return nil, errors.New("custom field key already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	definition := &models.CustomFieldDefinition{
+		Entity: request.Entity,
+		Key:    request.Key,
+		Type:   request.Type,
+	}
+	if err := applyCustomFieldRequest(definition, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.customFieldRepository.Create(definition, createdBy); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// Update updates a definition's label and validation rules. New rules apply to values saved
+// from now on; existing values are not revalidated.
+func (s *CustomFieldService) Update(id uint, request *models.CustomFieldDefinitionRequest, updatedBy string) (*models.CustomFieldDefinition, error) {
+	definition, err := s.customFieldRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Entity != definition.Entity || request.Key != definition.Key || request.Type != definition.Type {
+		return nil, errors.New("entity, key and type cannot be changed")
+	}
+
+	if err := applyCustomFieldRequest(definition, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.customFieldRepository.Update(definition, updatedBy); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// Delete deletes a definition together with its stored values
+func (s *CustomFieldService) Delete(id uint) error {
+	definition, err := s.customFieldRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	return s.customFieldRepository.Delete(definition)
+}
+
+// applyCustomFieldRequest validates the rules of a request and copies them onto a definition
+func applyCustomFieldRequest(definition *models.CustomFieldDefinition, request *models.CustomFieldDefinitionRequest) error {
+	if request.Min != nil && request.Max != nil && *request.Min > *request.Max {
+		return errors.New("min must not be greater than max")
+	}
+	if request.Pattern != "" {
+		if request.Type != models.CustomFieldTypeString {
+			return errors.New("pattern is only supported for string fields")
+		}
+		if _, err := regexp.Compile(request.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if (request.Min != nil || request.Max != nil) && request.Type != models.CustomFieldTypeString && request.Type != models.CustomFieldTypeNumber {
+		return errors.New("min and max are only supported for string and number fields")
+	}
+	if request.Type == models.CustomFieldTypeSelect {
+		if len(request.Options) == 0 {
+			return errors.New("select fields need at least one option")
+		}
+		for _, option := range request.Options {
+			if option == "" || strings.Contains(option, ",") {
+				return errors.New("options must be non-empty and must not contain commas")
+			}
+		}
+	} else if len(request.Options) > 0 {
+		return errors.New("options are only supported for select fields")
+	}
+
+	definition.Label = request.Label
+	definition.Required = request.Required
+	definition.Options = request.Options
+	definition.Pattern = request.Pattern
+	definition.Min = request.Min
+	definition.Max = request.Max
+	return nil
+}
+
+// mergeCustomFields validates custom field input against an entity's definitions and merges it
+// into the current values. A null value removes a field. When creating, every required field
+// must be set; when updating, required fields cannot be removed.
+func mergeCustomFields(
+	definitions []models.CustomFieldDefinition,
+	current models.CustomFields,
+	input map[string]interface{},
+	create bool,
+) (models.CustomFields, error) {
+	byKey := make(map[string]*models.CustomFieldDefinition, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+
+	merged := make(models.CustomFields, len(current)+len(input))
+	for key, value := range current {
+		merged[key] = value
+	}
+
+	// Report problems in key order so the message is stable
+	keys := make([]string, 0, len(input))
+	for key := range input {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownCustomField, key)
+		}
+		value := input[key]
+		if value == nil {
+			if definition.Required {
+				return nil, fmt.Errorf("custom field %q is required", key)
+			}
+			delete(merged, key)
+			continue
+		}
+		if err := validateCustomFieldValue(definition, value); err != nil {
+			return nil, err
+		}
+		merged[key] = value
+	}
+
+	if create {
+		for i := range definitions {
+			if definitions[i].Required && merged[definitions[i].Key] == nil {
+				return nil, fmt.Errorf("custom field %q is required", definitions[i].Key)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// validateCustomFieldValue checks a decoded JSON value against a definition
+func validateCustomFieldValue(definition *models.CustomFieldDefinition, value interface{}) error {
+	switch definition.Type {
+	case models.CustomFieldTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("custom field %q must be a string", definition.Key)
+		}
+		if definition.Required && s == "" {
+			return fmt.Errorf("custom field %q is required", definition.Key)
+		}
+		length := float64(utf8.RuneCountInString(s))
+		if definition.Min != nil && length < *definition.Min {
+			return fmt.Errorf("custom field %q must be at least %g characters", definition.Key, *definition.Min)
+		}
+		if definition.Max != nil && length > *definition.Max {
+			return fmt.Errorf("custom field %q must be at most %g characters", definition.Key, *definition.Max)
+		}
+		if definition.Pattern != "" {
+			pattern, err := regexp.Compile(definition.Pattern)
+			if err != nil {
+				return err
+			}
+			if !pattern.MatchString(s) {
+				return fmt.Errorf("custom field %q does not match the required format", definition.Key)
+			}
+		}
+	case models.CustomFieldTypeNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("custom field %q must be a number", definition.Key)
+		}
+		if definition.Min != nil && n < *definition.Min {
+			return fmt.Errorf("custom field %q must be at least %g", definition.Key, *definition.Min)
+		}
+		if definition.Max != nil && n > *definition.Max {
+			return fmt.Errorf("custom field %q must be at most %g", definition.Key, *definition.Max)
+		}
+	case models.CustomFieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("custom field %q must be a boolean", definition.Key)
+		}
+	case models.CustomFieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("custom field %q must be a date (YYYY-MM-DD)", definition.Key)
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return fmt.Errorf("custom field %q must be a date (YYYY-MM-DD)", definition.Key)
+		}
+	case models.CustomFieldTypeSelect:
+		s, ok := value.(string)
+		if !ok || !definition.Options.Contains(s) {
+			return fmt.Errorf("custom field %q must be one of %s", definition.Key, strings.Join(definition.Options, ", "))
+		}
+	default:
+		return fmt.Errorf("custom field %q has unknown type %q", definition.Key, definition.Type)
+	}
+	return nil
+}
+
+// customFieldConditions turns custom field filters into conditions on an entity's jsonb column.
+// Values are compared as text, so numbers and booleans are written as in JSON.
+func customFieldConditions(definitions []models.CustomFieldDefinition, column string, filters map[string]string) ([]repository.Condition, error) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conditions := make([]repository.Condition, 0, len(filters))
+	for _, key := range keys {
+		found := false
+		for i := range definitions {
+			if definitions[i].Key == key {
+				found = true
+				break
+			}
+		}
+		// Only defined keys reach the query; they are restricted by customFieldKeyPattern
+		if !found || !customFieldKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%w %q", ErrUnknownCustomField, key)
+		}
+		conditions = append(conditions, repository.Condition{
+			Column:   fmt.Sprintf("%s ->> '%s'", column, key),
+			Operator: "=",
+			Value:    filters[key],
+		})
+	}
+	return conditions, nil
+}
@@ -1,8 +1,12 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
+	"admin-dashboard/internal/config"
 	"admin-dashboard/internal/models"
 
 	"gorm.io/gorm"
@@ -10,19 +14,67 @@ import (
 
 // DashboardService handles dashboard-related operations
 type DashboardService struct {
-	db *gorm.DB
+	db             *gorm.DB
+	statsCache     StatsCache
+	authConfig     *config.AuthConfig
+	calendarConfig *config.CalendarConfig
 }
 
 // NewDashboardService creates a new dashboard service
-func NewDashboardService(db *gorm.DB) *DashboardService {
+func NewDashboardService(
+	db *gorm.DB,
+	statsCache StatsCache,
+	authConfig *config.AuthConfig,
+	calendarConfig *config.CalendarConfig,
+) *DashboardService {
 	return &DashboardService{
-		db: db,
+		db:             db,
+		statsCache:     statsCache,
+		authConfig:     authConfig,
+		calendarConfig: calendarConfig,
 	}
 }
 
-// GetStatistics gets dashboard statistics
-func (s *DashboardService) GetStatistics() (*models.Statistics, error) {
-	var stats models.Statistics
+// GetStatistics gets dashboard statistics from the cache when possible. Fresh statistics that
+// bypass the cache may only be requested by admins.
+func (s *DashboardService) GetStatistics(fresh bool, actorRoles []string) (*models.Statistics, error) {
+	if fresh {
+		if !hasAnyRole(actorRoles, s.authConfig.AdminRoles) {
+			return nil, ErrForbidden
+		}
+		return s.RefreshStatistics()
+	}
+
+	if stats, ok := s.statsCache.Get(); ok {
+		return stats, nil
+	}
+	return s.RefreshStatistics()
+}
+
+// RefreshStatistics computes dashboard statistics and stores them in the cache
+func (s *DashboardService) RefreshStatistics() (*models.Statistics, error) {
+	stats, err := s.computeStatistics()
+	if err != nil {
+		return nil, err
+	}
+
+	s.statsCache.Set(stats)
+	return stats, nil
+}
+
+// HandleEvent invalidates cached statistics when users, divisions, positions or checklists
+// change. It is subscribed to the event bus.
+func (s *DashboardService) HandleEvent(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error {
+	switch record.AggregateType {
+	case "user", "division", "position", "checklist":
+		s.statsCache.Invalidate()
+	}
+	return nil
+}
+
+// computeStatistics runs the statistics queries
+func (s *DashboardService) computeStatistics() (*models.Statistics, error) {
+	stats := models.Statistics{GeneratedAt: time.Now()}
 	
 	// Get total users
 	if err := s.db.Model(&models.User{}).Count(&stats.TotalUsers).Error; err != nil {
@@ -108,5 +160,338 @@ func (s *DashboardService) GetStatistics() (*models.Statistics, error) {
 		return nil, err
 	}
 	
+	// Get open checklist tasks that are past their due date
+	if err := s.db.Model(&models.ChecklistTask{}).
+		Where("clt_completed_at IS NULL AND clt_due_date < ?", today().Format("2006-01-02")).
+		Count(&stats.OverdueChecklistTasks).Error; err != nil {
+		return nil, err
+	}
+	
 	return &stats, nil
-}
\ No newline at end of file
+}
+
+// GetInactiveUsers lists active users who have not logged in for the given number of days.
+// Users who never logged in are included once their account is older than the cutoff.
+func (s *DashboardService) GetInactiveUsers(days int) ([]models.InactiveUser, error) {
+	if days < 1 {
+		days = 90
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	
+	var users []models.InactiveUser
+	
+	inactiveQuery := `
+        SELECT u.u_id as id, u.u_employee_id as employee_id, u.u_name as name, u.u_email as email,
+               COALESCE(d.div_name, '') as division, u.u_last_login_at as last_login_at
+        FROM "user".users u 
+        LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id 
+        WHERE u.u_is_active = true 
+          AND COALESCE(u.u_last_login_at, u.u_created_at) < ?
+        ORDER BY u.u_last_login_at ASC NULLS FIRST, u.u_name
+    `
+	
+	if err := s.db.Raw(inactiveQuery, cutoff).Scan(&users).Error; err != nil {
+		return nil, err
+	}
+	
+	return users, nil
+}
+
+// trendIntervals maps supported trend intervals to their Postgres interval
+var trendIntervals = map[string]string{
+	"week":    "1 week",
+	"month":   "1 month",
+	"quarter": "3 months",
+	"year":    "1 year",
+}
+
+// trendGroups maps supported trend breakdowns to the grouping expression
+var trendGroups = map[string]string{
+	"division": "COALESCE(d.div_name, 'Unassigned')",
+	"position": "COALESCE(p.pos_name, 'Unassigned')",
+}
+
+// maxTrendPeriods limits the number of periods a trends report may span
+const maxTrendPeriods = 520
+
+// GetTrends reports hires, leavers, headcount and turnover per period between from and to,
+// optionally broken down by the users' current division or position. Periods start at the
+// beginning of the interval containing from. Hires are counted by join date and leavers by
+// deactivation date.
+func (s *DashboardService) GetTrends(from, to time.Time, interval, groupBy string) (*models.TrendsResponse, error) {
+	if interval == "" {
+		interval = "month"
+	}
+	step, ok := trendIntervals[interval]
+	if !ok {
+		return nil, errors.New("interval must be one of week, month, quarter or year")
+	}
+
+	groupExpr := "''"
+	if groupBy != "" {
+		if groupExpr, ok = trendGroups[groupBy]; !ok {
+			return nil, errors.New("group_by must be division or position")
+		}
+	}
+
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	if to.Sub(from) > maxTrendPeriods*7*24*time.Hour {
+		return nil, fmt.Errorf("the report may span at most %d weeks", maxTrendPeriods)
+	}
+
+	var rows []struct {
+		PeriodStart    time.Time
+		PeriodEnd      time.Time
+		GroupName      string
+		Hires          int64
+		Leavers        int64
+		StartHeadcount int64
+		EndHeadcount   int64
+	}
+
+	trendQuery := `
+        WITH periods AS (
+            SELECT series AS period_start, series + CAST(@step AS interval) AS period_end
+            FROM generate_series(date_trunc(@unit, CAST(@from AS timestamp)), CAST(@to AS timestamp), CAST(@step AS interval)) series
+        )
+        SELECT periods.period_start, periods.period_end, ` + groupExpr + ` AS group_name,
+               COUNT(u.u_id) FILTER (WHERE u.u_join_date >= periods.period_start AND u.u_join_date < periods.period_end) AS hires,
+               COUNT(u.u_id) FILTER (WHERE u.u_deactivated_at >= periods.period_start AND u.u_deactivated_at < periods.period_end) AS leavers,
+               COUNT(u.u_id) FILTER (WHERE u.u_join_date < periods.period_start
+                                       AND (u.u_deactivated_at IS NULL OR u.u_deactivated_at >= periods.period_start)) AS start_headcount,
+               COUNT(u.u_id) FILTER (WHERE u.u_join_date < periods.period_end
+                                       AND (u.u_deactivated_at IS NULL OR u.u_deactivated_at >= periods.period_end)) AS end_headcount
+        FROM periods
+        CROSS JOIN "user".users u
+        LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+        LEFT JOIN "user".positions p ON u.u_position_id = p.pos_id
+        GROUP BY periods.period_start, periods.period_end, group_name
+        ORDER BY periods.period_start, group_name
+    `
+
+	err := s.db.Raw(trendQuery, map[string]interface{}{
+		"step": step,
+		"unit": interval,
+		"from": from,
+		"to":   to,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.TrendsResponse{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Interval: interval,
+		GroupBy:  groupBy,
+		Points:   make([]models.TrendPoint, len(rows)),
+	}
+	for i, row := range rows {
+		point := models.TrendPoint{
+			PeriodStart:    row.PeriodStart.Format("2006-01-02"),
+			PeriodEnd:      row.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+			Group:          row.GroupName,
+			Hires:          row.Hires,
+			Leavers:        row.Leavers,
+			StartHeadcount: row.StartHeadcount,
+			Headcount:      row.EndHeadcount,
+			NetChange:      row.EndHeadcount - row.StartHeadcount,
+		}
+		if average := float64(row.StartHeadcount+row.EndHeadcount) / 2; average > 0 {
+			point.TurnoverRate = math.Round(float64(row.Leavers)/average*10000) / 100
+		}
+		response.Points[i] = point
+	}
+
+	return response, nil
+}
+
+// GetHeadcountReport lists who joined and left in [from, to) with the headcount at both ends,
+// optionally limited to the users currently in one division
+func (s *DashboardService) GetHeadcountReport(from, to time.Time, divisionID *uint) (*models.HeadcountReport, error) {
+	params := map[string]interface{}{
+		"from": from,
+		"to":   to,
+	}
+	divisionFilter := ""
+	if divisionID != nil {
+		divisionFilter = " AND u.u_division_id = @division"
+		params["division"] = *divisionID
+	}
+
+	var headcount struct {
+		StartHeadcount int64
+		Headcount      int64
+	}
+
+	headcountQuery := `
+        SELECT COUNT(u.u_id) FILTER (WHERE u.u_join_date < @from AND (u.u_deactivated_at IS NULL OR u.u_deactivated_at >= @from)) AS start_headcount,
+               COUNT(u.u_id) FILTER (WHERE u.u_join_date < @to AND (u.u_deactivated_at IS NULL OR u.u_deactivated_at >= @to)) AS headcount
+        FROM "user".users u
+        WHERE true` + divisionFilter
+
+	if err := s.db.Raw(headcountQuery, params).Scan(&headcount).Error; err != nil {
+		return nil, err
+	}
+
+	report := &models.HeadcountReport{
+		From:           from,
+		To:             to,
+		StartHeadcount: headcount.StartHeadcount,
+		Headcount:      headcount.Headcount,
+	}
+
+	changesQuery := `
+        SELECT u.u_id AS user_id, u.u_employee_id AS employee_id, u.u_name AS name,
+               COALESCE(d.div_name, '') AS division, COALESCE(p.pos_name, '') AS position,
+               'joined' AS change, CAST(u.u_join_date AS timestamptz) AS "date"
+        FROM "user".users u
+        LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+        LEFT JOIN "user".positions p ON u.u_position_id = p.pos_id
+        WHERE u.u_join_date >= @from AND u.u_join_date < @to` + divisionFilter + `
+        UNION ALL
+        SELECT u.u_id, u.u_employee_id, u.u_name,
+               COALESCE(d.div_name, ''), COALESCE(p.pos_name, ''),
+               'left', u.u_deactivated_at
+        FROM "user".users u
+        LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+        LEFT JOIN "user".positions p ON u.u_position_id = p.pos_id
+        WHERE u.u_deactivated_at >= @from AND u.u_deactivated_at < @to` + divisionFilter + `
+        ORDER BY "date", name
+    `
+
+	if err := s.db.Raw(changesQuery, params).Scan(&report.Changes).Error; err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetUpcomingAnniversaries lists the work anniversaries of active users falling within the given
+// number of days from the given date, optionally limited to one division
+func (s *DashboardService) GetUpcomingAnniversaries(from time.Time, days int, divisionID *uint) ([]models.WorkAnniversary, error) {
+	if days < 1 {
+		days = 30
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+
+	params := map[string]interface{}{
+		"from": from,
+		"to":   from.AddDate(0, 0, days),
+	}
+	divisionFilter := ""
+	if divisionID != nil {
+		divisionFilter = " AND u.u_division_id = @division"
+		params["division"] = *divisionID
+	}
+
+	var anniversaries []models.WorkAnniversary
+
+	// The next anniversary is join date plus one more year than completed as of the day before,
+	// so an anniversary on the start date is included
+	anniversaryQuery := `
+        SELECT * FROM (
+            SELECT u.u_id AS user_id, u.u_employee_id AS employee_id, u.u_name AS name,
+                   COALESCE(d.div_name, '') AS division, u.u_join_date AS join_date,
+                   CAST(u.u_join_date + make_interval(years => a.years) AS date) AS "date", a.years
+            FROM "user".users u
+            LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+            CROSS JOIN LATERAL (
+                SELECT CAST(EXTRACT(YEAR FROM age(CAST(@from AS date) - 1, u.u_join_date)) AS integer) + 1 AS years
+            ) a
+            WHERE u.u_is_active = true AND u.u_join_date < CAST(@from AS date)` + divisionFilter + `
+        ) anniversaries
+        WHERE "date" < CAST(@to AS date)
+        ORDER BY "date", name
+    `
+
+	if err := s.db.Raw(anniversaryQuery, params).Scan(&anniversaries).Error; err != nil {
+		return nil, err
+	}
+
+	return anniversaries, nil
+}
+
+// maxCalendarDays limits the number of days a calendar may span
+const maxCalendarDays = 366
+
+// GetCalendar lists the birthdays, work anniversaries and probation ends of active users between
+// from and to inclusive, optionally limited to one division or to the direct reports of a manager
+func (s *DashboardService) GetCalendar(from, to time.Time, divisionID, managerID *uint) ([]models.CalendarEvent, error) {
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	if to.Sub(from) > maxCalendarDays*24*time.Hour {
+		return nil, fmt.Errorf("the calendar may span at most %d days", maxCalendarDays)
+	}
+
+	params := map[string]interface{}{
+		"from":      from,
+		"to":        to,
+		"probation": s.calendarConfig.ProbationMonths,
+	}
+	filter := ""
+	if divisionID != nil {
+		filter += " AND u.u_division_id = @division"
+		params["division"] = *divisionID
+	}
+	if managerID != nil {
+		filter += " AND u.u_manager_id = @manager"
+		params["manager"] = *managerID
+	}
+
+	// Anniversary years come from configuration; an empty list matches nothing
+	anniversaryFilter := " AND false"
+	if len(s.calendarConfig.AnniversaryYears) > 0 {
+		anniversaryFilter = " AND years.y - CAST(EXTRACT(YEAR FROM u.u_join_date) AS integer) IN @anniversaries"
+		params["anniversaries"] = s.calendarConfig.AnniversaryYears
+	}
+	probationFilter := " AND false"
+	if s.calendarConfig.ProbationMonths > 0 {
+		probationFilter = ""
+	}
+
+	var events []models.CalendarEvent
+
+	// Yearly dates are moved into every year of the range; Feb 29 falls on Feb 28 in other years
+	calendarQuery := `
+        WITH years AS (
+            SELECT generate_series(CAST(EXTRACT(YEAR FROM CAST(@from AS date)) AS integer),
+                                   CAST(EXTRACT(YEAR FROM CAST(@to AS date)) AS integer)) AS y
+        )
+        SELECT * FROM (
+            SELECT CAST(CAST(u.u_birthdate AS date) + make_interval(years => years.y - CAST(EXTRACT(YEAR FROM u.u_birthdate) AS integer)) AS date) AS "date",
+                   'birthday' AS type, 0 AS years,
+                   u.u_id AS user_id, u.u_employee_id AS employee_id, u.u_name AS name, COALESCE(d.div_name, '') AS division
+            FROM "user".users u
+            CROSS JOIN years
+            LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+            WHERE u.u_is_active = true AND u.u_birthdate IS NOT NULL` + filter + `
+            UNION ALL
+            SELECT CAST(CAST(u.u_join_date AS date) + make_interval(years => years.y - CAST(EXTRACT(YEAR FROM u.u_join_date) AS integer)) AS date),
+                   'work_anniversary', years.y - CAST(EXTRACT(YEAR FROM u.u_join_date) AS integer),
+                   u.u_id, u.u_employee_id, u.u_name, COALESCE(d.div_name, '')
+            FROM "user".users u
+            CROSS JOIN years
+            LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+            WHERE u.u_is_active = true` + anniversaryFilter + filter + `
+            UNION ALL
+            SELECT CAST(CAST(u.u_join_date AS date) + make_interval(months => @probation) AS date),
+                   'probation_end', 0,
+                   u.u_id, u.u_employee_id, u.u_name, COALESCE(d.div_name, '')
+            FROM "user".users u
+            LEFT JOIN "user".divisions d ON u.u_division_id = d.div_id
+            WHERE u.u_is_active = true` + probationFilter + filter + `
+        ) events
+        WHERE "date" BETWEEN CAST(@from AS date) AND CAST(@to AS date)
+        ORDER BY "date", type, name
+    `
+
+	if err := s.db.Raw(calendarQuery, params).Scan(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
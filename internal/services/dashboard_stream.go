@@ -0,0 +1,324 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// dashboardChannel is the Postgres NOTIFY channel shared by all API instances
+	dashboardChannel = "dashboard_changes"
+	// dashboardHistorySize is the number of past versions kept for Last-Event-ID resume
+	dashboardHistorySize = 64
+	// dashboardDebounce collects notifications arriving together into one refresh
+	dashboardDebounce = 250 * time.Millisecond
+	// dashboardReconnectDelay is the wait before reconnecting a dropped listener
+	dashboardReconnectDelay = 5 * time.Second
+	// dashboardSubscriberBuffer is the number of messages a slow client may fall behind
+	dashboardSubscriberBuffer = 16
+	// dashboardGeneratedAtField is the statistics field holding when they were computed
+	dashboardGeneratedAtField = "generated_at"
+)
+
+// Dashboard stream event names
+const (
+	DashboardEventSnapshot = "snapshot" // full statistics
+	DashboardEventDelta    = "delta"    // only the statistics fields that changed
+)
+
+// DashboardMessage is one server-sent event of the dashboard stream. Its ID is the ID of the
+// latest outbox event reflected in the statistics, so it is the same on every API instance.
+type DashboardMessage struct {
+	ID    uint
+	Event string
+	Data  []byte
+}
+
+// dashboardVersion is the statistics as of an outbox event ID
+type dashboardVersion struct {
+	id     uint
+	fields map[string]json.RawMessage
+}
+
+// DashboardStream pushes statistics changes to connected dashboard clients. Changes are
+// announced with Postgres NOTIFY from the event bus, so every instance refreshes its clients
+// no matter which instance made the change.
+type DashboardStream struct {
+	dashboardService *DashboardService
+	outboxRepository *repository.OutboxRepository
+	dsn              string
+	heartbeat        time.Duration
+
+	mu          sync.Mutex
+	history     []dashboardVersion // oldest first, the last entry is current
+	subscribers map[chan DashboardMessage]struct{}
+}
+
+// NewDashboardStream creates a new dashboard stream
+func NewDashboardStream(
+	dashboardService *DashboardService,
+	outboxRepository *repository.OutboxRepository,
+	dsn string,
+	heartbeat time.Duration,
+) *DashboardStream {
+	return &DashboardStream{
+		dashboardService: dashboardService,
+		outboxRepository: outboxRepository,
+		dsn:              dsn,
+		heartbeat:        heartbeat,
+		subscribers:      make(map[chan DashboardMessage]struct{}),
+	}
+}
+
+// Heartbeat returns how often idle connections receive a keep-alive comment
+func (s *DashboardStream) Heartbeat() time.Duration {
+	return s.heartbeat
+}
+
+// HandleEvent announces changes that affect the statistics. It is subscribed to the event bus;
+// NOTIFY is sent when the dispatcher's transaction commits.
+func (s *DashboardStream) HandleEvent(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error {
+	switch record.AggregateType {
+	case "user", "division", "position", "checklist":
+		return tx.Exec("SELECT pg_notify(?, ?)", dashboardChannel, strconv.FormatUint(uint64(record.ID), 10)).Error
+	}
+	return nil
+}
+
+// Run listens for change notifications until the context is cancelled, reconnecting when the
+// connection drops
+func (s *DashboardStream) Run(ctx context.Context) {
+	for {
+		if err := s.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Dashboard stream listener failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dashboardReconnectDelay):
+		}
+	}
+}
+
+// listen holds a dedicated connection subscribed to the change channel
+func (s *DashboardStream) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, s.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+dashboardChannel); err != nil {
+		return err
+	}
+
+	// Catch up on changes missed while disconnected
+	if err := s.refresh(0); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		latest := parseNotificationID(notification.Payload)
+
+		// Fold notifications sent by the same dispatch into one refresh
+		for {
+			waitCtx, cancel := context.WithTimeout(ctx, dashboardDebounce)
+			notification, err = conn.WaitForNotification(waitCtx)
+			cancel()
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				break
+			}
+			if id := parseNotificationID(notification.Payload); id > latest {
+				latest = id
+			}
+		}
+
+		if err := s.refresh(latest); err != nil {
+			log.Printf("Dashboard stream refresh failed: %v", err)
+		}
+	}
+}
+
+// Subscribe registers a client. It returns the messages that bring the client up to date,
+// followed by live changes on the channel, and a function to unregister. A client resuming
+// from a version still in history receives only what changed since; any other client receives
+// a full snapshot.
+func (s *DashboardStream) Subscribe(lastEventID string) ([]DashboardMessage, <-chan DashboardMessage, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.history) == 0 {
+		if err := s.loadLocked(0); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	current := s.history[len(s.history)-1]
+
+	var initial []DashboardMessage
+	resumed := false
+	if id, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+		for _, version := range s.history {
+			if version.id != uint(id) {
+				continue
+			}
+			resumed = true
+			if delta := diffFields(version.fields, current.fields); delta != nil {
+				initial = append(initial, DashboardMessage{ID: current.id, Event: DashboardEventDelta, Data: delta})
+			}
+			break
+		}
+	}
+	if !resumed {
+		data, err := json.Marshal(current.fields)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		initial = append(initial, DashboardMessage{ID: current.id, Event: DashboardEventSnapshot, Data: data})
+	}
+
+	ch := make(chan DashboardMessage, dashboardSubscriberBuffer)
+	s.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return initial, ch, unsubscribe, nil
+}
+
+// refresh reloads the statistics and sends the changed fields to every client
+func (s *DashboardStream) refresh(latest uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var previous map[string]json.RawMessage
+	if len(s.history) > 0 {
+		previous = s.history[len(s.history)-1].fields
+	}
+
+	if err := s.loadLocked(latest); err != nil {
+		return err
+	}
+	current := s.history[len(s.history)-1]
+
+	if previous == nil {
+		return nil
+	}
+	delta := diffFields(previous, current.fields)
+	if delta == nil {
+		return nil
+	}
+
+	message := DashboardMessage{ID: current.id, Event: DashboardEventDelta, Data: delta}
+	for ch := range s.subscribers {
+		select {
+		case ch <- message:
+		default:
+			// The client fell too far behind; closing makes it reconnect and resume
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return nil
+}
+
+// loadLocked appends the current statistics to history as of the given outbox event ID, or as
+// of the latest outbox event when none is given. The caller must hold s.mu.
+func (s *DashboardStream) loadLocked(latest uint) error {
+	if latest == 0 {
+		id, err := s.outboxRepository.LatestID()
+		if err != nil {
+			return err
+		}
+		latest = id
+	}
+	if n := len(s.history); n > 0 && s.history[n-1].id > latest {
+		latest = s.history[n-1].id
+	}
+
+	stats, err := s.dashboardService.RefreshStatistics()
+	if err != nil {
+		return err
+	}
+	fields, err := statisticsFields(stats)
+	if err != nil {
+		return err
+	}
+
+	if n := len(s.history); n > 0 && s.history[n-1].id == latest {
+		s.history[n-1].fields = fields
+	} else {
+		s.history = append(s.history, dashboardVersion{id: latest, fields: fields})
+	}
+	if len(s.history) > dashboardHistorySize {
+		s.history = s.history[len(s.history)-dashboardHistorySize:]
+	}
+
+	return nil
+}
+
+// statisticsFields splits statistics into their JSON fields for comparison
+func statisticsFields(stats *models.Statistics) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffFields returns a JSON object of the fields whose value changed, or nil when none did.
+// generated_at changes on every load, so it does not count as a change; it is sent along with
+// the fields that did change.
+func diffFields(previous, current map[string]json.RawMessage) []byte {
+	changed := make(map[string]json.RawMessage)
+	for name, value := range current {
+		if name != dashboardGeneratedAtField && !bytes.Equal(previous[name], value) {
+			changed[name] = value
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	if generatedAt, ok := current[dashboardGeneratedAtField]; ok {
+		changed[dashboardGeneratedAtField] = generatedAt
+	}
+	data, _ := json.Marshal(changed)
+	return data
+}
+
+// parseNotificationID reads the outbox event ID sent as a notification payload
+func parseNotificationID(payload string) uint {
+	id, _ := strconv.ParseUint(payload, 10, 64)
+	return uint(id)
+}
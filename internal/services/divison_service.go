@@ -11,16 +11,29 @@ import (
 
 // DivisionService handles division-related operations
 type DivisionService struct {
-	divisionRepository *repository.DivisionRepository
+	divisionRepository    *repository.DivisionRepository
+	outboxRepository      *repository.OutboxRepository
+	customFieldRepository *repository.CustomFieldRepository
 }
 
 // NewDivisionService creates a new division service
-func NewDivisionService(divisionRepository *repository.DivisionRepository) *DivisionService {
+func NewDivisionService(
+	divisionRepository *repository.DivisionRepository,
+	outboxRepository *repository.OutboxRepository,
+	customFieldRepository *repository.CustomFieldRepository,
+) *DivisionService {
 	return &DivisionService{
-		divisionRepository: divisionRepository,
+		divisionRepository:    divisionRepository,
+		outboxRepository:      outboxRepository,
+		customFieldRepository: customFieldRepository,
 	}
 }
 
+// WithTx returns a copy of the service whose repositories run inside the given transaction
+func (s *DivisionService) WithTx(tx *gorm.DB) *DivisionService {
+	return NewDivisionService(repository.NewDivisionRepository(tx), repository.NewOutboxRepository(tx), repository.NewCustomFieldRepository(tx))
+}
+
 // Get gets a division by ID
 func (s *DivisionService) Get(id uint) (*models.Division, error) {
 	return s.divisionRepository.FindByID(id)
@@ -36,21 +49,41 @@ func (s *DivisionService) Create(request *models.DivisionRequest, createdBy stri
 		return nil, err
 	}
 	
+	// Validate custom fields, including required ones that were left out
+	customFields, err := s.mergeCustomFields(nil, request.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Create division object
 	division := &models.Division{
-		Code:     request.Code,
-		Name:     request.Name,
-		IsActive: true, // Default to active
+		Code:         request.Code,
+		Name:         request.Name,
+		CustomFields: customFields,
+		IsActive:     true, // Default to active
 	}
 	
-	// Create division in database
-	err = s.divisionRepository.Create(division, createdBy)
+	// Create division in database together with its outbox event
+	var divisionResult *models.Division
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.divisionRepository.Create(division, createdBy); err != nil {
+			return err
+		}
+		
+		// Get created division
+		divisionResult, err = txService.divisionRepository.FindByID(division.ID)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.DivisionCreated{Division: divisionResult})
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get created division
-	return s.divisionRepository.FindByID(division.ID)
+	return divisionResult, nil
 }
 
 // Update updates a division
@@ -60,6 +93,18 @@ func (s *DivisionService) Update(id uint, request *models.DivisionRequest, updat
 	if err != nil {
 		return nil, err
 	}
+	if request.Version != nil && *request.Version != division.Version {
+		return nil, ErrVersionConflict
+	}
+	
+	// Remember the current code and name for DivisionRenamed
+	renamed := &models.DivisionRenamed{
+		DivisionID: division.ID,
+		OldCode:    division.Code,
+		NewCode:    request.Code,
+		OldName:    division.Name,
+		NewName:    request.Name,
+	}
 	
 	// Check if code is changed and already exists
 	if request.Code != division.Code {
@@ -75,20 +120,59 @@ func (s *DivisionService) Update(id uint, request *models.DivisionRequest, updat
 	
 	// Update division fields
 	division.Name = request.Name
+	if request.CustomFields != nil {
+		customFields, err := s.mergeCustomFields(division.CustomFields, request.CustomFields, false)
+		if err != nil {
+			return nil, err
+		}
+		division.CustomFields = customFields
+	}
 	
-	// Update division in database
-	err = s.divisionRepository.Update(division, updatedBy)
+	// Update division in database together with its outbox events
+	var divisionResult *models.Division
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.divisionRepository.Update(division, updatedBy); err != nil {
+			return err
+		}
+		
+		// Get updated division
+		divisionResult, err = txService.divisionRepository.FindByID(division.ID)
+		if err != nil {
+			return err
+		}
+		
+		if err := appendEvent(txService.outboxRepository, &models.DivisionUpdated{Division: divisionResult}); err != nil {
+			return err
+		}
+		if renamed.OldCode != renamed.NewCode || renamed.OldName != renamed.NewName {
+			return appendEvent(txService.outboxRepository, renamed)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get updated division
-	return s.divisionRepository.FindByID(division.ID)
+	return divisionResult, nil
 }
 
 // Delete deletes a division
 func (s *DivisionService) Delete(id uint) error {
-	return s.divisionRepository.Delete(id)
+	// Get division for the event payload
+	division, err := s.divisionRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.divisionRepository.Delete(id); err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.DivisionDeleted{Division: division})
+	})
 }
 
 // List lists all divisions with pagination
@@ -109,4 +193,13 @@ func (s *DivisionService) List(page, pageSize int, search string) (*models.Pagin
 // ListAll lists all active divisions without pagination
 func (s *DivisionService) ListAll() ([]models.Division, error) {
 	return s.divisionRepository.ListAll()
-}
\ No newline at end of file
+}
+
+// mergeCustomFields validates custom field input against the division field definitions
+func (s *DivisionService) mergeCustomFields(current models.CustomFields, input map[string]interface{}, create bool) (models.CustomFields, error) {
+	definitions, err := s.customFieldRepository.List(models.CustomFieldEntityDivision)
+	if err != nil {
+		return nil, err
+	}
+	return mergeCustomFields(definitions, current, input, create)
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// maxExpiringDays limits how far ahead the expiring documents report looks
+const maxExpiringDays = 366
+
+// Document errors
+var (
+	ErrDocumentTooLarge = errors.New("document file is too large")
+	ErrDocumentEmpty    = errors.New("document file is empty")
+	ErrDocumentCorrupt  = errors.New("stored document does not match its checksum")
+)
+
+// DocumentService handles documents attached to users
+type DocumentService struct {
+	documentRepository *repository.DocumentRepository
+	userRepository     *repository.UserRepository
+	blobStore          BlobStore
+	authConfig         *config.AuthConfig
+	documentConfig     *config.DocumentConfig
+}
+
+// NewDocumentService creates a new document service
+func NewDocumentService(
+	documentRepository *repository.DocumentRepository,
+	userRepository *repository.UserRepository,
+	blobStore BlobStore,
+	authConfig *config.AuthConfig,
+	documentConfig *config.DocumentConfig,
+) *DocumentService {
+	return &DocumentService{
+		documentRepository: documentRepository,
+		userRepository:     userRepository,
+		blobStore:          blobStore,
+		authConfig:         authConfig,
+		documentConfig:     documentConfig,
+	}
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *DocumentService) MaxSize() int {
+	return s.documentConfig.MaxSize
+}
+
+// List lists the documents of a user that the actor may read
+func (s *DocumentService) List(userID uint, actorID uint, actorRoles []string) ([]models.UserDocument, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.documentRepository.ListByUser(userID, s.readableLevels(user, actorID, actorRoles))
+}
+
+// Upload stores a file and attaches it to a user. Only admins and HR may upload, at a
+// confidentiality level they can read themselves.
+func (s *DocumentService) Upload(
+	ctx context.Context,
+	userID uint,
+	fileName string,
+	contentType string,
+	data []byte,
+	request *models.UserDocumentRequest,
+	actorID uint,
+	actorRoles []string,
+	createdBy string,
+) (*models.UserDocument, error) {
+	if !s.canManage(actorRoles) {
+		return nil, ErrForbidden
+	}
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	confidentiality := request.Confidentiality
+	if confidentiality == "" {
+		confidentiality = models.ConfidentialityInternal
+	}
+	if !containsString(s.readableLevels(user, actorID, actorRoles), confidentiality) {
+		return nil, ErrForbidden
+	}
+
+	if len(data) == 0 {
+		return nil, ErrDocumentEmpty
+	}
+	if len(data) > s.documentConfig.MaxSize {
+		return nil, ErrDocumentTooLarge
+	}
+
+	var expiresAt *time.Time
+	if request.ExpiresAt != "" {
+		t, err := time.Parse("2006-01-02", request.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_at format, use YYYY-MM-DD: %w", err)
+		}
+		expiresAt = &t
+	}
+
+	// Trust the declared type only when it is specific; downloads are always served as attachments
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("documents/%d/%s", userID, hex.EncodeToString(b))
+	if err := s.blobStore.Put(ctx, key, data, contentType); err != nil {
+		return nil, err
+	}
+
+	document := &models.UserDocument{
+		UserID:          userID,
+		Type:            request.Type,
+		Name:            filepath.Base(fileName),
+		ContentType:     contentType,
+		Size:            int64(len(data)),
+		Checksum:        sha256Hex(data),
+		BlobKey:         key,
+		Confidentiality: confidentiality,
+		ExpiresAt:       expiresAt,
+	}
+	if err := s.documentRepository.Create(document, createdBy); err != nil {
+		s.deleteBlob(key)
+		return nil, err
+	}
+	return document, nil
+}
+
+// Download returns a document and its file after checking it against the stored checksum.
+// Documents the actor may not read are reported as not found.
+func (s *DocumentService) Download(ctx context.Context, userID, id uint, actorID uint, actorRoles []string) (*models.UserDocument, []byte, error) {
+	document, err := s.findReadable(userID, id, actorID, actorRoles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := s.blobStore.Get(ctx, document.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sha256Hex(data) != document.Checksum {
+		return nil, nil, ErrDocumentCorrupt
+	}
+	return document, data, nil
+}
+
+// Delete removes a document and its file (admins and HR only)
+func (s *DocumentService) Delete(ctx context.Context, userID, id uint, actorID uint, actorRoles []string) error {
+	if !s.canManage(actorRoles) {
+		return ErrForbidden
+	}
+	document, err := s.findReadable(userID, id, actorID, actorRoles)
+	if err != nil {
+		return err
+	}
+
+	if err := s.documentRepository.Delete(document.ID); err != nil {
+		return err
+	}
+	s.deleteBlob(document.BlobKey)
+	return nil
+}
+
+// Expiring lists the documents the actor may read that expire within the next days days
+func (s *DocumentService) Expiring(days int, actorRoles []string) ([]models.ExpiringDocument, error) {
+	if days < 1 || days > maxExpiringDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxExpiringDays)
+	}
+	if !s.canManage(actorRoles) && !hasAnyRole(actorRoles, s.documentConfig.RestrictedRoles) {
+		return nil, ErrForbidden
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	documents, err := s.documentRepository.FindExpiring(today, today.AddDate(0, 0, days), s.readableLevels(nil, 0, actorRoles))
+	if err != nil {
+		return nil, err
+	}
+	for i := range documents {
+		if documents[i].ExpiresAt != nil {
+			documents[i].DaysLeft = int(documents[i].ExpiresAt.Sub(today).Hours() / 24)
+		}
+	}
+	return documents, nil
+}
+
+// HandleEvent removes the documents of deleted users. It is subscribed to the event bus, so
+// files are removed before the rows and a failure is retried with the event.
+func (s *DocumentService) HandleEvent(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error {
+	deleted, ok := event.(*models.UserDeleted)
+	if !ok {
+		return nil
+	}
+	documentRepository := repository.NewDocumentRepository(tx)
+
+	documents, err := documentRepository.ListAllByUser(deleted.UserID)
+	if err != nil {
+		return err
+	}
+	for i := range documents {
+		if err := s.blobStore.Delete(context.Background(), documents[i].BlobKey); err != nil {
+			return err
+		}
+	}
+	return documentRepository.DeleteByUser(deleted.UserID)
+}
+
+// findReadable finds a user's document, reporting it as not found when the actor may not read it
+func (s *DocumentService) findReadable(userID, id uint, actorID uint, actorRoles []string) (*models.UserDocument, error) {
+	user, err := s.userRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	document, err := s.documentRepository.FindByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(s.readableLevels(user, actorID, actorRoles), document.Confidentiality) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return document, nil
+}
+
+// readableLevels returns the confidentiality levels of a user's documents the actor may read.
+// Without a user only the levels that do not depend on the document's owner are returned.
+func (s *DocumentService) readableLevels(user *models.User, actorID uint, actorRoles []string) []string {
+	hr := s.canManage(actorRoles)
+	levels := []string{models.ConfidentialityPublic}
+	if hr || (user != nil && (user.ID == actorID || (user.ManagerID != nil && *user.ManagerID == actorID))) {
+		levels = append(levels, models.ConfidentialityInternal)
+	}
+	if hr {
+		levels = append(levels, models.ConfidentialityConfidential)
+	}
+	if hasAnyRole(actorRoles, s.documentConfig.RestrictedRoles) {
+		levels = append(levels, models.ConfidentialityRestricted)
+	}
+	return levels
+}
+
+// canManage reports whether the actor is an admin or HR
+func (s *DocumentService) canManage(actorRoles []string) bool {
+	return hasAnyRole(actorRoles, s.authConfig.AdminRoles) || hasAnyRole(actorRoles, s.documentConfig.HRRoles)
+}
+
+// deleteBlob removes a document file. Failures only leave an orphaned file behind, so they are
+// logged rather than returned.
+func (s *DocumentService) deleteBlob(key string) {
+	if err := s.blobStore.Delete(context.Background(), key); err != nil {
+		log.Printf("Failed to delete document %s: %v", key, err)
+	}
+}
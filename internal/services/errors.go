@@ -0,0 +1,16 @@
+package services
+
+import (
+	"errors"
+
+	"admin-dashboard/internal/repository"
+)
+
+// ErrForbidden is returned when the acting user is not allowed to perform an operation
+var ErrForbidden = errors.New("insufficient permissions")
+
+// ErrVersionConflict is returned when an update expects a version the record no longer has
+var ErrVersionConflict = repository.ErrVersionConflict
+
+// RoleConflictError is returned when a role assignment breaks a segregation-of-duties rule
+type RoleConflictError = repository.RoleConflictError
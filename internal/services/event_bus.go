@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"admin-dashboard/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EventHandler handles a domain event dispatched from the outbox. tx is the dispatcher's
+// transaction: writes made through it commit together with marking the event processed.
+// Events are delivered at least once, so handlers must tolerate seeing an event again.
+type EventHandler func(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error
+
+// eventSubscription is a handler registered on the bus
+type eventSubscription struct {
+	name       string
+	eventTypes []string
+	handler    EventHandler
+}
+
+// EventBus delivers dispatched domain events to in-process subscribers
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions []eventSubscription
+}
+
+// NewEventBus creates a new event bus
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers a handler for the given event types, or for every event when none are given
+func (b *EventBus) Subscribe(name string, handler EventHandler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, eventSubscription{
+		name:       name,
+		eventTypes: eventTypes,
+		handler:    handler,
+	})
+}
+
+// Publish runs the matching handlers in registration order and stops at the first error,
+// so the event is retried on the next dispatch
+func (b *EventBus) Publish(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, subscription := range b.subscriptions {
+		if !subscription.matches(record.EventType) {
+			continue
+		}
+		if err := subscription.handler(tx, record, event); err != nil {
+			return fmt.Errorf("%s: %w", subscription.name, err)
+		}
+	}
+
+	return nil
+}
+
+// matches reports whether the subscription wants the event type
+func (s *eventSubscription) matches(eventType string) bool {
+	if len(s.eventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/json"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+)
+
+// appendEvent writes a domain event to the outbox. Callers pass an outbox repository bound to
+// the transaction of the change so both commit or roll back together.
+func appendEvent(outbox *repository.OutboxRepository, event models.DomainEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return outbox.Append(&models.OutboxEvent{
+		EventType:     event.EventType(),
+		AggregateType: models.AggregateType(event.EventType()),
+		AggregateID:   event.AggregateID(),
+		Payload:       data,
+	})
+}
+
+// newRolesChanged compares a user's role names before and after a change and returns the
+// resulting event, or nil when the set of roles did not change
+func newRolesChanged(userID uint, before, after []string) *models.UserRolesChanged {
+	event := &models.UserRolesChanged{
+		UserID:  userID,
+		Added:   []string{},
+		Removed: []string{},
+		Roles:   after,
+	}
+
+	held := make(map[string]bool, len(before))
+	for _, name := range before {
+		held[name] = true
+	}
+	for _, name := range after {
+		if !held[name] {
+			event.Added = append(event.Added, name)
+		}
+		delete(held, name)
+	}
+	for _, name := range before {
+		if held[name] {
+			event.Removed = append(event.Removed, name)
+		}
+	}
+
+	if len(event.Added) == 0 && len(event.Removed) == 0 {
+		return nil
+	}
+	return event
+}
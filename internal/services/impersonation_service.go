@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+	"admin-dashboard/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonationService handles admin impersonation of users
+type ImpersonationService struct {
+	userRepository    *repository.UserRepository
+	roleRepository    *repository.RoleRepository
+	sessionRepository *repository.SessionRepository
+	jwtManager        *utils.JWTManager
+	config            *config.AuthConfig
+}
+
+// NewImpersonationService creates a new impersonation service
+func NewImpersonationService(
+	userRepository *repository.UserRepository,
+	roleRepository *repository.RoleRepository,
+	sessionRepository *repository.SessionRepository,
+	jwtManager *utils.JWTManager,
+	config *config.AuthConfig,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepository:    userRepository,
+		roleRepository:    roleRepository,
+		sessionRepository: sessionRepository,
+		jwtManager:        jwtManager,
+		config:            config,
+	}
+}
+
+// Impersonate issues a short-lived token that acts as the target user on behalf of the impersonator
+func (s *ImpersonationService) Impersonate(impersonatorID, targetID uint, ipAddress, userAgent string) (*models.ImpersonationResponse, error) {
+	if impersonatorID == targetID {
+		return nil, errors.New("you cannot impersonate yourself")
+	}
+
+	// Check the impersonator's permission
+	impersonator, err := s.userRepository.FindByID(impersonatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	impersonatorRoles, err := s.roleRepository.GetUserRoles(impersonator.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.canImpersonate(impersonatorRoles) {
+		return nil, fmt.Errorf("%w: impersonation is not allowed for your roles", ErrForbidden)
+	}
+
+	// Check the target
+	target, err := s.userRepository.FindByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !target.IsActive {
+		return nil, errors.New("cannot impersonate an inactive user")
+	}
+
+	targetRoles, err := s.roleRepository.GetUserRoles(target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxRoleLevel(targetRoles) >= maxRoleLevel(impersonatorRoles) {
+		return nil, fmt.Errorf("%w: cannot impersonate a user with an equal or higher role level", ErrForbidden)
+	}
+
+	// Open a session owned by the target but marked with the impersonator
+	now := time.Now()
+	lifetime := time.Duration(s.config.ImpersonationTTL) * time.Minute
+	session := &models.UserSession{
+		ID:             uuid.New(),
+		UserID:         target.ID,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(lifetime),
+		ImpersonatorID: &impersonator.ID,
+	}
+	if err := s.sessionRepository.Create(session); err != nil {
+		return nil, err
+	}
+
+	targetRoleNames := roleNames(targetRoles)
+	token, err := s.jwtManager.GenerateImpersonationToken(
+		target.ID, target.UID, target.EmployeeID, target.Email, targetRoleNames, session.ID.String(),
+		impersonator.ID, impersonator.EmployeeID, lifetime,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ImpersonationResponse{
+		Token:        token,
+		ExpiresAt:    session.ExpiresAt,
+		User:         *newUserResponse(target, targetRoleNames),
+		Impersonator: *newUserResponse(impersonator, roleNames(impersonatorRoles)),
+	}, nil
+}
+
+// canImpersonate reports whether the given roles grant the impersonation permission
+func (s *ImpersonationService) canImpersonate(roles []models.Role) bool {
+	for _, role := range roles {
+		for _, allowed := range s.config.ImpersonationRoles {
+			if role.Name == allowed {
+				return true
+			}
+		}
+	}
+
+	return s.config.ImpersonationMinLevel > 0 && maxRoleLevel(roles) >= s.config.ImpersonationMinLevel
+}
+
+// maxRoleLevel returns the highest level among the roles, or -1 when there are none
+func maxRoleLevel(roles []models.Role) int {
+	level := -1
+	for _, role := range roles {
+		if role.Level > level {
+			level = role.Level
+		}
+	}
+	return level
+}
+
+// roleNames converts roles to a string array
+func roleNames(roles []models.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	return names
+}
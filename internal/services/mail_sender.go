@@ -0,0 +1,134 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"admin-dashboard/internal/config"
+)
+
+// MailAttachment represents a file attached to a mail
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// MailMessage represents an outgoing mail
+type MailMessage struct {
+	To          []string
+	Subject     string
+	HTML        string
+	Attachments []MailAttachment
+}
+
+// MailSender sends mails
+type MailSender interface {
+	Send(message *MailMessage) error
+}
+
+// NewMailSender returns an SMTP sender, or a sender that only logs mails when no SMTP host is configured
+func NewMailSender(cfg *config.MailConfig) MailSender {
+	if cfg.SMTPHost == "" {
+		return &LogMailSender{}
+	}
+	return &SMTPMailSender{config: cfg}
+}
+
+// SMTPMailSender sends mails through an SMTP server
+type SMTPMailSender struct {
+	config *config.MailConfig
+}
+
+// Send sends a mail as a multipart MIME message
+func (s *SMTPMailSender) Send(message *MailMessage) error {
+	var auth smtp.Auth
+	if s.config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", s.config.SMTPUsername, s.config.SMTPPassword, s.config.SMTPHost)
+	}
+
+	body, err := buildMIMEMessage(s.config.From, message)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.SMTPHost, s.config.SMTPPort)
+	return smtp.SendMail(addr, auth, s.config.From, message.To, body)
+}
+
+// LogMailSender logs mails instead of sending them, for development
+type LogMailSender struct{}
+
+// Send logs the mail's recipients, subject and attachments
+func (s *LogMailSender) Send(message *MailMessage) error {
+	names := make([]string, len(message.Attachments))
+	for i, attachment := range message.Attachments {
+		names[i] = attachment.Filename
+	}
+	log.Printf("Mail not sent (no SMTP host configured): to=%s subject=%q attachments=%s",
+		strings.Join(message.To, ","), message.Subject, strings.Join(names, ","))
+	return nil
+}
+
+// buildMIMEMessage renders a mail with an HTML body and base64 attachments
+func buildMIMEMessage(from string, message *MailMessage) ([]byte, error) {
+	boundary, err := mimeBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	buf.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64Lines(&buf, []byte(message.HTML))
+
+	for _, attachment := range message.Attachments {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s\r\n", attachment.ContentType)
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&buf, "Content-Disposition: %s\r\n\r\n",
+			mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		writeBase64Lines(&buf, attachment.Data)
+	}
+
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes data base64 encoded in lines of 76 characters
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+}
+
+// mimeBoundary generates a random multipart boundary
+func mimeBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "boundary-" + hex.EncodeToString(b), nil
+}
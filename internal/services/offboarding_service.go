@@ -0,0 +1,266 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// offboardingBatchSize limits how many due offboardings one scheduler run carries out
+const offboardingBatchSize = 50
+
+// offboardingActor is recorded as updated_by and revoked_by for scheduled offboardings
+const offboardingActor = "offboarding"
+
+// ErrOffboardingScheduled is returned when a user already has a scheduled offboarding
+var ErrOffboardingScheduled = errors.New("an offboarding is already scheduled for this user")
+
+// OffboardingService handles the offboarding of leavers
+type OffboardingService struct {
+	offboardingRepository *repository.OffboardingRepository
+	userRepository        *repository.UserRepository
+	userService           *UserService
+	outboxRepository      *repository.OutboxRepository
+}
+
+// NewOffboardingService creates a new offboarding service
+func NewOffboardingService(
+	offboardingRepository *repository.OffboardingRepository,
+	userRepository *repository.UserRepository,
+	userService *UserService,
+	outboxRepository *repository.OutboxRepository,
+) *OffboardingService {
+	return &OffboardingService{
+		offboardingRepository: offboardingRepository,
+		userRepository:        userRepository,
+		userService:           userService,
+		outboxRepository:      outboxRepository,
+	}
+}
+
+// Offboard offboards a user now, or schedules the offboarding when its effective date is in
+// the future. A past effective date is kept as the leaver's deactivation date, while roles and
+// sessions are revoked right away.
+func (s *OffboardingService) Offboard(userID uint, request *models.OffboardRequest, createdBy string) (*models.Offboarding, error) {
+	if _, err := s.userRepository.FindByID(userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.offboardingRepository.FindScheduledByUser(userID); err == nil {
+		return nil, ErrOffboardingScheduled
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if request.SuccessorID != nil {
+		if err := validateSuccessor(s.userRepository, userID, *request.SuccessorID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	offboarding := &models.Offboarding{
+		UserID:      userID,
+		EffectiveAt: now,
+		Reason:      request.Reason,
+		SuccessorID: request.SuccessorID,
+		Status:      models.OffboardingScheduled,
+	}
+	if request.EffectiveDate != "" {
+		effectiveAt, err := parseTimestamp(request.EffectiveDate)
+		if err != nil {
+			return nil, errors.New("invalid effective date, use YYYY-MM-DD or RFC 3339")
+		}
+		offboarding.EffectiveAt = effectiveAt
+		if effectiveAt.After(now) {
+			if err := s.offboardingRepository.Create(offboarding, createdBy); err != nil {
+				return nil, err
+			}
+			return offboarding, nil
+		}
+	}
+
+	err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		if err := s.offboard(tx, offboarding, createdBy); err != nil {
+			return err
+		}
+		if err := repository.NewOffboardingRepository(tx).Create(offboarding, createdBy); err != nil {
+			return err
+		}
+		return appendEvent(repository.NewOutboxRepository(tx), &models.UserOffboarded{Offboarding: offboarding})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offboarding, nil
+}
+
+// List lists offboardings, optionally filtered by user and status
+func (s *OffboardingService) List(userID uint, status string) ([]models.Offboarding, error) {
+	return s.offboardingRepository.List(userID, status)
+}
+
+// Get gets an offboarding by ID
+func (s *OffboardingService) Get(id uint) (*models.Offboarding, error) {
+	return s.offboardingRepository.FindByID(id)
+}
+
+// Cancel cancels a scheduled offboarding
+func (s *OffboardingService) Cancel(id uint, cancelledBy string) (*models.Offboarding, error) {
+	offboarding, err := s.offboardingRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if offboarding.Status != models.OffboardingScheduled {
+		return nil, errors.New("only scheduled offboardings can be cancelled")
+	}
+
+	if err := s.offboardingRepository.Cancel(id, cancelledBy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("only scheduled offboardings can be cancelled")
+		}
+		return nil, err
+	}
+	return s.offboardingRepository.FindByID(id)
+}
+
+// RunDue carries out the scheduled offboardings whose effective date has come. A failed
+// offboarding is marked as failed and not retried.
+func (s *OffboardingService) RunDue(now time.Time) error {
+	offboardings, err := s.offboardingRepository.FindDue(now, offboardingBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range offboardings {
+		offboarding := &offboardings[i]
+
+		err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+			if err := s.offboard(tx, offboarding, offboardingActor); err != nil {
+				return err
+			}
+			if err := repository.NewOffboardingRepository(tx).Complete(offboarding); err != nil {
+				return err
+			}
+			return appendEvent(repository.NewOutboxRepository(tx), &models.UserOffboarded{Offboarding: offboarding})
+		})
+		if err != nil {
+			log.Printf("Offboarding %d of user %d failed: %v", offboarding.ID, offboarding.UserID, err)
+			offboarding.Status = models.OffboardingFailed
+			offboarding.LastError = err.Error()
+			// Not found when it was cancelled while it ran
+			if err := s.offboardingRepository.Complete(offboarding); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// offboard reassigns a leaver's direct reports, revokes their roles and sessions and deactivates
+// them inside tx, emitting the events of each change, and fills in the outcome on the offboarding.
+// The caller saves the offboarding and emits UserOffboarded.
+func (s *OffboardingService) offboard(tx *gorm.DB, offboarding *models.Offboarding, actor string) error {
+	txService := s.userService.WithTx(tx)
+	user, err := txService.userRepository.FindByID(offboarding.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Direct reports move to the successor, or to the leaver's manager
+	newManagerID := offboarding.SuccessorID
+	var movedIDs []uint
+	if newManagerID == nil {
+		newManagerID = user.ManagerID
+	} else {
+		if err := validateSuccessor(txService.userRepository, user.ID, *newManagerID); err != nil {
+			return err
+		}
+		// A successor who reported to the leaver now reports to the leaver's manager
+		successor, err := txService.userRepository.FindByID(*newManagerID)
+		if err != nil {
+			return err
+		}
+		if successor.ManagerID != nil && *successor.ManagerID == user.ID {
+			successor.ManagerID = user.ManagerID
+			if err := txService.userRepository.Update(successor, nil, actor); err != nil {
+				return err
+			}
+			movedIDs = append(movedIDs, successor.ID)
+		}
+	}
+	reportIDs, err := txService.userRepository.ReassignReports(user.ID, newManagerID, actor)
+	if err != nil {
+		return err
+	}
+	movedIDs = append(movedIDs, reportIDs...)
+	for _, movedID := range movedIDs {
+		response, err := txService.Get(movedID)
+		if err != nil {
+			return err
+		}
+		if err := appendEvent(txService.outboxRepository, &models.UserUpdated{User: response}); err != nil {
+			return err
+		}
+	}
+	offboarding.ReassignedReports = len(movedIDs)
+
+	// Revoke every role and deactivate the leaver
+	rolesBefore, err := txService.roleNames(user.ID)
+	if err != nil {
+		return err
+	}
+	wasActive := user.IsActive
+	user.IsActive = false
+	user.DeactivatedAt = &offboarding.EffectiveAt
+	if err := txService.userRepository.Update(user, []uint{}, actor); err != nil {
+		return err
+	}
+	response, err := txService.Get(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := appendEvent(txService.outboxRepository, &models.UserUpdated{User: response}); err != nil {
+		return err
+	}
+	if wasActive {
+		if err := appendEvent(txService.outboxRepository, &models.UserDeactivated{User: response}); err != nil {
+			return err
+		}
+	}
+	if event := newRolesChanged(user.ID, rolesBefore, response.Roles); event != nil {
+		if err := appendEvent(txService.outboxRepository, event); err != nil {
+			return err
+		}
+	}
+
+	// End every session so existing tokens stop working
+	if err := repository.NewSessionRepository(tx).RevokeAllByUser(user.ID, actor); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	offboarding.Status = models.OffboardingCompleted
+	offboarding.RevokedRoles = rolesBefore
+	offboarding.CompletedAt = &now
+	return nil
+}
+
+// validateSuccessor checks that a successor is an active user other than the leaver
+func validateSuccessor(userRepository *repository.UserRepository, userID, successorID uint) error {
+	if successorID == userID {
+		return errors.New("a user cannot be their own successor")
+	}
+	successor, err := userRepository.FindByID(successorID)
+	if err != nil {
+		return errors.New("successor not found")
+	}
+	if !successor.IsActive {
+		return errors.New("successor is not active")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// outboxBatchSize is the number of outbox events handled per transaction
+const outboxBatchSize = 100
+
+// OutboxDispatcher periodically publishes committed outbox events to the event bus
+type OutboxDispatcher struct {
+	outboxRepository *repository.OutboxRepository
+	eventBus         *EventBus
+	interval         time.Duration
+}
+
+// NewOutboxDispatcher creates a new outbox dispatcher
+func NewOutboxDispatcher(
+	outboxRepository *repository.OutboxRepository,
+	eventBus *EventBus,
+	interval time.Duration,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepository: outboxRepository,
+		eventBus:         eventBus,
+		interval:         interval,
+	}
+}
+
+// Run dispatches pending events every interval until the context is cancelled
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Dispatch(); err != nil {
+			log.Printf("Outbox dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch drains the outbox batch by batch. A failed batch stays unprocessed and is retried
+// on the next run.
+func (d *OutboxDispatcher) Dispatch() error {
+	for {
+		processed, err := d.outboxRepository.ProcessBatch(outboxBatchSize, d.publish)
+		if err != nil {
+			return err
+		}
+		if processed < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+// publish decodes each event and hands it to the bus subscribers. Events that cannot be decoded
+// are logged and skipped so they do not hold up the rest of the outbox.
+func (d *OutboxDispatcher) publish(tx *gorm.DB, records []models.OutboxEvent) error {
+	for i := range records {
+		event, err := models.DecodeEvent(records[i].EventType, records[i].Payload)
+		if err != nil {
+			log.Printf("Skipping outbox event %d: %v", records[i].ID, err)
+			continue
+		}
+
+		if err := d.eventBus.Publish(tx, &records[i], event); err != nil {
+			return fmt.Errorf("outbox event %d: %w", records[i].ID, err)
+		}
+	}
+
+	return nil
+}
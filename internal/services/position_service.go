@@ -11,16 +11,29 @@ import (
 
 // PositionService handles position-related operations
 type PositionService struct {
-	positionRepository *repository.PositionRepository
+	positionRepository    *repository.PositionRepository
+	outboxRepository      *repository.OutboxRepository
+	customFieldRepository *repository.CustomFieldRepository
 }
 
 // NewPositionService creates a new position service
-func NewPositionService(positionRepository *repository.PositionRepository) *PositionService {
+func NewPositionService(
+	positionRepository *repository.PositionRepository,
+	outboxRepository *repository.OutboxRepository,
+	customFieldRepository *repository.CustomFieldRepository,
+) *PositionService {
 	return &PositionService{
-		positionRepository: positionRepository,
+		positionRepository:    positionRepository,
+		outboxRepository:      outboxRepository,
+		customFieldRepository: customFieldRepository,
 	}
 }
 
+// WithTx returns a copy of the service whose repositories run inside the given transaction
+func (s *PositionService) WithTx(tx *gorm.DB) *PositionService {
+	return NewPositionService(repository.NewPositionRepository(tx), repository.NewOutboxRepository(tx), repository.NewCustomFieldRepository(tx))
+}
+
 // Get gets a position by ID
 func (s *PositionService) Get(id uint) (*models.Position, error) {
 	return s.positionRepository.FindByID(id)
@@ -36,21 +49,41 @@ func (s *PositionService) Create(request *models.PositionRequest, createdBy stri
 		return nil, err
 	}
 	
+	// Validate custom fields, including required ones that were left out
+	customFields, err := s.mergeCustomFields(nil, request.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Create position object
 	position := &models.Position{
-		Code:     request.Code,
-		Name:     request.Name,
-		IsActive: true, // Default to active
+		Code:         request.Code,
+		Name:         request.Name,
+		CustomFields: customFields,
+		IsActive:     true, // Default to active
 	}
 	
-	// Create position in database
-	err = s.positionRepository.Create(position, createdBy)
+	// Create position in database together with its outbox event
+	var positionResult *models.Position
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.positionRepository.Create(position, createdBy); err != nil {
+			return err
+		}
+		
+		// Get created position
+		positionResult, err = txService.positionRepository.FindByID(position.ID)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.PositionCreated{Position: positionResult})
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get created position
-	return s.positionRepository.FindByID(position.ID)
+	return positionResult, nil
 }
 
 // Update updates a position
@@ -60,6 +93,9 @@ func (s *PositionService) Update(id uint, request *models.PositionRequest, updat
 	if err != nil {
 		return nil, err
 	}
+	if request.Version != nil && *request.Version != position.Version {
+		return nil, ErrVersionConflict
+	}
 	
 	// Check if code is changed and already exists
 	if request.Code != position.Code {
@@ -75,20 +111,53 @@ func (s *PositionService) Update(id uint, request *models.PositionRequest, updat
 	
 	// Update position fields
 	position.Name = request.Name
+	if request.CustomFields != nil {
+		customFields, err := s.mergeCustomFields(position.CustomFields, request.CustomFields, false)
+		if err != nil {
+			return nil, err
+		}
+		position.CustomFields = customFields
+	}
 	
-	// Update position in database
-	err = s.positionRepository.Update(position, updatedBy)
+	// Update position in database together with its outbox event
+	var positionResult *models.Position
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.positionRepository.Update(position, updatedBy); err != nil {
+			return err
+		}
+		
+		// Get updated position
+		positionResult, err = txService.positionRepository.FindByID(position.ID)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.PositionUpdated{Position: positionResult})
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get updated position
-	return s.positionRepository.FindByID(position.ID)
+	return positionResult, nil
 }
 
 // Delete deletes a position
 func (s *PositionService) Delete(id uint) error {
-	return s.positionRepository.Delete(id)
+	// Get position for the event payload
+	position, err := s.positionRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.positionRepository.Delete(id); err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.PositionDeleted{Position: position})
+	})
 }
 
 // List lists all positions with pagination
@@ -109,4 +178,13 @@ func (s *PositionService) List(page, pageSize int, search string) (*models.Pagin
 // ListAll lists all active positions without pagination
 func (s *PositionService) ListAll() ([]models.Position, error) {
 	return s.positionRepository.ListAll()
-}
\ No newline at end of file
+}
+
+// mergeCustomFields validates custom field input against the position field definitions
+func (s *PositionService) mergeCustomFields(current models.CustomFields, input map[string]interface{}, create bool) (models.CustomFields, error) {
+	definitions, err := s.customFieldRepository.List(models.CustomFieldEntityPosition)
+	if err != nil {
+		return nil, err
+	}
+	return mergeCustomFields(definitions, current, input, create)
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+	"admin-dashboard/internal/utils"
+)
+
+const (
+	// reportScheduleBatchSize is the number of due schedules run per poll
+	reportScheduleBatchSize = 20
+	// reportDefaultPeriod is the period covered by a schedule's first report
+	reportDefaultPeriod = 7 * 24 * time.Hour
+)
+
+// reportEmailTemplate renders the scheduled report email
+var reportEmailTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif">
+<h2>{{.Schedule.Name}}</h2>
+<p>{{.Scope}}, {{.From}} to {{.To}}</p>
+
+<h3>Headcount</h3>
+<p>{{.Headcount.StartHeadcount}} &rarr; {{.Headcount.Headcount}} ({{.NetChange}})</p>
+{{if .Headcount.Changes}}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Date</th><th>Change</th><th>Employee ID</th><th>Name</th><th>Division</th><th>Position</th></tr>
+{{range .Headcount.Changes}}<tr><td>{{.Date.Format "2006-01-02"}}</td><td>{{.Change}}</td><td>{{.EmployeeID}}</td><td>{{.Name}}</td><td>{{.Division}}</td><td>{{.Position}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No one joined or left.</p>
+{{end}}
+
+<h3>Upcoming work anniversaries (next {{.Schedule.AnniversaryDays}} days)</h3>
+{{if .Anniversaries}}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Date</th><th>Years</th><th>Employee ID</th><th>Name</th><th>Division</th></tr>
+{{range .Anniversaries}}<tr><td>{{.Date.Format "2006-01-02"}}</td><td>{{.Years}}</td><td>{{.EmployeeID}}</td><td>{{.Name}}</td><td>{{.Division}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No upcoming anniversaries.</p>
+{{end}}
+
+<p style="color: #888">The same data is attached as CSV.</p>
+</body>
+</html>
+`))
+
+// ReportScheduleService handles scheduled report emails
+type ReportScheduleService struct {
+	reportScheduleRepository *repository.ReportScheduleRepository
+	divisionRepository       *repository.DivisionRepository
+	userRepository           *repository.UserRepository
+	dashboardService         *DashboardService
+	mailSender               MailSender
+}
+
+// NewReportScheduleService creates a new report schedule service
+func NewReportScheduleService(
+	reportScheduleRepository *repository.ReportScheduleRepository,
+	divisionRepository *repository.DivisionRepository,
+	userRepository *repository.UserRepository,
+	dashboardService *DashboardService,
+	mailSender MailSender,
+) *ReportScheduleService {
+	return &ReportScheduleService{
+		reportScheduleRepository: reportScheduleRepository,
+		divisionRepository:       divisionRepository,
+		userRepository:           userRepository,
+		dashboardService:         dashboardService,
+		mailSender:               mailSender,
+	}
+}
+
+// ListSchedules lists all report schedules
+func (s *ReportScheduleService) ListSchedules() ([]models.ReportSchedule, error) {
+	return s.reportScheduleRepository.List()
+}
+
+// GetSchedule gets a report schedule by ID
+func (s *ReportScheduleService) GetSchedule(id uint) (*models.ReportSchedule, error) {
+	return s.reportScheduleRepository.FindByID(id)
+}
+
+// CreateSchedule creates a report schedule
+func (s *ReportScheduleService) CreateSchedule(request *models.ReportScheduleRequest, createdBy string) (*models.ReportSchedule, error) {
+	schedule := &models.ReportSchedule{IsActive: true}
+	if err := s.applyRequest(schedule, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportScheduleRepository.Create(schedule, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.reportScheduleRepository.FindByID(schedule.ID)
+}
+
+// UpdateSchedule updates a report schedule; the next run is recomputed from the new expression
+func (s *ReportScheduleService) UpdateSchedule(id uint, request *models.ReportScheduleRequest, updatedBy string) (*models.ReportSchedule, error) {
+	schedule, err := s.reportScheduleRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(schedule, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportScheduleRepository.Update(schedule, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return s.reportScheduleRepository.FindByID(schedule.ID)
+}
+
+// DeleteSchedule deletes a report schedule
+func (s *ReportScheduleService) DeleteSchedule(id uint) error {
+	if _, err := s.reportScheduleRepository.FindByID(id); err != nil {
+		return err
+	}
+
+	return s.reportScheduleRepository.Delete(id)
+}
+
+// RunSchedule sends a schedule's report now, without changing when it runs next
+func (s *ReportScheduleService) RunSchedule(id uint) (*models.ReportSchedule, error) {
+	schedule, err := s.reportScheduleRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.send(schedule, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportScheduleRepository.RecordRun(schedule.ID, now, schedule.NextRunAt, ""); err != nil {
+		return nil, err
+	}
+
+	return s.reportScheduleRepository.FindByID(schedule.ID)
+}
+
+// RunDue sends the reports of every schedule that is due. A failed report is recorded on the
+// schedule and not retried before its next run.
+func (s *ReportScheduleService) RunDue(now time.Time) error {
+	schedules, err := s.reportScheduleRepository.FindDue(now, reportScheduleBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		lastError := ""
+		if err := s.send(schedule, now); err != nil {
+			log.Printf("Report schedule %d failed: %v", schedule.ID, err)
+			lastError = err.Error()
+		}
+
+		nextRunAt, err := nextScheduleRun(schedule, now)
+		if err != nil {
+			lastError = err.Error()
+		}
+		if err := s.reportScheduleRepository.RecordRun(schedule.ID, now, nextRunAt, lastError); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send renders a schedule's report covering the time since its last run and mails it
+func (s *ReportScheduleService) send(schedule *models.ReportSchedule, now time.Time) error {
+	recipients := []string(schedule.Recipients)
+	if len(recipients) == 0 {
+		emails, err := s.userRepository.FindManagerEmails(schedule.DivisionID)
+		if err != nil {
+			return err
+		}
+		recipients = emails
+	}
+	if len(recipients) == 0 {
+		return errors.New("no recipients: the schedule has none and there are no active managers in scope")
+	}
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return err
+	}
+	now = now.In(location)
+
+	from := now.Add(-reportDefaultPeriod)
+	if schedule.LastRunAt != nil && schedule.LastRunAt.Before(now) {
+		from = schedule.LastRunAt.In(location)
+	}
+
+	headcount, err := s.dashboardService.GetHeadcountReport(from, now, schedule.DivisionID)
+	if err != nil {
+		return err
+	}
+	anniversaries, err := s.dashboardService.GetUpcomingAnniversaries(now, schedule.AnniversaryDays, schedule.DivisionID)
+	if err != nil {
+		return err
+	}
+
+	scope := "All divisions"
+	if schedule.Division != nil {
+		scope = schedule.Division.Name
+	}
+	netChange := headcount.Headcount - headcount.StartHeadcount
+	netChangeText := strconv.FormatInt(netChange, 10)
+	if netChange >= 0 {
+		netChangeText = "+" + netChangeText
+	}
+
+	var html bytes.Buffer
+	if err := reportEmailTemplate.Execute(&html, map[string]interface{}{
+		"Schedule":      schedule,
+		"Scope":         scope,
+		"From":          from.Format("2006-01-02 15:04"),
+		"To":            now.Format("2006-01-02 15:04 MST"),
+		"Headcount":     headcount,
+		"NetChange":     netChangeText,
+		"Anniversaries": anniversaries,
+	}); err != nil {
+		return err
+	}
+
+	date := now.Format("2006-01-02")
+	return s.mailSender.Send(&MailMessage{
+		To:      recipients,
+		Subject: fmt.Sprintf("%s: %s", schedule.Name, scope),
+		HTML:    html.String(),
+		Attachments: []MailAttachment{
+			{Filename: "headcount-changes-" + date + ".csv", ContentType: "text/csv", Data: headcountCSV(headcount)},
+			{Filename: "work-anniversaries-" + date + ".csv", ContentType: "text/csv", Data: anniversariesCSV(anniversaries)},
+		},
+	})
+}
+
+// applyRequest validates a schedule request and copies it onto the schedule
+func (s *ReportScheduleService) applyRequest(schedule *models.ReportSchedule, request *models.ReportScheduleRequest) error {
+	timezone := request.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone: %s", timezone)
+	}
+
+	if request.DivisionID != nil {
+		if _, err := s.divisionRepository.FindByID(*request.DivisionID); err != nil {
+			return errors.New("division not found")
+		}
+	}
+
+	schedule.Name = request.Name
+	schedule.Cron = request.Cron
+	schedule.Timezone = timezone
+	schedule.DivisionID = request.DivisionID
+	schedule.Recipients = request.Recipients
+	schedule.AnniversaryDays = request.AnniversaryDays
+	if schedule.AnniversaryDays == 0 {
+		schedule.AnniversaryDays = 30
+	}
+	if request.IsActive != nil {
+		schedule.IsActive = *request.IsActive
+	}
+
+	nextRunAt, err := nextScheduleRun(schedule, time.Now())
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = nextRunAt
+
+	return nil
+}
+
+// nextScheduleRun returns the first time after now matching the schedule's cron expression
+// in its timezone
+func nextScheduleRun(schedule *models.ReportSchedule, now time.Time) (*time.Time, error) {
+	cron, err := utils.ParseCron(schedule.Cron)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	next := cron.Next(now.In(location))
+	if next.IsZero() {
+		return nil, errors.New("cron expression never matches")
+	}
+	return &next, nil
+}
+
+// headcountCSV renders headcount changes as CSV
+func headcountCSV(report *models.HeadcountReport) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"date", "change", "employee_id", "name", "division", "position"})
+
+	for _, change := range report.Changes {
+		writer.Write([]string{
+			change.Date.Format("2006-01-02"), change.Change, change.EmployeeID,
+			change.Name, change.Division, change.Position,
+		})
+	}
+
+	writer.Flush()
+	return buf.Bytes()
+}
+
+// anniversariesCSV renders work anniversaries as CSV
+func anniversariesCSV(anniversaries []models.WorkAnniversary) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"date", "years", "employee_id", "name", "division", "join_date"})
+
+	for _, anniversary := range anniversaries {
+		writer.Write([]string{
+			anniversary.Date.Format("2006-01-02"), strconv.Itoa(anniversary.Years), anniversary.EmployeeID,
+			anniversary.Name, anniversary.Division, anniversary.JoinDate.Format("2006-01-02"),
+		})
+	}
+
+	writer.Flush()
+	return buf.Bytes()
+}
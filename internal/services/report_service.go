@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ReportService handles saved report definitions and dashboard widgets
+type ReportService struct {
+	reportRepository *repository.ReportRepository
+}
+
+// NewReportService creates a new report service
+func NewReportService(reportRepository *repository.ReportRepository) *ReportService {
+	return &ReportService{
+		reportRepository: reportRepository,
+	}
+}
+
+// Schema describes the entities, dimensions, filters and metrics reports may use
+func (s *ReportService) Schema() *models.ReportSchema {
+	return &models.ReportSchema{
+		Entities:     models.ReportEntities,
+		Dimensions:   models.ReportDimensions,
+		FilterFields: models.ReportFilterFields,
+		Operators:    models.ReportOperators,
+		Metrics:      models.ReportMetrics,
+	}
+}
+
+// List lists a user's saved reports
+func (s *ReportService) List(ownerID uint) ([]models.ReportDefinition, error) {
+	return s.reportRepository.ListByOwner(ownerID, false)
+}
+
+// Get gets one of a user's saved reports; reports of other users are not found
+func (s *ReportService) Get(id, ownerID uint) (*models.ReportDefinition, error) {
+	report, err := s.reportRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if report.OwnerID != ownerID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return report, nil
+}
+
+// Create saves a report for a user
+func (s *ReportService) Create(request *models.ReportDefinitionRequest, ownerID uint, createdBy string) (*models.ReportDefinition, error) {
+	report := &models.ReportDefinition{OwnerID: ownerID}
+	if err := applyReportRequest(report, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportRepository.Create(report, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepository.FindByID(report.ID)
+}
+
+// Update updates one of a user's saved reports
+func (s *ReportService) Update(id uint, request *models.ReportDefinitionRequest, ownerID uint, updatedBy string) (*models.ReportDefinition, error) {
+	report, err := s.Get(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyReportRequest(report, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportRepository.Update(report, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepository.FindByID(report.ID)
+}
+
+// Delete deletes one of a user's saved reports
+func (s *ReportService) Delete(id, ownerID uint) error {
+	if _, err := s.Get(id, ownerID); err != nil {
+		return err
+	}
+
+	return s.reportRepository.Delete(id)
+}
+
+// Run executes one of a user's saved reports
+func (s *ReportService) Run(id, ownerID uint) (*models.ReportResult, error) {
+	report, err := s.Get(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.run(report)
+}
+
+// Preview executes a report definition without saving it
+func (s *ReportService) Preview(request *models.ReportDefinitionRequest) (*models.ReportResult, error) {
+	report := &models.ReportDefinition{}
+	if err := applyReportRequest(report, request); err != nil {
+		return nil, err
+	}
+
+	return s.run(report)
+}
+
+// Widgets executes a user's pinned reports in widget order
+func (s *ReportService) Widgets(ownerID uint) ([]models.ReportResult, error) {
+	reports, err := s.reportRepository.ListByOwner(ownerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ReportResult, len(reports))
+	for i := range reports {
+		result, err := s.run(&reports[i])
+		if err != nil {
+			return nil, err
+		}
+		results[i] = *result
+	}
+
+	return results, nil
+}
+
+// run executes a report definition
+func (s *ReportService) run(report *models.ReportDefinition) (*models.ReportResult, error) {
+	rows, err := s.reportRepository.Run(report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReportResult{
+		Report:      report,
+		Rows:        rows,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// applyReportRequest validates a report request against the report schema and copies it onto the definition
+func applyReportRequest(report *models.ReportDefinition, request *models.ReportDefinitionRequest) error {
+	entity := request.Entity
+	if entity == "" {
+		entity = models.ReportEntityUsers
+	}
+	if !containsString(models.ReportEntities, entity) {
+		return fmt.Errorf("unknown entity: %s", entity)
+	}
+
+	metric := request.Metric
+	if metric == "" {
+		metric = models.ReportMetricCount
+	}
+	if !containsString(models.ReportMetrics, metric) {
+		return fmt.Errorf("unknown metric: %s", metric)
+	}
+
+	seen := make(map[string]bool, len(request.GroupBy))
+	for _, dimension := range request.GroupBy {
+		if !containsString(models.ReportDimensions, dimension) {
+			return fmt.Errorf("unknown dimension: %s", dimension)
+		}
+		if seen[dimension] {
+			return fmt.Errorf("duplicate dimension: %s", dimension)
+		}
+		seen[dimension] = true
+	}
+
+	for _, filter := range request.Filters {
+		if !containsString(models.ReportFilterFields, filter.Field) {
+			return fmt.Errorf("unknown filter field: %s", filter.Field)
+		}
+		if !containsString(models.ReportOperators, filter.Operator) {
+			return fmt.Errorf("unknown filter operator: %s", filter.Operator)
+		}
+
+		values, isList := filter.Value.([]interface{})
+		switch {
+		case filter.Operator == "in" && (!isList || len(values) == 0):
+			return fmt.Errorf("filter on %s with operator in needs a non-empty list of values", filter.Field)
+		case filter.Operator != "in" && isList:
+			return fmt.Errorf("filter on %s with operator %s needs a single value", filter.Field, filter.Operator)
+		case filter.Value == nil:
+			return fmt.Errorf("filter on %s needs a value", filter.Field)
+		}
+		for _, value := range append(values, filter.Value) {
+			if _, isObject := value.(map[string]interface{}); isObject {
+				return errors.New("filter values must be strings, numbers or booleans")
+			}
+		}
+	}
+
+	report.Name = request.Name
+	report.Entity = entity
+	report.Metric = metric
+	report.GroupBy = request.GroupBy
+	report.Filters = request.Filters
+	report.WidgetOrder = request.WidgetOrder
+	if request.IsPinned != nil {
+		report.IsPinned = *request.IsPinned
+	}
+
+	return nil
+}
+
+// containsString reports whether the list contains the value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"admin-dashboard/internal/repository"
+)
+
+// roleSweeperActor is recorded as revoked_by when expired grants end sessions
+const roleSweeperActor = "role-expiry"
+
+// RoleGrantSweeper periodically expires time-bound role assignments
+type RoleGrantSweeper struct {
+	roleRepository    *repository.RoleRepository
+	sessionRepository *repository.SessionRepository
+	userService       *UserService
+	interval          time.Duration
+}
+
+// NewRoleGrantSweeper creates a new role grant sweeper
+func NewRoleGrantSweeper(
+	roleRepository *repository.RoleRepository,
+	sessionRepository *repository.SessionRepository,
+	userService *UserService,
+	interval time.Duration,
+) *RoleGrantSweeper {
+	return &RoleGrantSweeper{
+		roleRepository:    roleRepository,
+		sessionRepository: sessionRepository,
+		userService:       userService,
+		interval:          interval,
+	}
+}
+
+// Run sweeps expired grants every interval until the context is cancelled
+func (s *RoleGrantSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sweep(); err != nil {
+			log.Printf("Role grant sweep failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires grants whose validity ended, one user at a time so that each user's
+// UserRolesChanged event is recorded with the removal, and revokes the sessions of affected
+// users, so their next token no longer carries the expired role. Every instance sweeps, but
+// each grant is expired, and its user's sessions revoked, by only one of them.
+func (s *RoleGrantSweeper) Sweep() error {
+	now := time.Now()
+	userIDs, err := s.roleRepository.FindUsersWithExpiredRoles(now)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		expirations, err := s.userService.ExpireRoles(userID, now)
+		if err != nil {
+			return err
+		}
+		if len(expirations) == 0 {
+			continue // Expired by another instance
+		}
+		for _, expiration := range expirations {
+			log.Printf("Expired role %d of user %d (valid until %s)", expiration.RoleID, expiration.UserID, expiration.ValidUntil.Format(time.RFC3339))
+		}
+
+		if err := s.sessionRepository.RevokeAllByUser(userID, roleSweeperActor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -11,16 +11,23 @@ import (
 
 // RoleService handles role-related operations
 type RoleService struct {
-	roleRepository *repository.RoleRepository
+	roleRepository   *repository.RoleRepository
+	outboxRepository *repository.OutboxRepository
 }
 
 // NewRoleService creates a new role service
-func NewRoleService(roleRepository *repository.RoleRepository) *RoleService {
+func NewRoleService(roleRepository *repository.RoleRepository, outboxRepository *repository.OutboxRepository) *RoleService {
 	return &RoleService{
-		roleRepository: roleRepository,
+		roleRepository:   roleRepository,
+		outboxRepository: outboxRepository,
 	}
 }
 
+// WithTx returns a copy of the service whose repositories run inside the given transaction
+func (s *RoleService) WithTx(tx *gorm.DB) *RoleService {
+	return NewRoleService(repository.NewRoleRepository(tx), repository.NewOutboxRepository(tx))
+}
+
 // Get gets a role by ID
 func (s *RoleService) Get(id uint) (*models.Role, error) {
 	return s.roleRepository.FindByID(id)
@@ -43,14 +50,27 @@ func (s *RoleService) Create(request *models.RoleRequest, createdBy string) (*mo
 		IsActive: true, // Default to active
 	}
 	
-	// Create role in database
-	err = s.roleRepository.Create(role, createdBy)
+	// Create role in database together with its outbox event
+	var roleResult *models.Role
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.roleRepository.Create(role, createdBy); err != nil {
+			return err
+		}
+		
+		// Get created role
+		roleResult, err = txService.roleRepository.FindByID(role.ID)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.RoleCreated{Role: roleResult})
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get created role
-	return s.roleRepository.FindByID(role.ID)
+	return roleResult, nil
 }
 
 // Update updates a role
@@ -60,6 +80,9 @@ func (s *RoleService) Update(id uint, request *models.RoleRequest, updatedBy str
 	if err != nil {
 		return nil, err
 	}
+	if request.Version != nil && *request.Version != role.Version {
+		return nil, ErrVersionConflict
+	}
 	
 	// Check if name is changed and already exists
 	if request.Name != role.Name {
@@ -76,19 +99,51 @@ func (s *RoleService) Update(id uint, request *models.RoleRequest, updatedBy str
 	// Update role fields
 	role.Level = request.Level
 	
-	// Update role in database
-	err = s.roleRepository.Update(role, updatedBy)
+	return s.Save(role, updatedBy)
+}
+
+// Save writes a changed role to the database together with its outbox event. Update validates
+// a request into the role first; SCIM provisioning changes the role itself.
+func (s *RoleService) Save(role *models.Role, updatedBy string) (*models.Role, error) {
+	var roleResult *models.Role
+	err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.roleRepository.Update(role, updatedBy); err != nil {
+			return err
+		}
+		
+		// Get updated role
+		var err error
+		roleResult, err = txService.roleRepository.FindByID(role.ID)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.RoleUpdated{Role: roleResult})
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get updated role
-	return s.roleRepository.FindByID(role.ID)
+	return roleResult, nil
 }
 
 // Delete deletes a role
 func (s *RoleService) Delete(id uint) error {
-	return s.roleRepository.Delete(id)
+	// Get role for the event payload
+	role, err := s.roleRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.roleRepository.Delete(id); err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.RoleDeleted{Role: role})
+	})
 }
 
 // List lists all roles with pagination
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// scheduledChangeBatchSize limits how many due changes one scheduler run applies
+const scheduledChangeBatchSize = 100
+
+// ErrScheduledChangeNotPending is returned when editing or cancelling a change that was already processed
+var ErrScheduledChangeNotPending = errors.New("only pending changes can be edited or cancelled")
+
+// ScheduledChangeService handles user updates scheduled for a later time
+type ScheduledChangeService struct {
+	scheduledChangeRepository *repository.ScheduledChangeRepository
+	userService               *UserService
+	approvalService           *ApprovalService
+	outboxRepository          *repository.OutboxRepository
+}
+
+// NewScheduledChangeService creates a new scheduled change service
+func NewScheduledChangeService(
+	scheduledChangeRepository *repository.ScheduledChangeRepository,
+	userService *UserService,
+	approvalService *ApprovalService,
+	outboxRepository *repository.OutboxRepository,
+) *ScheduledChangeService {
+	return &ScheduledChangeService{
+		scheduledChangeRepository: scheduledChangeRepository,
+		userService:               userService,
+		approvalService:           approvalService,
+		outboxRepository:          outboxRepository,
+	}
+}
+
+// Create schedules an update of a user. The update is validated now against the user's current
+// state and again when it is applied.
+func (s *ScheduledChangeService) Create(userID uint, request *models.ScheduledChangeRequest, requesterID uint, requestedBy string) (*models.ScheduledChangeResponse, error) {
+	effectiveAt, payload, err := s.prepare(userID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &models.ScheduledChange{
+		TargetUserID:  userID,
+		Payload:       payload,
+		EffectiveAt:   effectiveAt,
+		Status:        models.ScheduledChangePending,
+		RequestedByID: requesterID,
+		RequestedBy:   requestedBy,
+	}
+	if err := s.scheduledChangeRepository.Create(change); err != nil {
+		return nil, err
+	}
+	return newScheduledChangeResponse(change)
+}
+
+// Get gets a scheduled change by ID
+func (s *ScheduledChangeService) Get(id uint) (*models.ScheduledChangeResponse, error) {
+	change, err := s.scheduledChangeRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return newScheduledChangeResponse(change)
+}
+
+// List lists scheduled changes, optionally filtered by user and status
+func (s *ScheduledChangeService) List(userID uint, status string) ([]models.ScheduledChangeResponse, error) {
+	changes, err := s.scheduledChangeRepository.List(userID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ScheduledChangeResponse, len(changes))
+	for i := range changes {
+		response, err := newScheduledChangeResponse(&changes[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+	return responses, nil
+}
+
+// Update replaces the changes and effective time of a pending change
+func (s *ScheduledChangeService) Update(id uint, request *models.ScheduledChangeRequest, updatedBy string) (*models.ScheduledChangeResponse, error) {
+	change, err := s.scheduledChangeRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if change.Status != models.ScheduledChangePending {
+		return nil, ErrScheduledChangeNotPending
+	}
+
+	effectiveAt, payload, err := s.prepare(change.TargetUserID, request)
+	if err != nil {
+		return nil, err
+	}
+	change.EffectiveAt = effectiveAt
+	change.Payload = payload
+
+	if err := s.scheduledChangeRepository.Update(change, updatedBy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledChangeNotPending
+		}
+		return nil, err
+	}
+	return s.Get(id)
+}
+
+// Cancel cancels a pending change
+func (s *ScheduledChangeService) Cancel(id uint, cancelledBy string) (*models.ScheduledChangeResponse, error) {
+	if _, err := s.scheduledChangeRepository.FindByID(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduledChangeRepository.Cancel(id, cancelledBy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledChangeNotPending
+		}
+		return nil, err
+	}
+	return s.Get(id)
+}
+
+// RunDue applies the pending changes whose effective time has come, in order. Sensitive changes
+// are held as change requests like updates made through the API; a change that no longer
+// validates is marked as failed and not retried.
+func (s *ScheduledChangeService) RunDue(now time.Time) error {
+	changes, err := s.scheduledChangeRepository.FindDue(now, scheduledChangeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range changes {
+		if err := s.apply(&changes[i]); err != nil {
+			log.Printf("Scheduled change %d of user %d failed: %v", changes[i].ID, changes[i].TargetUserID, err)
+			// Not found when it was cancelled while it ran
+			if err := s.scheduledChangeRepository.RecordOutcome(changes[i].ID, models.ScheduledChangeFailed, nil, err.Error()); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply applies a due change on behalf of its requester, or submits it for approval
+func (s *ScheduledChangeService) apply(change *models.ScheduledChange) error {
+	var request models.UpdateUserRequest
+	if err := json.Unmarshal([]byte(change.Payload), &request); err != nil {
+		return err
+	}
+
+	// Record the outcome in the same transaction, so a change cancelled in the meantime or an
+	// outcome that fails to save leaves no update or change request behind
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		scheduledChangeRepository := repository.NewScheduledChangeRepository(tx)
+
+		// Hold sensitive changes for approval
+		changeRequest, err := s.approvalService.WithTx(tx).Submit(change.TargetUserID, &request, change.RequestedByID, change.RequestedBy)
+		if err != nil {
+			return err
+		}
+		if changeRequest != nil {
+			return scheduledChangeRepository.RecordOutcome(change.ID, models.ScheduledChangeSubmitted, &changeRequest.ID, "")
+		}
+
+		if _, err := s.userService.WithTx(tx).Update(change.TargetUserID, &request, change.RequestedBy); err != nil {
+			return err
+		}
+		return scheduledChangeRepository.RecordOutcome(change.ID, models.ScheduledChangeApplied, nil, "")
+	})
+}
+
+// prepare validates a scheduled change request and returns its effective time and payload
+func (s *ScheduledChangeService) prepare(userID uint, request *models.ScheduledChangeRequest) (time.Time, string, error) {
+	effectiveAt, err := parseTimestamp(request.EffectiveAt)
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid effective_at, use RFC 3339 or YYYY-MM-DD")
+	}
+	if !effectiveAt.After(time.Now()) {
+		return time.Time{}, "", errors.New("effective_at must be in the future")
+	}
+
+	if err := s.userService.ValidateUpdate(userID, request.Changes); err != nil {
+		return time.Time{}, "", err
+	}
+
+	payload, err := json.Marshal(request.Changes)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return effectiveAt, string(payload), nil
+}
+
+// newScheduledChangeResponse decodes the changes of a scheduled change
+func newScheduledChangeResponse(change *models.ScheduledChange) (*models.ScheduledChangeResponse, error) {
+	var changes models.UpdateUserRequest
+	if err := json.Unmarshal([]byte(change.Payload), &changes); err != nil {
+		return nil, err
+	}
+
+	return &models.ScheduledChangeResponse{
+		ScheduledChange: *change,
+		Changes:         &changes,
+	}, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// schedulerLockKey is the Postgres advisory lock held by the leading scheduler instance
+const schedulerLockKey int64 = 0x7363686564756c65 // "schedule"
+
+// ScheduledJob runs the work that is due at now
+type ScheduledJob func(now time.Time) error
+
+// scheduledJob is a named job registered on the scheduler
+type scheduledJob struct {
+	name string
+	run  ScheduledJob
+}
+
+// Scheduler runs due jobs such as report schedules and scheduled offboardings. Every API
+// instance runs a scheduler, but only the one holding the advisory lock runs jobs; when it
+// stops or loses its connection, the lock is released and another instance takes over on its
+// next poll.
+type Scheduler struct {
+	db       *gorm.DB
+	interval time.Duration
+	jobs     []scheduledJob
+
+	conn *sql.Conn // the connection holding the lock while this instance leads
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(db *gorm.DB, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		db:       db,
+		interval: interval,
+	}
+}
+
+// Register adds a job that runs on every poll while this instance leads. Register jobs before
+// calling Run.
+func (s *Scheduler) Register(name string, job ScheduledJob) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, run: job})
+}
+
+// Run runs due jobs every interval while leading, until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer s.resign()
+
+	for {
+		leader, err := s.lead(ctx)
+		if err != nil {
+			log.Printf("Scheduler leader election failed: %v", err)
+		}
+		if leader {
+			for _, job := range s.jobs {
+				if err := job.run(time.Now()); err != nil {
+					log.Printf("Scheduled job %s failed: %v", job.name, err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead reports whether this instance leads, trying to take the lock when it does not. The lock
+// is session-level, so it is held on a dedicated connection for as long as the instance leads.
+func (s *Scheduler) lead(ctx context.Context) (bool, error) {
+	if s.conn != nil {
+		if err := s.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		// The connection and with it the lock are gone
+		log.Printf("Scheduler lost leadership")
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", schedulerLockKey).Scan(&locked); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	log.Printf("Scheduler acquired leadership")
+	s.conn = conn
+	return true, nil
+}
+
+// resign releases the lock so another instance can take over without waiting for the
+// connection to drop
+func (s *Scheduler) resign() {
+	if s.conn == nil {
+		return
+	}
+	if _, err := s.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", schedulerLockKey); err != nil {
+		log.Printf("Scheduler failed to release leadership: %v", err)
+	}
+	s.conn.Close()
+	s.conn = nil
+}
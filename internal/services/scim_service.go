@@ -0,0 +1,1072 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// scimActor is recorded as created_by/updated_by for provisioned changes
+	scimActor = "scim"
+
+	// scimMaxCount caps the page size of list responses
+	scimMaxCount = 200
+)
+
+// SCIMError represents an error that maps onto a SCIM error response
+type SCIMError struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+// Error implements the error interface
+func (e *SCIMError) Error() string {
+	return e.Detail
+}
+
+func newSCIMError(status int, scimType, detail string) *SCIMError {
+	return &SCIMError{Status: status, ScimType: scimType, Detail: detail}
+}
+
+// scimAttribute maps a filterable SCIM attribute onto a database column
+type scimAttribute struct {
+	column string
+	kind   string // string, bool, uuid or int
+}
+
+// scimUserAttributes lists the filterable SCIM user attributes
+var scimUserAttributes = map[string]scimAttribute{
+	"id":             {column: "u_uid", kind: "uuid"},
+	"username":       {column: "u_email", kind: "string"},
+	"externalid":     {column: "u_employee_id", kind: "string"},
+	"emails":         {column: "u_email", kind: "string"},
+	"emails.value":   {column: "u_email", kind: "string"},
+	"displayname":    {column: "u_name", kind: "string"},
+	"name.formatted": {column: "u_name", kind: "string"},
+	"active":         {column: "u_is_active", kind: "bool"},
+}
+
+// scimGroupAttributes lists the filterable SCIM group attributes
+var scimGroupAttributes = map[string]scimAttribute{
+	"id":          {column: "role_id", kind: "int"},
+	"displayname": {column: "role_name", kind: "string"},
+}
+
+// scimMemberPath matches paths such as members[value eq "<id>"]
+var scimMemberPath = regexp.MustCompile(`(?i)^members\[value eq "([^"]+)"\]$`)
+
+// SCIMService handles SCIM 2.0 provisioning operations. Writes go through the user and role
+// services, so provisioned changes emit the same events as changes made through the API.
+type SCIMService struct {
+	userRepository   *repository.UserRepository
+	roleRepository   *repository.RoleRepository
+	outboxRepository *repository.OutboxRepository
+	userService      *UserService
+	roleService      *RoleService
+}
+
+// NewSCIMService creates a new SCIM service
+func NewSCIMService(
+	userRepository *repository.UserRepository,
+	roleRepository *repository.RoleRepository,
+	outboxRepository *repository.OutboxRepository,
+	userService *UserService,
+	roleService *RoleService,
+) *SCIMService {
+	return &SCIMService{
+		userRepository:   userRepository,
+		roleRepository:   roleRepository,
+		outboxRepository: outboxRepository,
+		userService:      userService,
+		roleService:      roleService,
+	}
+}
+
+// WithTx returns a copy of the service that reads and writes through the given transaction
+func (s *SCIMService) WithTx(tx *gorm.DB) *SCIMService {
+	return NewSCIMService(
+		repository.NewUserRepository(tx),
+		repository.NewRoleRepository(tx),
+		repository.NewOutboxRepository(tx),
+		s.userService.WithTx(tx),
+		s.roleService.WithTx(tx),
+	)
+}
+
+// ListUsers lists users matching a SCIM filter
+func (s *SCIMService) ListUsers(filter string, startIndex, count int) (*models.SCIMListResponse, error) {
+	conditions, err := parseSCIMFilter(filter, scimUserAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	startIndex, count = normalizeSCIMPagination(startIndex, count)
+	users, totalResults, err := s.userRepository.ListByConditions(conditions, startIndex-1, count)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*models.SCIMUser, len(users))
+	for i := range users {
+		resources[i] = toSCIMUser(&users[i])
+	}
+
+	return newSCIMListResponse(resources, totalResults, startIndex), nil
+}
+
+// GetUser gets a user by SCIM ID
+func (s *SCIMService) GetUser(id string) (*models.SCIMUser, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+// CreateUser provisions a new user
+func (s *SCIMService) CreateUser(request *models.SCIMUser) (*models.SCIMUser, error) {
+	if request.ExternalID == "" {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "externalId is required")
+	}
+
+	user := &models.User{
+		JoinDate: today(),
+		IsActive: true, // Default to active
+	}
+	if err := applySCIMUser(user, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUniqueUser(user, 0); err != nil {
+		return nil, err
+	}
+
+	// Identity providers rarely send a password, so generate one the user never sees
+	user.Password = request.Password
+	if user.Password == "" {
+		password, err := randomPassword()
+		if err != nil {
+			return nil, err
+		}
+		user.Password = password
+	}
+
+	if _, err := s.userService.Insert(user, nil, scimActor); err != nil {
+		return nil, err
+	}
+
+	created, err := s.userRepository.FindByID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMUser(created), nil
+}
+
+// ReplaceUser replaces a user with the given representation
+func (s *SCIMService) ReplaceUser(id string, request *models.SCIMUser) (*models.SCIMUser, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attributes missing from a replace are cleared
+	user.Phone = ""
+	user.Address = ""
+	if err := applySCIMUser(user, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUniqueUser(user, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveUser(user, request.Password); err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(id)
+}
+
+// PatchUser applies SCIM PATCH operations to a user
+func (s *SCIMService) PatchUser(id string, request *models.SCIMPatchRequest) (*models.SCIMUser, error) {
+	user, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var password string
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace":
+			if operation.Path == "" {
+				var attributes map[string]json.RawMessage
+				if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+					return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "value must be an object when path is omitted")
+				}
+				for path, value := range attributes {
+					if err := applySCIMUserAttribute(user, path, value, &password); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+
+			if err := applySCIMUserAttribute(user, operation.Path, operation.Value, &password); err != nil {
+				return nil, err
+			}
+		case "remove":
+			if err := removeSCIMUserAttribute(user, operation.Path); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, newSCIMError(http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("unsupported operation %q", operation.Op))
+		}
+	}
+
+	if err := s.ensureUniqueUser(user, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveUser(user, password); err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(id)
+}
+
+// DeactivateUser deprovisions a user by marking it inactive instead of deleting it
+func (s *SCIMService) DeactivateUser(id string) error {
+	user, err := s.findUser(id)
+	if err != nil {
+		return err
+	}
+
+	user.IsActive = false
+	_, err = s.userService.Save(user, nil, scimActor)
+	return err
+}
+
+// ListGroups lists roles matching a SCIM filter as groups
+func (s *SCIMService) ListGroups(filter string, startIndex, count int, includeMembers bool) (*models.SCIMListResponse, error) {
+	conditions, err := parseSCIMFilter(filter, scimGroupAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	startIndex, count = normalizeSCIMPagination(startIndex, count)
+	roles, totalResults, err := s.roleRepository.ListByConditions(conditions, startIndex-1, count)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*models.SCIMGroup, len(roles))
+	for i := range roles {
+		var members []models.User
+		if includeMembers {
+			members, err = s.roleRepository.GetRoleUsers(roles[i].ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resources[i] = toSCIMGroup(&roles[i], members)
+	}
+
+	return newSCIMListResponse(resources, totalResults, startIndex), nil
+}
+
+// GetGroup gets a role by SCIM ID as a group
+func (s *SCIMService) GetGroup(id string) (*models.SCIMGroup, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.roleRepository.GetRoleUsers(role.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSCIMGroup(role, members), nil
+}
+
+// CreateGroup provisions a new role from a group
+func (s *SCIMService) CreateGroup(request *models.SCIMGroup) (*models.SCIMGroup, error) {
+	if request.DisplayName == "" {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	if err := s.ensureUniqueRole(request.DisplayName, 0); err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.resolveMembers(request.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the role together with its members
+	var role *models.Role
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		var err error
+		role, err = s.roleService.WithTx(tx).Create(&models.RoleRequest{Name: request.DisplayName}, scimActor)
+		if err != nil {
+			return err
+		}
+
+		return s.userService.WithTx(tx).SetRoleMembers(role.ID, memberIDs, scimActor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(strconv.FormatUint(uint64(role.ID), 10))
+}
+
+// ReplaceGroup replaces a group's name and members
+func (s *SCIMService) ReplaceGroup(id string, request *models.SCIMGroup) (*models.SCIMGroup, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.DisplayName == "" {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	memberIDs, err := s.resolveMembers(request.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rename the role and replace its members together
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.renameRole(role, request.DisplayName); err != nil {
+			return err
+		}
+
+		return txService.userService.SetRoleMembers(role.ID, memberIDs, scimActor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(id)
+}
+
+// PatchGroup applies SCIM PATCH operations to a group
+func (s *SCIMService) PatchGroup(id string, request *models.SCIMPatchRequest) (*models.SCIMGroup, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply all operations or none
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		for _, operation := range request.Operations {
+			op := strings.ToLower(operation.Op)
+			path := strings.ToLower(operation.Path)
+
+			switch {
+			case op != "add" && op != "replace" && op != "remove":
+				return newSCIMError(http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("unsupported operation %q", operation.Op))
+
+			case path == "" && op != "remove":
+				var value struct {
+					DisplayName string              `json:"displayName"`
+					Members     []models.SCIMMember `json:"members"`
+				}
+				if err := json.Unmarshal(operation.Value, &value); err != nil {
+					return newSCIMError(http.StatusBadRequest, "invalidValue", "value must be an object when path is omitted")
+				}
+				if value.DisplayName != "" {
+					if err := txService.renameRole(role, value.DisplayName); err != nil {
+						return err
+					}
+				}
+				if value.Members != nil {
+					if err := txService.patchMembers(role.ID, op, value.Members); err != nil {
+						return err
+					}
+				}
+
+			case path == "displayname":
+				if op == "remove" {
+					return newSCIMError(http.StatusBadRequest, "mutability", "displayName cannot be removed")
+				}
+				name, err := decodeSCIMString(operation.Value)
+				if err != nil {
+					return err
+				}
+				if err := txService.renameRole(role, name); err != nil {
+					return err
+				}
+
+			case path == "members":
+				var members []models.SCIMMember
+				if len(operation.Value) > 0 {
+					if err := json.Unmarshal(operation.Value, &members); err != nil {
+						return newSCIMError(http.StatusBadRequest, "invalidValue", "members must be an array")
+					}
+				}
+				if op == "remove" && len(members) == 0 {
+					op = "replace" // Removing without a value clears all members
+				}
+				if err := txService.patchMembers(role.ID, op, members); err != nil {
+					return err
+				}
+
+			case scimMemberPath.MatchString(operation.Path) && op == "remove":
+				match := scimMemberPath.FindStringSubmatch(operation.Path)
+				if err := txService.patchMembers(role.ID, op, []models.SCIMMember{{Value: match[1]}}); err != nil {
+					return err
+				}
+
+			default:
+				return newSCIMError(http.StatusBadRequest, "invalidPath", fmt.Sprintf("unsupported path %q", operation.Path))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGroup(id)
+}
+
+// DeactivateGroup deprovisions a group by marking the role inactive
+func (s *SCIMService) DeactivateGroup(id string) error {
+	role, err := s.findRole(id)
+	if err != nil {
+		return err
+	}
+
+	role.IsActive = false
+	_, err = s.roleService.Save(role, scimActor)
+	return err
+}
+
+// findUser finds a user by SCIM ID
+func (s *SCIMService) findUser(id string) (*models.User, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, newSCIMError(http.StatusNotFound, "", "User not found")
+	}
+
+	user, err := s.userRepository.FindByUID(uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, newSCIMError(http.StatusNotFound, "", "User not found")
+	}
+	return user, err
+}
+
+// findRole finds a role by SCIM ID
+func (s *SCIMService) findRole(id string) (*models.Role, error) {
+	roleID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, newSCIMError(http.StatusNotFound, "", "Group not found")
+	}
+
+	role, err := s.roleRepository.FindByID(uint(roleID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, newSCIMError(http.StatusNotFound, "", "Group not found")
+	}
+	return role, err
+}
+
+// ensureUniqueUser checks that no other user has the same email or employee ID
+func (s *SCIMService) ensureUniqueUser(user *models.User, excludeID uint) error {
+	existing, err := s.userRepository.FindByEmail(user.Email)
+	if err == nil && existing.ID != excludeID {
+		return newSCIMError(http.StatusConflict, "uniqueness", "userName already exists")
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	existing, err = s.userRepository.FindByEmployeeID(user.EmployeeID)
+	if err == nil && existing.ID != excludeID {
+		return newSCIMError(http.StatusConflict, "uniqueness", "externalId already exists")
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// ensureUniqueRole checks that no other role has the same name
+func (s *SCIMService) ensureUniqueRole(name string, excludeID uint) error {
+	existing, err := s.roleRepository.FindByName(name)
+	if err == nil && existing.ID != excludeID {
+		return newSCIMError(http.StatusConflict, "uniqueness", "displayName already exists")
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// renameRole renames a role if the name changed
+func (s *SCIMService) renameRole(role *models.Role, name string) error {
+	if name == role.Name {
+		return nil
+	}
+
+	if err := s.ensureUniqueRole(name, role.ID); err != nil {
+		return err
+	}
+
+	role.Name = name
+	updated, err := s.roleService.Save(role, scimActor)
+	if err != nil {
+		return err
+	}
+	*role = *updated
+	return nil
+}
+
+// resolveMembers converts SCIM member references into user IDs
+func (s *SCIMService) resolveMembers(members []models.SCIMMember) ([]uint, error) {
+	userIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		user, err := s.findUser(member.Value)
+		if err != nil {
+			var scimErr *SCIMError
+			if errors.As(err, &scimErr) {
+				return nil, newSCIMError(http.StatusBadRequest, "invalidValue", fmt.Sprintf("member %q does not exist", member.Value))
+			}
+			return nil, err
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// patchMembers adds, removes or replaces members of a role
+func (s *SCIMService) patchMembers(roleID uint, op string, members []models.SCIMMember) error {
+	userIDs, err := s.resolveMembers(members)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case "add":
+		for _, userID := range userIDs {
+			if err := s.userService.AssignRole(userID, roleID, scimActor); err != nil {
+				return err
+			}
+		}
+	case "remove":
+		for _, userID := range userIDs {
+			if err := s.userService.RevokeRole(userID, roleID); err != nil {
+				return err
+			}
+		}
+	case "replace":
+		return s.userService.SetRoleMembers(roleID, userIDs, scimActor)
+	}
+	return nil
+}
+
+// saveUser saves a provisioned user and, when given, its new password in one transaction
+func (s *SCIMService) saveUser(user *models.User, password string) error {
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.userService.WithTx(tx).Save(user, nil, scimActor); err != nil {
+			return err
+		}
+
+		if password != "" {
+			return repository.NewUserRepository(tx).UpdatePassword(user.ID, password, scimActor)
+		}
+		return nil
+	})
+}
+
+// toSCIMUser converts a user into its SCIM representation
+func toSCIMUser(user *models.User) *models.SCIMUser {
+	active := user.IsActive
+	givenName, familyName := splitName(user.Name)
+
+	scimUser := &models.SCIMUser{
+		Schemas:    []string{models.SCIMSchemaUser},
+		ID:         user.UID.String(),
+		ExternalID: user.EmployeeID,
+		UserName:   user.Email,
+		Name: &models.SCIMName{
+			Formatted:  user.Name,
+			GivenName:  givenName,
+			FamilyName: familyName,
+		},
+		DisplayName: user.Name,
+		Emails:      []models.SCIMMultiValue{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &models.SCIMMeta{
+			ResourceType: "User",
+			Created:      &user.CreatedAt,
+			LastModified: &user.UpdatedAt,
+			Location:     "/scim/v2/Users/" + user.UID.String(),
+		},
+	}
+
+	if user.Phone != "" {
+		scimUser.PhoneNumbers = []models.SCIMMultiValue{{Value: user.Phone, Type: "work", Primary: true}}
+	}
+
+	if user.Address != "" {
+		scimUser.Addresses = []models.SCIMAddress{{Formatted: user.Address, Type: "work", Primary: true}}
+	}
+
+	for _, role := range user.Roles {
+		scimUser.Groups = append(scimUser.Groups, models.SCIMMultiValue{
+			Value:   strconv.FormatUint(uint64(role.ID), 10),
+			Display: role.Name,
+		})
+	}
+
+	return scimUser
+}
+
+// toSCIMGroup converts a role and its members into a SCIM group
+func toSCIMGroup(role *models.Role, members []models.User) *models.SCIMGroup {
+	id := strconv.FormatUint(uint64(role.ID), 10)
+	group := &models.SCIMGroup{
+		Schemas:     []string{models.SCIMSchemaGroup},
+		ID:          id,
+		DisplayName: role.Name,
+		Members:     make([]models.SCIMMember, len(members)),
+		Meta: &models.SCIMMeta{
+			ResourceType: "Group",
+			Created:      &role.CreatedAt,
+			LastModified: &role.UpdatedAt,
+			Location:     "/scim/v2/Groups/" + id,
+		},
+	}
+
+	for i, member := range members {
+		group.Members[i] = models.SCIMMember{
+			Value:   member.UID.String(),
+			Display: member.Name,
+			Ref:     "/scim/v2/Users/" + member.UID.String(),
+		}
+	}
+
+	return group
+}
+
+// applySCIMUser copies a SCIM user representation onto a user
+func applySCIMUser(user *models.User, request *models.SCIMUser) error {
+	if request.UserName == "" {
+		return newSCIMError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	user.Email = request.UserName
+
+	if request.ExternalID != "" {
+		user.EmployeeID = request.ExternalID
+	}
+
+	switch {
+	case request.Name != nil && request.Name.Formatted != "":
+		user.Name = request.Name.Formatted
+	case request.Name != nil && (request.Name.GivenName != "" || request.Name.FamilyName != ""):
+		user.Name = strings.TrimSpace(request.Name.GivenName + " " + request.Name.FamilyName)
+	case request.DisplayName != "":
+		user.Name = request.DisplayName
+	}
+
+	if user.Name == "" {
+		return newSCIMError(http.StatusBadRequest, "invalidValue", "name or displayName is required")
+	}
+
+	if phone := primaryValue(request.PhoneNumbers); phone != "" {
+		user.Phone = phone
+	}
+
+	for i, address := range request.Addresses {
+		if i == 0 || address.Primary {
+			user.Address = address.Formatted
+		}
+	}
+
+	if request.Active != nil {
+		user.IsActive = *request.Active
+	}
+
+	return nil
+}
+
+// applySCIMUserAttribute applies an add or replace operation on a single user attribute
+func applySCIMUserAttribute(user *models.User, path string, value json.RawMessage, password *string) error {
+	attribute := strings.ToLower(strings.TrimPrefix(path, models.SCIMSchemaUser+":"))
+
+	// Extension attributes are not mapped onto users
+	if strings.HasPrefix(attribute, "urn:") {
+		return nil
+	}
+
+	var err error
+	switch {
+	case attribute == "username":
+		user.Email, err = decodeSCIMString(value)
+	case attribute == "externalid":
+		user.EmployeeID, err = decodeSCIMString(value)
+	case attribute == "displayname" || attribute == "name.formatted":
+		user.Name, err = decodeSCIMString(value)
+	case attribute == "name":
+		var name models.SCIMName
+		if err := json.Unmarshal(value, &name); err != nil {
+			return newSCIMError(http.StatusBadRequest, "invalidValue", "name must be an object")
+		}
+		if name.Formatted != "" {
+			user.Name = name.Formatted
+		} else if name.GivenName != "" || name.FamilyName != "" {
+			user.Name = strings.TrimSpace(name.GivenName + " " + name.FamilyName)
+		}
+	case attribute == "name.givenname":
+		var givenName string
+		givenName, err = decodeSCIMString(value)
+		_, familyName := splitName(user.Name)
+		user.Name = strings.TrimSpace(givenName + " " + familyName)
+	case attribute == "name.familyname":
+		var familyName string
+		familyName, err = decodeSCIMString(value)
+		givenName, _ := splitName(user.Name)
+		user.Name = strings.TrimSpace(givenName + " " + familyName)
+	case attribute == "active":
+		user.IsActive, err = decodeSCIMBool(value)
+	case attribute == "password":
+		*password, err = decodeSCIMString(value)
+	case strings.HasPrefix(attribute, "emails"):
+		user.Email, err = decodeSCIMMultiValue(value)
+	case strings.HasPrefix(attribute, "phonenumbers"):
+		user.Phone, err = decodeSCIMMultiValue(value)
+	case strings.HasPrefix(attribute, "addresses"):
+		user.Address, err = decodeSCIMAddress(value)
+	default:
+		return newSCIMError(http.StatusBadRequest, "invalidPath", fmt.Sprintf("unsupported path %q", path))
+	}
+	return err
+}
+
+// removeSCIMUserAttribute applies a remove operation on a single user attribute
+func removeSCIMUserAttribute(user *models.User, path string) error {
+	attribute := strings.ToLower(strings.TrimPrefix(path, models.SCIMSchemaUser+":"))
+
+	switch {
+	case attribute == "":
+		return newSCIMError(http.StatusBadRequest, "noTarget", "path is required for remove operations")
+	case strings.HasPrefix(attribute, "urn:"):
+		return nil
+	case strings.HasPrefix(attribute, "phonenumbers"):
+		user.Phone = ""
+	case strings.HasPrefix(attribute, "addresses"):
+		user.Address = ""
+	default:
+		return newSCIMError(http.StatusBadRequest, "mutability", fmt.Sprintf("%q cannot be removed", path))
+	}
+	return nil
+}
+
+// decodeSCIMString decodes a string operation value
+func decodeSCIMString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", newSCIMError(http.StatusBadRequest, "invalidValue", "value must be a string")
+	}
+	return s, nil
+}
+
+// decodeSCIMBool decodes a boolean operation value, accepting "True"/"False" strings
+func decodeSCIMBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if parsed, err := strconv.ParseBool(s); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return false, newSCIMError(http.StatusBadRequest, "invalidValue", "value must be a boolean")
+}
+
+// decodeSCIMMultiValue decodes a multi-valued attribute into its primary value
+func decodeSCIMMultiValue(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+
+	var values []models.SCIMMultiValue
+	if err := json.Unmarshal(value, &values); err == nil {
+		return primaryValue(values), nil
+	}
+
+	var single models.SCIMMultiValue
+	if err := json.Unmarshal(value, &single); err == nil {
+		return single.Value, nil
+	}
+
+	return "", newSCIMError(http.StatusBadRequest, "invalidValue", "value must be a string or multi-valued attribute")
+}
+
+// decodeSCIMAddress decodes an address attribute into its formatted value
+func decodeSCIMAddress(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+
+	var addresses []models.SCIMAddress
+	if err := json.Unmarshal(value, &addresses); err == nil {
+		var formatted string
+		for i, address := range addresses {
+			if i == 0 || address.Primary {
+				formatted = address.Formatted
+			}
+		}
+		return formatted, nil
+	}
+
+	return "", newSCIMError(http.StatusBadRequest, "invalidValue", "value must be a string or address list")
+}
+
+// primaryValue returns the primary value of a multi-valued attribute, or the first one
+func primaryValue(values []models.SCIMMultiValue) string {
+	var value string
+	for i, v := range values {
+		if i == 0 || v.Primary {
+			value = v.Value
+		}
+		if v.Primary {
+			break
+		}
+	}
+	return value
+}
+
+// splitName splits a full name into given and family names
+func splitName(name string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(name), " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// parseSCIMFilter converts a SCIM filter expression into repository conditions.
+// Only "and" joined comparisons on whitelisted attributes are supported.
+func parseSCIMFilter(filter string, attributes map[string]scimAttribute) ([]repository.Condition, error) {
+	tokens, err := tokenizeSCIMFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []repository.Condition
+	for i := 0; i < len(tokens); {
+		if len(conditions) > 0 {
+			if !strings.EqualFold(tokens[i], "and") {
+				return nil, newSCIMError(http.StatusBadRequest, "invalidFilter", fmt.Sprintf("unsupported logical operator %q", tokens[i]))
+			}
+			i++
+		}
+
+		if i+1 >= len(tokens) {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidFilter", "incomplete filter expression")
+		}
+
+		attribute, ok := attributes[strings.ToLower(tokens[i])]
+		if !ok {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidFilter", fmt.Sprintf("unsupported filter attribute %q", tokens[i]))
+		}
+		op := strings.ToLower(tokens[i+1])
+
+		if op == "pr" {
+			conditions = append(conditions, repository.Condition{Column: attribute.column, Operator: "IS NOT NULL"})
+			i += 2
+			continue
+		}
+
+		if i+2 >= len(tokens) {
+			return nil, newSCIMError(http.StatusBadRequest, "invalidFilter", "incomplete filter expression")
+		}
+
+		condition, err := newSCIMCondition(attribute, op, tokens[i+2])
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+		i += 3
+	}
+
+	return conditions, nil
+}
+
+// newSCIMCondition builds a repository condition from a single comparison
+func newSCIMCondition(attribute scimAttribute, op, rawValue string) (repository.Condition, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+		return repository.Condition{}, newSCIMError(http.StatusBadRequest, "invalidFilter", fmt.Sprintf("invalid filter value %s", rawValue))
+	}
+
+	condition := repository.Condition{Column: attribute.column}
+
+	switch attribute.kind {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return condition, newSCIMError(http.StatusBadRequest, "invalidFilter", "filter value must be a string")
+		}
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+
+		switch op {
+		case "eq":
+			condition.Operator, condition.Value = "ILIKE", escaped
+		case "ne":
+			condition.Operator, condition.Value = "NOT ILIKE", escaped
+		case "co":
+			condition.Operator, condition.Value = "ILIKE", "%"+escaped+"%"
+		case "sw":
+			condition.Operator, condition.Value = "ILIKE", escaped+"%"
+		case "ew":
+			condition.Operator, condition.Value = "ILIKE", "%"+escaped
+		default:
+			return condition, newSCIMError(http.StatusBadRequest, "invalidFilter", fmt.Sprintf("unsupported filter operator %q", op))
+		}
+		return condition, nil
+
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return condition, newSCIMError(http.StatusBadRequest, "invalidFilter", "filter value must be a boolean")
+		}
+		condition.Value = b
+
+	case "uuid":
+		s, _ := value.(string)
+		uid, err := uuid.Parse(s)
+		if err != nil {
+			return condition, newSCIMError(http.StatusBadRequest, "invalidFilter", "filter value must be a valid id")
+		}
+		condition.Value = uid
+
+	case "int":
+		s, _ := value.(string)
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return condition, newSCIMError(http.StatusBadRequest, "invalidFilter", "filter value must be a valid id")
+		}
+		condition.Value = uint(id)
+	}
+
+	switch op {
+	case "eq":
+		condition.Operator = "="
+	case "ne":
+		condition.Operator = "<>"
+	default:
+		return condition, newSCIMError(http.StatusBadRequest, "invalidFilter", fmt.Sprintf("unsupported filter operator %q", op))
+	}
+	return condition, nil
+}
+
+// tokenizeSCIMFilter splits a filter expression on whitespace, keeping quoted strings intact
+func tokenizeSCIMFilter(filter string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuotes, escaped := false, false
+
+	for _, r := range filter {
+		switch {
+		case inQuotes:
+			current.WriteRune(r)
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == '"' {
+				inQuotes = false
+			}
+		case r == '"':
+			current.WriteRune(r)
+			inQuotes = true
+		case unicode.IsSpace(r):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if inQuotes {
+		return nil, newSCIMError(http.StatusBadRequest, "invalidFilter", "unterminated string in filter")
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
+
+// normalizeSCIMPagination applies SCIM defaults to startIndex and count
+func normalizeSCIMPagination(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	if count < 0 {
+		count = 0
+	} else if count > scimMaxCount {
+		count = scimMaxCount
+	}
+
+	return startIndex, count
+}
+
+// newSCIMListResponse wraps resources in a SCIM list response
+func newSCIMListResponse[T any](resources []T, totalResults int64, startIndex int) *models.SCIMListResponse {
+	return &models.SCIMListResponse{
+		Schemas:      []string{models.SCIMSchemaListResponse},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// randomPassword generates a random password for provisioned users
+func randomPassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// today returns the current date at midnight
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"admin-dashboard/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSCIMFilter(t *testing.T) {
+	uid := uuid.MustParse("2819c223-7f76-453a-919d-413861904646")
+
+	tests := []struct {
+		name       string
+		filter     string
+		attributes map[string]scimAttribute
+		want       []repository.Condition
+	}{
+		{
+			name:       "empty filter",
+			filter:     "",
+			attributes: scimUserAttributes,
+			want:       nil,
+		},
+		{
+			name:       "string equality is case-insensitive",
+			filter:     `userName eq "Jane@Example.com"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_email", Operator: "ILIKE", Value: "Jane@Example.com"}},
+		},
+		{
+			name:       "attribute and operator names are case-insensitive",
+			filter:     `DISPLAYNAME SW "Jane"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_name", Operator: "ILIKE", Value: "Jane%"}},
+		},
+		{
+			name:       "contains escapes LIKE wildcards",
+			filter:     `displayName co "50%_off\\"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_name", Operator: "ILIKE", Value: `%50\%\_off\\%`}},
+		},
+		{
+			name:       "ends with",
+			filter:     `emails.value ew "@example.com"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_email", Operator: "ILIKE", Value: "%@example.com"}},
+		},
+		{
+			name:       "string inequality is case-insensitive",
+			filter:     `externalId ne "e_001"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_employee_id", Operator: "NOT ILIKE", Value: `e\_001`}},
+		},
+		{
+			name:       "quoted value keeps spaces and escaped quotes",
+			filter:     `name.formatted eq "Jane \"JD\" Doe"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_name", Operator: "ILIKE", Value: `Jane "JD" Doe`}},
+		},
+		{
+			name:       "present",
+			filter:     "externalId pr",
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_employee_id", Operator: "IS NOT NULL"}},
+		},
+		{
+			name:       "boolean",
+			filter:     "active eq false",
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_is_active", Operator: "=", Value: false}},
+		},
+		{
+			name:       "uuid",
+			filter:     `id eq "2819c223-7f76-453a-919d-413861904646"`,
+			attributes: scimUserAttributes,
+			want:       []repository.Condition{{Column: "u_uid", Operator: "=", Value: uid}},
+		},
+		{
+			name:       "group id",
+			filter:     `id ne "12"`,
+			attributes: scimGroupAttributes,
+			want:       []repository.Condition{{Column: "role_id", Operator: "<>", Value: uint(12)}},
+		},
+		{
+			name:       "and joins comparisons",
+			filter:     `active eq true and  userName sw "j" AND externalId pr`,
+			attributes: scimUserAttributes,
+			want: []repository.Condition{
+				{Column: "u_is_active", Operator: "=", Value: true},
+				{Column: "u_email", Operator: "ILIKE", Value: "j%"},
+				{Column: "u_employee_id", Operator: "IS NOT NULL"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSCIMFilter(tt.filter, tt.attributes)
+			if err != nil {
+				t.Fatalf("parseSCIMFilter(%q): %v", tt.filter, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSCIMFilter(%q) = %#v, want %#v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSCIMFilterInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     string
+		attributes map[string]scimAttribute
+	}{
+		{name: "or is not supported", filter: `userName eq "a" or userName eq "b"`, attributes: scimUserAttributes},
+		{name: "unknown attribute", filter: `title eq "CEO"`, attributes: scimUserAttributes},
+		{name: "user attribute on groups", filter: `userName eq "a"`, attributes: scimGroupAttributes},
+		{name: "missing value", filter: "userName eq", attributes: scimUserAttributes},
+		{name: "missing operator", filter: "userName", attributes: scimUserAttributes},
+		{name: "dangling and", filter: `userName eq "a" and`, attributes: scimUserAttributes},
+		{name: "unterminated string", filter: `userName eq "a`, attributes: scimUserAttributes},
+		{name: "unquoted string", filter: "userName eq jane", attributes: scimUserAttributes},
+		{name: "string for boolean", filter: `active eq "true"`, attributes: scimUserAttributes},
+		{name: "number for string", filter: "userName eq 1", attributes: scimUserAttributes},
+		{name: "invalid uuid", filter: `id eq "42"`, attributes: scimUserAttributes},
+		{name: "invalid group id", filter: `id eq "admins"`, attributes: scimGroupAttributes},
+		{name: "unsupported string operator", filter: `userName gt "a"`, attributes: scimUserAttributes},
+		{name: "unsupported boolean operator", filter: "active co true", attributes: scimUserAttributes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSCIMFilter(tt.filter, tt.attributes)
+
+			var scimErr *SCIMError
+			if !errors.As(err, &scimErr) {
+				t.Fatalf("parseSCIMFilter(%q) error = %v, want a SCIMError", tt.filter, err)
+			}
+			if scimErr.Status != http.StatusBadRequest || scimErr.ScimType != "invalidFilter" {
+				t.Errorf("parseSCIMFilter(%q) error = %d %s, want 400 invalidFilter", tt.filter, scimErr.Status, scimErr.ScimType)
+			}
+		})
+	}
+}
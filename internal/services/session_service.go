@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionService handles session and login history operations
+type SessionService struct {
+	sessionRepository *repository.SessionRepository
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(sessionRepository *repository.SessionRepository) *SessionService {
+	return &SessionService{
+		sessionRepository: sessionRepository,
+	}
+}
+
+// ListSessions lists the active sessions of a user, flagging the current one
+func (s *SessionService) ListSessions(userID uint, currentSessionID string) ([]models.SessionResponse, error) {
+	sessions, err := s.sessionRepository.ListActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = models.SessionResponse{
+			ID:             session.ID,
+			IPAddress:      session.IPAddress,
+			UserAgent:      session.UserAgent,
+			CreatedAt:      session.CreatedAt,
+			ExpiresAt:      session.ExpiresAt,
+			Current:        session.ID.String() == currentSessionID,
+			ImpersonatorID: session.ImpersonatorID,
+		}
+	}
+
+	return responses, nil
+}
+
+// RevokeSession revokes one of the user's own sessions
+func (s *SessionService) RevokeSession(userID uint, sessionID uuid.UUID, revokedBy string) error {
+	session, err := s.sessionRepository.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Users may only revoke their own sessions
+	if session.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+
+	if session.RevokedAt != nil {
+		return errors.New("session is already revoked")
+	}
+
+	return s.sessionRepository.Revoke(sessionID, revokedBy)
+}
+
+// ListLogins lists the login history of a user with pagination
+func (s *SessionService) ListLogins(userID uint, page, pageSize int) (*models.PaginatedResponse, error) {
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.sessionRepository.ListLogins(userID, page, pageSize)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+)
+
+// SoDService handles segregation-of-duties rules, exceptions and violation scans
+type SoDService struct {
+	sodRepository  *repository.SoDRepository
+	roleRepository *repository.RoleRepository
+	userRepository *repository.UserRepository
+}
+
+// NewSoDService creates a new segregation-of-duties service
+func NewSoDService(
+	sodRepository *repository.SoDRepository,
+	roleRepository *repository.RoleRepository,
+	userRepository *repository.UserRepository,
+) *SoDService {
+	return &SoDService{
+		sodRepository:  sodRepository,
+		roleRepository: roleRepository,
+		userRepository: userRepository,
+	}
+}
+
+// ListRules lists all rules
+func (s *SoDService) ListRules() ([]models.SoDRule, error) {
+	return s.sodRepository.ListRules()
+}
+
+// GetRule gets a rule by ID
+func (s *SoDService) GetRule(id uint) (*models.SoDRule, error) {
+	return s.sodRepository.FindRuleByID(id)
+}
+
+// CreateRule creates a rule
+func (s *SoDService) CreateRule(request *models.SoDRuleRequest, createdBy string) (*models.SoDRule, error) {
+	if err := s.validateRoles(request.RoleIDs); err != nil {
+		return nil, err
+	}
+
+	rule := &models.SoDRule{
+		Name:        request.Name,
+		Description: request.Description,
+		IsActive:    true,
+	}
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+
+	if err := s.sodRepository.CreateRule(rule, request.RoleIDs, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.sodRepository.FindRuleByID(rule.ID)
+}
+
+// UpdateRule updates a rule
+func (s *SoDService) UpdateRule(id uint, request *models.SoDRuleRequest, updatedBy string) (*models.SoDRule, error) {
+	rule, err := s.sodRepository.FindRuleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRoles(request.RoleIDs); err != nil {
+		return nil, err
+	}
+
+	rule.Name = request.Name
+	rule.Description = request.Description
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+
+	if err := s.sodRepository.UpdateRule(rule, request.RoleIDs, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return s.sodRepository.FindRuleByID(id)
+}
+
+// DeleteRule deletes a rule
+func (s *SoDService) DeleteRule(id uint) error {
+	if _, err := s.sodRepository.FindRuleByID(id); err != nil {
+		return err
+	}
+
+	return s.sodRepository.DeleteRule(id)
+}
+
+// ListExceptions lists exceptions
+func (s *SoDService) ListExceptions(userID uint, activeOnly bool) ([]models.SoDException, error) {
+	return s.sodRepository.ListExceptions(userID, activeOnly)
+}
+
+// CreateException grants a user a justified, expiring exception to a rule
+func (s *SoDService) CreateException(request *models.SoDExceptionRequest, createdBy string) (*models.SoDException, error) {
+	if _, err := s.sodRepository.FindRuleByID(request.RuleID); err != nil {
+		return nil, errors.New("rule not found")
+	}
+
+	if _, err := s.userRepository.FindByID(request.UserID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	expiresAt, err := parseTimestamp(request.ExpiresAt)
+	if err != nil {
+		return nil, errors.New("invalid expires_at format, use RFC 3339 or YYYY-MM-DD")
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	exception := &models.SoDException{
+		RuleID:        request.RuleID,
+		UserID:        request.UserID,
+		Justification: request.Justification,
+		ExpiresAt:     expiresAt,
+	}
+	if err := s.sodRepository.CreateException(exception, createdBy); err != nil {
+		return nil, err
+	}
+
+	return exception, nil
+}
+
+// DeleteException revokes an exception
+func (s *SoDService) DeleteException(id uint) error {
+	return s.sodRepository.DeleteException(id)
+}
+
+// ScanViolations lists users currently holding conflicting roles without a valid exception
+func (s *SoDService) ScanViolations() ([]models.SoDViolation, error) {
+	return s.sodRepository.ScanViolations()
+}
+
+// validateRoles checks that a rule names at least two distinct existing roles
+func (s *SoDService) validateRoles(roleIDs []uint) error {
+	distinct := make(map[uint]bool, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if _, err := s.roleRepository.FindByID(roleID); err != nil {
+			return errors.New("role not found")
+		}
+		distinct[roleID] = true
+	}
+
+	if len(distinct) < 2 {
+		return errors.New("a rule needs at least two distinct roles")
+	}
+	return nil
+}
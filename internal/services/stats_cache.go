@@ -0,0 +1,66 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"admin-dashboard/internal/models"
+)
+
+// StatsCache holds the most recently computed dashboard statistics
+type StatsCache interface {
+	// Get returns the cached statistics, or false when there are none or they expired
+	Get() (*models.Statistics, bool)
+	// Set stores freshly computed statistics
+	Set(stats *models.Statistics)
+	// Invalidate drops the cached statistics so the next read recomputes them
+	Invalidate()
+}
+
+// MemoryStatsCache is a StatsCache kept in process memory; each API instance has its own
+type MemoryStatsCache struct {
+	ttl time.Duration
+
+	mu        sync.RWMutex
+	stats     *models.Statistics
+	expiresAt time.Time
+}
+
+// NewMemoryStatsCache creates a new in-memory statistics cache. A ttl of zero or less disables caching.
+func NewMemoryStatsCache(ttl time.Duration) *MemoryStatsCache {
+	return &MemoryStatsCache{
+		ttl: ttl,
+	}
+}
+
+// Get returns the cached statistics, or false when there are none or they expired
+func (c *MemoryStatsCache) Get() (*models.Statistics, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.stats == nil || time.Now().After(c.expiresAt) {
+		return nil, false
+	}
+	return c.stats, true
+}
+
+// Set stores freshly computed statistics
+func (c *MemoryStatsCache) Set(stats *models.Statistics) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = stats
+	c.expiresAt = time.Now().Add(c.ttl)
+}
+
+// Invalidate drops the cached statistics so the next read recomputes them
+func (c *MemoryStatsCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = nil
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// userBatchMaxUsers limits how many users one batch may change
+const userBatchMaxUsers = 500
+
+// errUserBatchFailed rolls back an all-or-nothing batch in which a user failed
+var errUserBatchFailed = errors.New("user batch failed")
+
+// UserBatchService applies the same operations to many users
+type UserBatchService struct {
+	userRepository        *repository.UserRepository
+	roleRepository        *repository.RoleRepository
+	customFieldRepository *repository.CustomFieldRepository
+	outboxRepository      *repository.OutboxRepository
+	userService           *UserService
+	approvalService       *ApprovalService
+}
+
+// NewUserBatchService creates a new user batch service
+func NewUserBatchService(
+	userRepository *repository.UserRepository,
+	roleRepository *repository.RoleRepository,
+	customFieldRepository *repository.CustomFieldRepository,
+	outboxRepository *repository.OutboxRepository,
+	userService *UserService,
+	approvalService *ApprovalService,
+) *UserBatchService {
+	return &UserBatchService{
+		userRepository:        userRepository,
+		roleRepository:        roleRepository,
+		customFieldRepository: customFieldRepository,
+		outboxRepository:      outboxRepository,
+		userService:           userService,
+		approvalService:       approvalService,
+	}
+}
+
+// Run applies the operations of a batch to each target user as one update, validated like
+// PUT /users/{id}. Updates that need approval are submitted as change requests. In
+// all-or-nothing mode a single failure rolls back every user; in best-effort mode each user is
+// changed on its own. Errors are only returned for invalid batches, not for failed users.
+func (s *UserBatchService) Run(request *models.UserBatchRequest, requesterID uint, requestedBy string) (*models.UserBatchResponse, error) {
+	mode := request.Mode
+	if mode == "" {
+		mode = models.UserBatchAllOrNothing
+	}
+
+	if err := s.validateOperations(request.Operations); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := s.resolveTargets(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.UserBatchResponse{
+		Mode:    mode,
+		Total:   len(userIDs),
+		Results: make([]models.UserBatchResult, len(userIDs)),
+	}
+
+	if mode == models.UserBatchBestEffort {
+		for i, userID := range userIDs {
+			response.Results[i] = s.runItem(s.outboxRepository, userID, request.Operations, requesterID, requestedBy)
+		}
+		countUserBatchResults(response)
+		return response, nil
+	}
+
+	// Run every user in one transaction, each in its own savepoint so that all failures are reported
+	err = s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txOutboxRepository := repository.NewOutboxRepository(tx)
+		for i, userID := range userIDs {
+			response.Results[i] = s.runItem(txOutboxRepository, userID, request.Operations, requesterID, requestedBy)
+		}
+
+		countUserBatchResults(response)
+		if response.Failed > 0 {
+			return errUserBatchFailed
+		}
+		return nil
+	})
+	if errors.Is(err, errUserBatchFailed) {
+		for i := range response.Results {
+			result := &response.Results[i]
+			if result.Status != models.UserBatchItemFailed {
+				result.Status = models.UserBatchItemRolledBack
+				result.User = nil
+				result.ChangeRequest = nil
+			}
+		}
+		response.Updated = 0
+		response.Submitted = 0
+		response.Error = fmt.Sprintf("%d of %d users failed, no users were changed", response.Failed, response.Total)
+		return response, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// runItem applies the operations to one user in a transaction of the given outbox repository
+func (s *UserBatchService) runItem(outboxRepository *repository.OutboxRepository, userID uint, operations []models.UserBatchOperation, requesterID uint, requestedBy string) models.UserBatchResult {
+	result := models.UserBatchResult{UserID: userID}
+
+	err := outboxRepository.Transaction(func(tx *gorm.DB) error {
+		userService := s.userService.WithTx(tx)
+		request, err := s.newUpdateRequest(repository.NewRoleRepository(tx), userID, operations)
+		if err != nil {
+			return err
+		}
+
+		// Hold sensitive changes for approval
+		changeRequest, err := s.approvalService.WithTx(tx).Submit(userID, request, requesterID, requestedBy)
+		if err != nil {
+			return err
+		}
+		if changeRequest != nil {
+			result.Status = models.UserBatchItemSubmitted
+			result.ChangeRequest = changeRequest
+			return nil
+		}
+
+		user, err := userService.Update(userID, request, requestedBy)
+		if err != nil {
+			return err
+		}
+		result.Status = models.UserBatchItemUpdated
+		result.User = user
+		return nil
+	})
+	if err != nil {
+		result.Status = models.UserBatchItemFailed
+		result.User = nil
+		result.ChangeRequest = nil
+		result.Error = err.Error()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.Error = "user not found"
+		}
+	}
+
+	return result
+}
+
+// newUpdateRequest combines the operations into one update of the user. Role operations edit
+// the user's assigned roles, which the update keeps with their validity windows.
+func (s *UserBatchService) newUpdateRequest(roleRepository *repository.RoleRepository, userID uint, operations []models.UserBatchOperation) (*models.UpdateUserRequest, error) {
+	var request models.UpdateUserRequest
+	for _, operation := range operations {
+		switch operation.Type {
+		case models.UserBatchUpdate:
+			// Later operations override the fields of earlier ones, while cleared fields add up
+			var changes models.UpdateUserRequest
+			if err := json.Unmarshal(operation.Changes, &changes); err != nil {
+				return nil, err
+			}
+			cleared := slices.Concat(request.Clear, changes.Clear)
+			if err := json.Unmarshal(operation.Changes, &request); err != nil {
+				return nil, err
+			}
+			request.Clear = cleared
+		case models.UserBatchAddRole, models.UserBatchRemoveRole:
+			if request.RoleIDs == nil {
+				grants, err := roleRepository.ListUserRoleGrants(userID)
+				if err != nil {
+					return nil, err
+				}
+				request.RoleIDs = make([]uint, 0, len(grants)+1)
+				for _, grant := range grants {
+					request.RoleIDs = append(request.RoleIDs, grant.RoleID)
+				}
+			}
+
+			if operation.Type == models.UserBatchRemoveRole {
+				request.RoleIDs = slices.DeleteFunc(request.RoleIDs, func(roleID uint) bool {
+					return roleID == operation.RoleID
+				})
+			} else if !slices.Contains(request.RoleIDs, operation.RoleID) {
+				request.RoleIDs = append(request.RoleIDs, operation.RoleID)
+			}
+		case models.UserBatchDeactivate:
+			isActive := false
+			request.IsActive = &isActive
+		}
+	}
+
+	return &request, nil
+}
+
+// validateOperations checks the operations of a batch before any user is changed
+func (s *UserBatchService) validateOperations(operations []models.UserBatchOperation) error {
+	for i, operation := range operations {
+		switch operation.Type {
+		case models.UserBatchUpdate:
+			if len(operation.Changes) == 0 || bytes.Equal(bytes.TrimSpace(operation.Changes), []byte("null")) {
+				return fmt.Errorf("operation %d: changes are required", i+1)
+			}
+
+			var changes models.UpdateUserRequest
+			if err := json.Unmarshal(operation.Changes, &changes); err != nil {
+				return fmt.Errorf("operation %d: invalid changes: %v", i+1, err)
+			}
+			if changes.Email != "" {
+				return fmt.Errorf("operation %d: email cannot be changed in a batch", i+1)
+			}
+		case models.UserBatchAddRole, models.UserBatchRemoveRole:
+			if operation.RoleID == 0 {
+				return fmt.Errorf("operation %d: role_id is required", i+1)
+			}
+
+			// Check if the role exists and, when assigning it, is active
+			role, err := s.roleRepository.FindByID(operation.RoleID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("operation %d: role not found", i+1)
+			} else if err != nil {
+				return err
+			}
+			if operation.Type == models.UserBatchAddRole && !role.IsActive {
+				return fmt.Errorf("operation %d: role is inactive", i+1)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveTargets returns the IDs of the users a batch changes, from its user IDs or filter
+func (s *UserBatchService) resolveTargets(request *models.UserBatchRequest) ([]uint, error) {
+	var userIDs []uint
+	switch {
+	case len(request.UserIDs) > 0 && request.Filter != nil:
+		return nil, errors.New("use either user_ids or filter, not both")
+	case len(request.UserIDs) > 0:
+		// Skip repeated IDs
+		for _, userID := range request.UserIDs {
+			if !slices.Contains(userIDs, userID) {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	case request.Filter != nil:
+		filter := request.Filter
+
+		var conditions []repository.Condition
+		if filter.DivisionID != nil {
+			conditions = append(conditions, repository.Condition{Column: "u_division_id", Operator: "=", Value: *filter.DivisionID})
+		}
+		if filter.PositionID != nil {
+			conditions = append(conditions, repository.Condition{Column: "u_position_id", Operator: "=", Value: *filter.PositionID})
+		}
+		if filter.ManagerID != nil {
+			conditions = append(conditions, repository.Condition{Column: "u_manager_id", Operator: "=", Value: *filter.ManagerID})
+		}
+		if filter.IsActive != nil {
+			conditions = append(conditions, repository.Condition{Column: "u_is_active", Operator: "=", Value: *filter.IsActive})
+		}
+		if len(filter.CustomFields) > 0 {
+			definitions, err := s.customFieldRepository.List(models.CustomFieldEntityUser)
+			if err != nil {
+				return nil, err
+			}
+			customFieldConditions, err := customFieldConditions(definitions, "u_custom_fields", filter.CustomFields)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, customFieldConditions...)
+		}
+
+		// Refuse an empty filter, which would change every user
+		if len(conditions) == 0 && filter.Search == "" {
+			return nil, errors.New("filter needs at least one criterion")
+		}
+
+		var err error
+		userIDs, err = s.userRepository.FindIDs(filter.Search, conditions)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("user_ids or filter is required")
+	}
+
+	if len(userIDs) > userBatchMaxUsers {
+		return nil, fmt.Errorf("batch targets %d users, at most %d are allowed", len(userIDs), userBatchMaxUsers)
+	}
+	return userIDs, nil
+}
+
+// countUserBatchResults counts the results of a batch by status
+func countUserBatchResults(response *models.UserBatchResponse) {
+	response.Updated, response.Submitted, response.Failed = 0, 0, 0
+	for _, result := range response.Results {
+		switch result.Status {
+		case models.UserBatchItemUpdated:
+			response.Updated++
+		case models.UserBatchItemSubmitted:
+			response.Submitted++
+		case models.UserBatchItemFailed:
+			response.Failed++
+		}
+	}
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"admin-dashboard/internal/models"
@@ -12,10 +14,12 @@ import (
 
 // UserService handles user-related operations
 type UserService struct {
-	userRepository     *repository.UserRepository
-	roleRepository     *repository.RoleRepository
-	divisionRepository *repository.DivisionRepository
-	positionRepository *repository.PositionRepository
+	userRepository        *repository.UserRepository
+	roleRepository        *repository.RoleRepository
+	divisionRepository    *repository.DivisionRepository
+	positionRepository    *repository.PositionRepository
+	outboxRepository      *repository.OutboxRepository
+	customFieldRepository *repository.CustomFieldRepository
 }
 
 // NewUserService creates a new user service
@@ -24,12 +28,16 @@ func NewUserService(
 	roleRepository *repository.RoleRepository,
 	divisionRepository *repository.DivisionRepository,
 	positionRepository *repository.PositionRepository,
+	outboxRepository *repository.OutboxRepository,
+	customFieldRepository *repository.CustomFieldRepository,
 ) *UserService {
 	return &UserService{
-		userRepository:     userRepository,
-		roleRepository:     roleRepository,
-		divisionRepository: divisionRepository,
-		positionRepository: positionRepository,
+		userRepository:        userRepository,
+		roleRepository:        roleRepository,
+		divisionRepository:    divisionRepository,
+		positionRepository:    positionRepository,
+		outboxRepository:      outboxRepository,
+		customFieldRepository: customFieldRepository,
 	}
 }
 
@@ -53,41 +61,8 @@ func (s *UserService) Get(id uint) (*models.UserResponse, error) {
 		roleNames[i] = role.Name
 	}
 	
-	// Format birthdate and join date
-	var birthdateStr string
-	if user.Birthdate != nil {
-		birthdateStr = user.Birthdate.Format("2006-01-02")
-	}
-	
 	// Create user response
-	userResponse := &models.UserResponse{
-		ID:           user.ID,
-		UID:          user.UID,
-		EmployeeID:   user.EmployeeID,
-		Name:         user.Name,
-		Email:        user.Email,
-		Phone:        user.Phone,
-		Address:      user.Address,
-		Birthdate:    birthdateStr,
-		JoinDate:     user.JoinDate.Format("2006-01-02"),
-		ProfileImage: user.ProfileImage,
-		IsManager:    user.IsManager,
-		IsActive:     user.IsActive,
-		Roles:        roleNames,
-	}
-	
-	// Add related information if available
-	if user.Division != nil {
-		userResponse.Division = user.Division.Name
-	}
-	
-	if user.Position != nil {
-		userResponse.Position = user.Position.Name
-	}
-	
-	if user.Manager != nil {
-		userResponse.Manager = user.Manager.Name
-	}
+	userResponse := newUserResponse(user, roleNames)
 	
 	return userResponse, nil
 }
@@ -126,6 +101,12 @@ func (s *UserService) Create(request *models.CreateUserRequest, createdBy string
 		return nil, errors.New("invalid join date format, use YYYY-MM-DD")
 	}
 	
+	// Validate custom fields, including required ones that were left out
+	customFields, err := s.mergeCustomFields(nil, request.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Create user object
 	user := &models.User{
 		EmployeeID:   request.EmployeeID,
@@ -141,17 +122,37 @@ func (s *UserService) Create(request *models.CreateUserRequest, createdBy string
 		PositionID:   request.PositionID,
 		IsManager:    request.IsManager,
 		ManagerID:    request.ManagerID,
+		CustomFields: customFields,
 		IsActive:     true, // Default to active
 	}
 	
-	// Create user in database
-	err = s.userRepository.Create(user, request.RoleIDs, createdBy)
+	return s.Insert(user, request.RoleIDs, createdBy)
+}
+
+// Insert creates a prepared user in the database together with its outbox event. Create
+// validates a request into a user first; SCIM provisioning builds the user itself.
+func (s *UserService) Insert(user *models.User, roleIDs []uint, createdBy string) (*models.UserResponse, error) {
+	var response *models.UserResponse
+	err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.userRepository.Create(user, roleIDs, createdBy); err != nil {
+			return err
+		}
+		
+		// Get created user
+		var err error
+		response, err = txService.Get(user.ID)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.UserCreated{User: response})
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Get created user
-	return s.Get(user.ID)
+	return response, nil
 }
 
 // Update updates a user
@@ -162,6 +163,104 @@ func (s *UserService) Update(id uint, request *models.UpdateUserRequest, updated
 		return nil, err
 	}
 	
+	// Apply requested changes
+	if err := s.applyUpdate(user, request); err != nil {
+		return nil, err
+	}
+	
+	return s.Save(user, request.RoleIDs, updatedBy)
+}
+
+// Save writes a changed user to the database together with its outbox events, replacing the
+// assigned roles when roleIDs is not nil. Update validates a request into the user first; SCIM
+// provisioning changes the user itself.
+func (s *UserService) Save(user *models.User, roleIDs []uint, updatedBy string) (*models.UserResponse, error) {
+	var response *models.UserResponse
+	err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		stored, err := txService.userRepository.FindByID(user.ID)
+		if err != nil {
+			return err
+		}
+		wasActive := stored.IsActive
+		
+		rolesBefore, err := txService.roleNames(user.ID)
+		if err != nil {
+			return err
+		}
+		
+		if err := txService.userRepository.Update(user, roleIDs, updatedBy); err != nil {
+			return err
+		}
+		
+		// Get updated user
+		response, err = txService.Get(user.ID)
+		if err != nil {
+			return err
+		}
+		
+		if err := appendEvent(txService.outboxRepository, &models.UserUpdated{User: response}); err != nil {
+			return err
+		}
+		if wasActive && !user.IsActive {
+			if err := appendEvent(txService.outboxRepository, &models.UserDeactivated{User: response}); err != nil {
+				return err
+			}
+		}
+		if event := newRolesChanged(user.ID, rolesBefore, response.Roles); event != nil {
+			return appendEvent(txService.outboxRepository, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return response, nil
+}
+
+// SetAvatar records a user's uploaded avatar, or removes it when avatarKey is empty
+func (s *UserService) SetAvatar(id uint, avatarKey, avatarType, profileImage string, updatedBy string) (*models.UserResponse, error) {
+	var response *models.UserResponse
+	err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.userRepository.UpdateAvatar(id, avatarKey, avatarType, profileImage, updatedBy); err != nil {
+			return err
+		}
+		
+		// Get updated user
+		var err error
+		response, err = txService.Get(id)
+		if err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.UserUpdated{User: response})
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return response, nil
+}
+
+// ValidateUpdate checks that an update request would apply cleanly to a user without saving it
+func (s *UserService) ValidateUpdate(id uint, request *models.UpdateUserRequest) error {
+	user, err := s.userRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	
+	return s.applyUpdate(user, request)
+}
+
+// applyUpdate validates an update request and copies the provided fields onto the user
+func (s *UserService) applyUpdate(user *models.User, request *models.UpdateUserRequest) error {
+	// Reject updates made against an older version of the user
+	if request.Version != nil && *request.Version != user.Version {
+		return ErrVersionConflict
+	}
+	
 	// Update user fields if provided
 	if request.Name != "" {
 		user.Name = request.Name
@@ -169,11 +268,11 @@ func (s *UserService) Update(id uint, request *models.UpdateUserRequest, updated
 	
 	if request.Email != "" && request.Email != user.Email {
 		// Check if email already exists
-		_, err = s.userRepository.FindByEmail(request.Email)
+		_, err := s.userRepository.FindByEmail(request.Email)
 		if err == nil {
-			return nil, errors.New("email already exists")
+			return errors.New("email already exists")
 		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, err
+			return err
 		}
 		
 		user.Email = request.Email
@@ -190,7 +289,7 @@ func (s *UserService) Update(id uint, request *models.UpdateUserRequest, updated
 	if request.Birthdate != "" {
 		t, err := time.Parse("2006-01-02", request.Birthdate)
 		if err != nil {
-			return nil, errors.New("invalid birthdate format, use YYYY-MM-DD")
+			return errors.New("invalid birthdate format, use YYYY-MM-DD")
 		}
 		user.Birthdate = &t
 	}
@@ -198,7 +297,7 @@ func (s *UserService) Update(id uint, request *models.UpdateUserRequest, updated
 	if request.JoinDate != "" {
 		joinDate, err := time.Parse("2006-01-02", request.JoinDate)
 		if err != nil {
-			return nil, errors.New("invalid join date format, use YYYY-MM-DD")
+			return errors.New("invalid join date format, use YYYY-MM-DD")
 		}
 		user.JoinDate = joinDate
 	}
@@ -227,23 +326,95 @@ func (s *UserService) Update(id uint, request *models.UpdateUserRequest, updated
 		user.IsActive = *request.IsActive
 	}
 	
-	// Update user in database
-	err = s.userRepository.Update(user, request.RoleIDs, updatedBy)
+	if request.CustomFields != nil {
+		customFields, err := s.mergeCustomFields(user.CustomFields, request.CustomFields, false)
+		if err != nil {
+			return err
+		}
+		user.CustomFields = customFields
+	}
+	
+	// Reset cleared fields
+	for _, field := range request.Clear {
+		switch field {
+		case "phone":
+			user.Phone = ""
+		case "address":
+			user.Address = ""
+		case "birthdate":
+			user.Birthdate = nil
+		case "profile_image":
+			user.ProfileImage = ""
+		case "division_id":
+			user.DivisionID = nil
+		case "position_id":
+			user.PositionID = nil
+		case "manager_id":
+			user.ManagerID = nil
+		case "custom_fields":
+			// Remove every field, so required ones are still enforced
+			removals := make(map[string]interface{}, len(user.CustomFields))
+			for key := range user.CustomFields {
+				removals[key] = nil
+			}
+			customFields, err := s.mergeCustomFields(user.CustomFields, removals, false)
+			if err != nil {
+				return err
+			}
+			user.CustomFields = customFields
+		default:
+			return fmt.Errorf("%s cannot be cleared", field)
+		}
+	}
+	
+	return nil
+}
+
+// mergeCustomFields validates custom field input against the user field definitions
+func (s *UserService) mergeCustomFields(current models.CustomFields, input map[string]interface{}, create bool) (models.CustomFields, error) {
+	definitions, err := s.customFieldRepository.List(models.CustomFieldEntityUser)
 	if err != nil {
 		return nil, err
 	}
-	
-	// Get updated user
-	return s.Get(user.ID)
+	return mergeCustomFields(definitions, current, input, create)
+}
+
+// WithTx returns a copy of the service whose repositories run inside the given transaction
+func (s *UserService) WithTx(tx *gorm.DB) *UserService {
+	return NewUserService(
+		repository.NewUserRepository(tx),
+		repository.NewRoleRepository(tx),
+		repository.NewDivisionRepository(tx),
+		repository.NewPositionRepository(tx),
+		repository.NewOutboxRepository(tx),
+		repository.NewCustomFieldRepository(tx),
+	)
 }
 
 // Delete deletes a user
 func (s *UserService) Delete(id uint) error {
-	return s.userRepository.Delete(id)
+	// Get user for the event payload
+	user, err := s.userRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+	
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		if err := txService.userRepository.Delete(id); err != nil {
+			return err
+		}
+		
+		return appendEvent(txService.outboxRepository, &models.UserDeleted{
+			UserID:     user.ID,
+			EmployeeID: user.EmployeeID,
+			Email:      user.Email,
+		})
+	})
 }
 
-// List lists all users with pagination
-func (s *UserService) List(page, pageSize int, search string) (*models.PaginatedResponse, error) {
+// List lists all users with pagination, optionally filtered by custom field values
+func (s *UserService) List(page, pageSize int, search string, customFields map[string]string) (*models.PaginatedResponse, error) {
 	// Validate page and pageSize
 	if page < 1 {
 		page = 1
@@ -253,8 +424,21 @@ func (s *UserService) List(page, pageSize int, search string) (*models.Paginated
 		pageSize = 10
 	}
 	
+	// Filter by custom fields
+	var conditions []repository.Condition
+	if len(customFields) > 0 {
+		definitions, err := s.customFieldRepository.List(models.CustomFieldEntityUser)
+		if err != nil {
+			return nil, err
+		}
+		conditions, err = customFieldConditions(definitions, "u_custom_fields", customFields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
 	// Get paginated list of users
-	paginatedResponse, err := s.userRepository.List(page, pageSize, search)
+	paginatedResponse, err := s.userRepository.List(page, pageSize, search, conditions)
 	if err != nil {
 		return nil, err
 	}
@@ -264,12 +448,6 @@ func (s *UserService) List(page, pageSize int, search string) (*models.Paginated
 	userResponses := make([]models.UserResponse, len(users))
 	
 	for i, user := range users {
-		// Format birthdate and join date
-		var birthdateStr string
-		if user.Birthdate != nil {
-			birthdateStr = user.Birthdate.Format("2006-01-02")
-		}
-		
 		// Get roles
 		roleNames := make([]string, len(user.Roles))
 		for j, role := range user.Roles {
@@ -277,36 +455,9 @@ func (s *UserService) List(page, pageSize int, search string) (*models.Paginated
 		}
 		
 		// Create user response
-		userResponse := models.UserResponse{
-			ID:           user.ID,
-			UID:          user.UID,
-			EmployeeID:   user.EmployeeID,
-			Name:         user.Name,
-			Email:        user.Email,
-			Phone:        user.Phone,
-			Address:      user.Address,
-			Birthdate:    birthdateStr,
-			JoinDate:     user.JoinDate.Format("2006-01-02"),
-			ProfileImage: user.ProfileImage,
-			IsManager:    user.IsManager,
-			IsActive:     user.IsActive,
-			Roles:        roleNames,
-		}
-		
-		// Add related information if available
-		if user.Division != nil {
-			userResponse.Division = user.Division.Name
-		}
-		
-		if user.Position != nil {
-			userResponse.Position = user.Position.Name
-		}
-		
-		if user.Manager != nil {
-			userResponse.Manager = user.Manager.Name
-		}
+		userResponse := newUserResponse(&user, roleNames)
 		
-		userResponses[i] = userResponse
+		userResponses[i] = *userResponse
 	}
 	
 	// Update response items
@@ -318,4 +469,301 @@ func (s *UserService) List(page, pageSize int, search string) (*models.Paginated
 // UpdatePassword updates a user's password
 func (s *UserService) UpdatePassword(id uint, password string, updatedBy string) error {
 	return s.userRepository.UpdatePassword(id, password, updatedBy)
-}
\ No newline at end of file
+}
+
+// ListRoleGrants lists a user's role assignments with their validity windows
+func (s *UserService) ListRoleGrants(userID uint) ([]models.RoleGrantResponse, error) {
+	// Check if the user exists
+	if _, err := s.userRepository.FindByID(userID); err != nil {
+		return nil, err
+	}
+
+	grants, err := s.roleRepository.ListUserRoleGrants(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]models.RoleGrantResponse, len(grants))
+	for i, grant := range grants {
+		responses[i] = models.RoleGrantResponse{
+			RoleID:     grant.RoleID,
+			ValidFrom:  grant.ValidFrom,
+			ValidUntil: grant.ValidUntil,
+			Effective: (grant.ValidFrom == nil || !grant.ValidFrom.After(now)) &&
+				(grant.ValidUntil == nil || grant.ValidUntil.After(now)),
+			CreatedAt: grant.CreatedAt,
+			CreatedBy: grant.CreatedBy,
+		}
+		if grant.Role != nil {
+			responses[i].RoleName = grant.Role.Name
+			responses[i].Level = grant.Role.Level
+		}
+	}
+
+	return responses, nil
+}
+
+// GrantRole assigns a role to a user, optionally limited to a validity window
+func (s *UserService) GrantRole(userID uint, request *models.RoleGrantRequest, createdBy string) ([]models.RoleGrantResponse, error) {
+	// Check if the user exists
+	if _, err := s.userRepository.FindByID(userID); err != nil {
+		return nil, err
+	}
+
+	// Check if the role exists and is active
+	role, err := s.roleRepository.FindByID(request.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.IsActive {
+		return nil, errors.New("role is inactive")
+	}
+
+	grant := &models.UserRole{
+		UserID:    userID,
+		RoleID:    request.RoleID,
+		CreatedBy: createdBy,
+	}
+
+	// Parse validity window
+	if request.ValidFrom != "" {
+		validFrom, err := parseTimestamp(request.ValidFrom)
+		if err != nil {
+			return nil, errors.New("invalid valid_from format, use RFC 3339 or YYYY-MM-DD")
+		}
+		grant.ValidFrom = &validFrom
+	}
+
+	if request.ValidUntil != "" {
+		validUntil, err := parseTimestamp(request.ValidUntil)
+		if err != nil {
+			return nil, errors.New("invalid valid_until format, use RFC 3339 or YYYY-MM-DD")
+		}
+		if !validUntil.After(time.Now()) {
+			return nil, errors.New("valid_until must be in the future")
+		}
+		if grant.ValidFrom != nil && !validUntil.After(*grant.ValidFrom) {
+			return nil, errors.New("valid_until must be after valid_from")
+		}
+		grant.ValidUntil = &validUntil
+	}
+
+	err = s.changeRoles(userID, func(txService *UserService) error {
+		return txService.roleRepository.GrantUserRole(grant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListRoleGrants(userID)
+}
+
+// RevokeRole removes a role from a user
+func (s *UserService) RevokeRole(userID, roleID uint) error {
+	// Check if the user exists
+	if _, err := s.userRepository.FindByID(userID); err != nil {
+		return err
+	}
+
+	return s.changeRoles(userID, func(txService *UserService) error {
+		return txService.roleRepository.RemoveUserRole(userID, roleID)
+	})
+}
+
+// AssignRole assigns a role to a user without a validity window, if it is not already assigned
+func (s *UserService) AssignRole(userID, roleID uint, createdBy string) error {
+	// Check if the user exists
+	if _, err := s.userRepository.FindByID(userID); err != nil {
+		return err
+	}
+
+	return s.changeRoles(userID, func(txService *UserService) error {
+		return txService.roleRepository.AddUserRole(userID, roleID, createdBy)
+	})
+}
+
+// SetRoleMembers replaces all users assigned to a role
+func (s *UserService) SetRoleMembers(roleID uint, userIDs []uint, createdBy string) error {
+	members, err := s.roleRepository.GetRoleUsers(roleID)
+	if err != nil {
+		return err
+	}
+
+	// Both former and new members may change
+	affected := append([]uint{}, userIDs...)
+	for _, member := range members {
+		if !slices.Contains(affected, member.ID) {
+			affected = append(affected, member.ID)
+		}
+	}
+
+	return s.changeRolesOf(affected, func(txService *UserService) error {
+		return txService.roleRepository.SetRoleUsers(roleID, userIDs, createdBy)
+	})
+}
+
+// ExpireRoles removes a user's assignments whose validity ended and emits UserRolesChanged for
+// the roles that lapsed
+func (s *UserService) ExpireRoles(userID uint, now time.Time) ([]models.RoleGrantExpiration, error) {
+	var expirations []models.RoleGrantExpiration
+	err := s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		var err error
+		expirations, err = txService.roleRepository.ExpireUserRoles(userID, now)
+		if err != nil {
+			return err
+		}
+
+		after, err := txService.roleNames(userID)
+		if err != nil {
+			return err
+		}
+
+		// Expired roles stopped being effective before they were removed, so add the ones that
+		// were ever effective back to the roles the user held
+		before := append([]string{}, after...)
+		for _, expiration := range expirations {
+			if expiration.ValidFrom != nil && !expiration.ValidFrom.Before(expiration.ValidUntil) {
+				continue
+			}
+			role, err := txService.roleRepository.FindByID(expiration.RoleID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if !slices.Contains(before, role.Name) {
+				before = append(before, role.Name)
+			}
+		}
+
+		return txService.recordRolesChanged(userID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expirations, nil
+}
+
+// changeRoles runs a role assignment change in a transaction and emits UserRolesChanged
+// when the user's effective roles differ afterwards
+func (s *UserService) changeRoles(userID uint, change func(txService *UserService) error) error {
+	return s.changeRolesOf([]uint{userID}, change)
+}
+
+// changeRolesOf runs a role assignment change affecting several users in a transaction and
+// emits UserRolesChanged for each user whose effective roles differ afterwards
+func (s *UserService) changeRolesOf(userIDs []uint, change func(txService *UserService) error) error {
+	return s.outboxRepository.Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx)
+		before := make(map[uint][]string, len(userIDs))
+		for _, userID := range userIDs {
+			names, err := txService.roleNames(userID)
+			if err != nil {
+				return err
+			}
+			before[userID] = names
+		}
+
+		if err := change(txService); err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			after, err := txService.roleNames(userID)
+			if err != nil {
+				return err
+			}
+			if err := txService.recordRolesChanged(userID, before[userID], after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// recordRolesChanged bumps the user's version and emits UserRolesChanged when the user's
+// effective roles differ; the service must be bound to a transaction
+func (s *UserService) recordRolesChanged(userID uint, before, after []string) error {
+	event := newRolesChanged(userID, before, after)
+	if event == nil {
+		return nil
+	}
+
+	// Roles are part of the user's representation
+	if err := s.userRepository.IncrementVersion(userID); err != nil {
+		return err
+	}
+	return appendEvent(s.outboxRepository, event)
+}
+
+// roleNames returns the names of the roles a user effectively holds
+func (s *UserService) roleNames(userID uint) ([]string, error) {
+	roles, err := s.roleRepository.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	return names, nil
+}
+
+// parseTimestamp parses an RFC 3339 timestamp or a YYYY-MM-DD date
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// newUserResponse converts a user and its role names into a UserResponse
+func newUserResponse(user *models.User, roleNames []string) *models.UserResponse {
+	// Format birthdate and join date
+	var birthdateStr string
+	if user.Birthdate != nil {
+		birthdateStr = user.Birthdate.Format("2006-01-02")
+	}
+
+	userResponse := &models.UserResponse{
+		ID:            user.ID,
+		UID:           user.UID,
+		EmployeeID:    user.EmployeeID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Phone:         user.Phone,
+		Address:       user.Address,
+		Birthdate:     birthdateStr,
+		JoinDate:      user.JoinDate.Format("2006-01-02"),
+		ProfileImage:  user.ProfileImage,
+		IsManager:     user.IsManager,
+		IsActive:      user.IsActive,
+		DeactivatedAt: user.DeactivatedAt,
+		LastLoginAt:   user.LastLoginAt,
+		Roles:         roleNames,
+		CustomFields:  user.CustomFields,
+		Version:       user.Version,
+	}
+	if userResponse.CustomFields == nil {
+		userResponse.CustomFields = models.CustomFields{}
+	}
+
+	// Add related information if available
+	if user.Division != nil {
+		userResponse.Division = user.Division.Name
+	}
+
+	if user.Position != nil {
+		userResponse.Position = user.Position.Name
+	}
+
+	if user.Manager != nil {
+		userResponse.Manager = user.Manager.Name
+	}
+
+	return userResponse
+}
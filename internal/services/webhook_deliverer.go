@@ -0,0 +1,232 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"admin-dashboard/internal/config"
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+)
+
+const (
+	// webhookBatchSize is the number of deliveries claimed per run
+	webhookBatchSize = 50
+	// webhookMaxBackoff caps the delay between retries
+	webhookMaxBackoff = 6 * time.Hour
+	// webhookMaxErrorLength truncates response bodies recorded as errors
+	webhookMaxErrorLength = 512
+)
+
+// WebhookDeliverer periodically posts due webhook deliveries, retrying failures with
+// exponential backoff and dead-lettering them after the configured number of attempts
+type WebhookDeliverer struct {
+	webhookRepository *repository.WebhookRepository
+	config            *config.WebhookConfig
+	client            *http.Client
+}
+
+// NewWebhookDeliverer creates a new webhook deliverer
+func NewWebhookDeliverer(webhookRepository *repository.WebhookRepository, cfg *config.WebhookConfig) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		webhookRepository: webhookRepository,
+		config:            cfg,
+		client:            newWebhookClient(time.Duration(cfg.Timeout)*time.Second, cfg.AllowPrivateTargets),
+	}
+}
+
+// newWebhookClient creates the HTTP client deliveries are posted with. Unless allowPrivate is
+// set, it refuses to connect to loopback, private, link-local and other non-public addresses.
+// The check runs on the address actually dialled, after DNS resolution, so a host name that
+// later resolves to an internal address is refused too. Redirects are not followed; a 3xx
+// response counts as a failed delivery.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("webhook target %s is not a public address", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // Connect directly, so the address check applies to the target
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookBlockedPrefixes lists non-public ranges not covered by the netip.Addr predicates
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which can reach IPv4 internals
+}
+
+// isPublicAddr reports whether addr is a globally routable unicast address
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Run sends due deliveries every poll interval until the context is cancelled
+func (d *WebhookDeliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(d.config.PollInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Deliver(ctx); err != nil {
+			log.Printf("Webhook delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver claims due deliveries and sends each one once
+func (d *WebhookDeliverer) Deliver(ctx context.Context) error {
+	// Lease claimed deliveries for longer than a request can take, so a crash mid-send
+	// only delays the retry
+	lease := 2 * time.Duration(d.config.Timeout) * time.Second
+
+	deliveries, err := d.webhookRepository.ClaimDueDeliveries(webhookBatchSize, lease)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if err := d.send(ctx, &deliveries[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send posts one delivery and records the outcome
+func (d *WebhookDeliverer) send(ctx context.Context, delivery *models.WebhookDelivery) error {
+	attempt := &models.WebhookDeliveryAttempt{AttemptedAt: time.Now()}
+	delivery.Attempts++
+
+	if delivery.Subscription == nil || !delivery.Subscription.IsActive {
+		attempt.Error = "subscription is inactive"
+	} else {
+		attempt.StatusCode, attempt.Error = d.post(ctx, delivery)
+	}
+	attempt.DurationMs = time.Since(attempt.AttemptedAt).Milliseconds()
+
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+
+	switch {
+	case attempt.Error == "":
+		now := time.Now()
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryDead
+		log.Printf("Webhook delivery %d dead-lettered after %d attempts: %s", delivery.ID, delivery.Attempts, attempt.Error)
+	default:
+		delivery.Status = models.WebhookDeliveryRetrying
+		delivery.NextAttemptAt = time.Now().Add(d.backoff(delivery.Attempts))
+	}
+
+	return d.webhookRepository.RecordAttempt(delivery, attempt)
+}
+
+// post sends the signed request and returns the response status and an error message,
+// which is empty on a 2xx response
+func (d *WebhookDeliverer) post(ctx context.Context, delivery *models.WebhookDelivery) (int, string) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "admin-dashboard-webhooks")
+	req.Header.Set("X-Webhook-Id", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(delivery.Subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return resp.StatusCode, fmt.Sprintf("unexpected redirect %d to %q, redirects are not followed", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, ""
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxErrorLength))
+	return resp.StatusCode, fmt.Sprintf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+}
+
+// backoff returns the delay before the next attempt: the base doubled after every failure, capped
+func (d *WebhookDeliverer) backoff(attempts int) time.Duration {
+	delay := time.Duration(d.config.BackoffBase) * time.Second
+	for i := 1; i < attempts && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxBackoff {
+		delay = webhookMaxBackoff
+	}
+	return delay
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription
+// secret, as sent in the X-Webhook-Signature header
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"admin-dashboard/internal/models"
+	"admin-dashboard/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// WebhookService handles webhook subscriptions and their delivery log
+type WebhookService struct {
+	webhookRepository *repository.WebhookRepository
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepository *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepository,
+	}
+}
+
+// ListSubscriptions lists all subscriptions
+func (s *WebhookService) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	return s.webhookRepository.ListSubscriptions()
+}
+
+// GetSubscription gets a subscription by ID
+func (s *WebhookService) GetSubscription(id uint) (*models.WebhookSubscription, error) {
+	return s.webhookRepository.FindSubscriptionByID(id)
+}
+
+// CreateSubscription creates a subscription
+func (s *WebhookService) CreateSubscription(request *models.WebhookSubscriptionRequest, createdBy string) (*models.WebhookSubscription, error) {
+	if request.Secret == "" {
+		return nil, errors.New("secret is required")
+	}
+	if err := validateSubscription(request); err != nil {
+		return nil, err
+	}
+
+	subscription := &models.WebhookSubscription{
+		Name:     request.Name,
+		URL:      request.URL,
+		Secret:   request.Secret,
+		Events:   request.Events,
+		IsActive: true,
+	}
+	if request.IsActive != nil {
+		subscription.IsActive = *request.IsActive
+	}
+
+	if err := s.webhookRepository.CreateSubscription(subscription, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepository.FindSubscriptionByID(subscription.ID)
+}
+
+// UpdateSubscription updates a subscription, keeping the current secret when none is given
+func (s *WebhookService) UpdateSubscription(id uint, request *models.WebhookSubscriptionRequest, updatedBy string) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepository.FindSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSubscription(request); err != nil {
+		return nil, err
+	}
+
+	subscription.Name = request.Name
+	subscription.URL = request.URL
+	subscription.Events = request.Events
+	if request.Secret != "" {
+		subscription.Secret = request.Secret
+	}
+	if request.IsActive != nil {
+		subscription.IsActive = *request.IsActive
+	}
+
+	if err := s.webhookRepository.UpdateSubscription(subscription, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepository.FindSubscriptionByID(subscription.ID)
+}
+
+// DeleteSubscription deletes a subscription
+func (s *WebhookService) DeleteSubscription(id uint) error {
+	if _, err := s.webhookRepository.FindSubscriptionByID(id); err != nil {
+		return err
+	}
+
+	return s.webhookRepository.DeleteSubscription(id)
+}
+
+// ListDeliveries lists a subscription's deliveries with pagination
+func (s *WebhookService) ListDeliveries(subscriptionID uint, status string, page, pageSize int) (*models.PaginatedResponse, error) {
+	if _, err := s.webhookRepository.FindSubscriptionByID(subscriptionID); err != nil {
+		return nil, err
+	}
+
+	// Validate page and pageSize
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return s.webhookRepository.ListDeliveries(subscriptionID, status, page, pageSize)
+}
+
+// GetDelivery gets a delivery with its attempt log
+func (s *WebhookService) GetDelivery(id uint) (*models.WebhookDelivery, error) {
+	return s.webhookRepository.FindDeliveryByID(id)
+}
+
+// Redeliver queues a delivery to be sent again, including dead-lettered ones
+func (s *WebhookService) Redeliver(id uint) (*models.WebhookDelivery, error) {
+	if _, err := s.webhookRepository.FindDeliveryByID(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepository.Redeliver(id); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepository.FindDeliveryByID(id)
+}
+
+// HandleEvent queues a delivery of an event to every active subscription that wants it.
+// It is subscribed to the event bus, so deliveries commit together with the dispatched event.
+func (s *WebhookService) HandleEvent(tx *gorm.DB, record *models.OutboxEvent, event models.DomainEvent) error {
+	webhookRepository := repository.NewWebhookRepository(tx)
+
+	subscriptions, err := webhookRepository.ListActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	payload, err := newWebhookPayload(record)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	for i := range subscriptions {
+		if !subscriptions[i].Matches(record.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscriptions[i].ID,
+			OutboxEventID:  record.ID,
+			EventType:      record.EventType,
+			Payload:        payload,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+
+	return webhookRepository.CreateDeliveries(deliveries)
+}
+
+// newWebhookPayload builds the request body posted for an event
+func newWebhookPayload(event *models.OutboxEvent) (models.JSON, error) {
+	envelope := models.WebhookEnvelope{
+		ID:         event.ID,
+		Type:       event.EventType,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Payload,
+	}
+	return json.Marshal(envelope)
+}
+
+// validateSubscription checks the URL scheme and event filter of a subscription request
+func validateSubscription(request *models.WebhookSubscriptionRequest) error {
+	target, err := url.Parse(request.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	for _, eventType := range request.Events {
+		if eventType == "*" {
+			continue
+		}
+		known := false
+		for _, t := range models.EventTypes {
+			if t == eventType {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+
+	return nil
+}
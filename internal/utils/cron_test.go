@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	santiago, err := time.LoadLocation("America/Santiago") // clocks skip midnight
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "next minute",
+			expr: "* * * * *",
+			from: time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC),
+			want: time.Date(2026, 1, 1, 10, 1, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after from",
+			expr: "0 10 * * *",
+			from: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "shorthand",
+			expr: "@monthly",
+			from: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "list and range",
+			expr: "0 9-17/4 * * 1,3",
+			from: time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC), // Monday
+			want: time.Date(2026, 1, 5, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "list and range on the next matching day",
+			expr: "0 9-17/4 * * 1,3",
+			from: time.Date(2026, 1, 5, 17, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "step from start value",
+			expr: "10/20 * * * *",
+			from: time.Date(2026, 1, 1, 10, 31, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 1, 10, 50, 0, 0, time.UTC),
+		},
+		{
+			name: "step from start value wraps to next hour",
+			expr: "10/20 * * * *",
+			from: time.Date(2026, 1, 1, 10, 50, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 1, 11, 10, 0, 0, time.UTC),
+		},
+		{
+			name: "7 is Sunday",
+			expr: "0 0 * * 7",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), // Thursday
+			want: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "0 is Sunday",
+			expr: "0 0 * * 0",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week, day of week first",
+			expr: "0 0 15 * 1",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), // Monday
+		},
+		{
+			name: "day of month or day of week, day of month first",
+			expr: "0 0 15 * 1",
+			from: time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), // Thursday
+		},
+		{
+			name: "day of month only when day of week is *",
+			expr: "0 0 15 * *",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week only when day of month is *",
+			expr: "0 0 * * 1",
+			from: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "impossible date",
+			expr: "0 0 31 2 *",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Time{},
+		},
+		{
+			name: "impossible date in short month",
+			expr: "0 0 31 4,6,9,11 *",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Time{},
+		},
+		{
+			name: "minutes continue past the skipped DST hour",
+			expr: "30 * * * *",
+			from: time.Date(2026, 3, 8, 1, 45, 0, 0, newYork),
+			want: time.Date(2026, 3, 8, 3, 30, 0, 0, newYork),
+		},
+		{
+			name: "hour rolls over across the DST change",
+			expr: "0 4 * * *",
+			from: time.Date(2026, 3, 8, 0, 30, 0, 0, newYork),
+			want: time.Date(2026, 3, 8, 4, 0, 0, 0, newYork),
+		},
+		{
+			name: "time in the skipped DST hour is not run that day",
+			expr: "30 2 * * *",
+			from: time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			want: time.Date(2026, 3, 9, 2, 30, 0, 0, newYork),
+		},
+		{
+			name: "next day starts in the skipped DST hour",
+			expr: "0 12 * * *",
+			from: time.Date(2026, 9, 5, 13, 0, 0, 0, santiago),
+			want: time.Date(2026, 9, 6, 12, 0, 0, 0, santiago),
+		},
+		{
+			name: "repeated DST hour runs once",
+			expr: "30 1 * * *",
+			from: time.Date(2026, 11, 1, 1, 30, 0, 0, newYork), // first 1:30, EDT
+			want: time.Date(2026, 11, 2, 1, 30, 0, 0, newYork),
+		},
+		{
+			name: "every hour includes the repeated DST hour",
+			expr: "30 * * * *",
+			from: time.Date(2026, 11, 1, 1, 30, 0, 0, newYork),
+			want: time.Date(2026, 11, 1, 1, 30, 0, 0, newYork).Add(time.Hour), // 1:30 EST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,9 @@ type CustomClaims struct {
 	EmployeeID string    `json:"employee_id"`
 	Email      string    `json:"email"`
 	Roles      []string  `json:"roles"`
+	// Set only on impersonation tokens
+	ImpersonatorID         uint   `json:"impersonator_id,omitempty"`
+	ImpersonatorEmployeeID string `json:"impersonator_employee_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -34,23 +37,49 @@ func NewJWTManager(config *config.JWTConfig) *JWTManager {
 	}
 }
 
-// GenerateToken generates a new JWT token
-func (m *JWTManager) GenerateToken(userID uint, uid uuid.UUID, employeeID, email string, roles []string) (string, error) {
-	// Set expiration time
-	expirationTime := time.Now().Add(time.Duration(m.config.Expiry) * time.Hour)
+// TokenLifetime returns how long generated tokens stay valid
+func (m *JWTManager) TokenLifetime() time.Duration {
+	return time.Duration(m.config.Expiry) * time.Hour
+}
 
-	// Create claims
+// GenerateToken generates a new JWT token bound to the given session ID
+func (m *JWTManager) GenerateToken(userID uint, uid uuid.UUID, employeeID, email string, roles []string, sessionID string) (string, error) {
 	claims := &CustomClaims{
 		UserID:     userID,
 		UID:        uid,
 		EmployeeID: employeeID,
 		Email:      email,
 		Roles:      roles,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+	}
+
+	return m.signToken(claims, sessionID, m.TokenLifetime())
+}
+
+// GenerateImpersonationToken generates a short-lived token acting as the target user
+// while carrying the identity of the impersonator
+func (m *JWTManager) GenerateImpersonationToken(userID uint, uid uuid.UUID, employeeID, email string, roles []string, sessionID string, impersonatorID uint, impersonatorEmployeeID string, lifetime time.Duration) (string, error) {
+	claims := &CustomClaims{
+		UserID:                 userID,
+		UID:                    uid,
+		EmployeeID:             employeeID,
+		Email:                  email,
+		Roles:                  roles,
+		ImpersonatorID:         impersonatorID,
+		ImpersonatorEmployeeID: impersonatorEmployeeID,
+	}
+
+	return m.signToken(claims, sessionID, lifetime)
+}
+
+// signToken sets the registered claims and signs the token
+func (m *JWTManager) signToken(claims *CustomClaims, sessionID string, lifetime time.Duration) (string, error) {
+	// Set expiration time
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        sessionID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
 	}
 
 	// Create token with claims